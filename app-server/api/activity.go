@@ -1,11 +1,14 @@
 package api
 
 import (
+	"errors"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 
 	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
 	"hobbyhub-server/models"
 	"hobbyhub-server/utils"
 )
@@ -16,6 +19,7 @@ import (
 // @Accept json
 // @Produce json
 // @Param id path int true "活动ID"
+// @Param Authorization header string false "JWT Token，非公开活动需要"
 // @Success 200 {array} models.Activity
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
@@ -39,10 +43,166 @@ func GetActivitie(c *gin.Context) {
 		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
 		return
 	}
+	// 对无权查看的用户隐藏活动
+	canView, err := canViewActivity(activity, getOptionalJWTUserId(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity visibility"})
+		return
+	}
+	if !canView {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
 
 	c.JSON(http.StatusOK, activity)
 }
 
+// getOptionalJWTUserId 解析可选的JWT，未登录或token无效时返回0
+func getOptionalJWTUserId(c *gin.Context) int64 {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		return 0
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		return 0
+	}
+	return jwtUser.Id
+}
+
+// canViewActivity 根据活动可见性判断用户能否查看活动，userId 为0表示未登录
+func canViewActivity(activity *models.Activity, userId int64) (bool, error) {
+	if activity.Visibility == models.ActivityVisibilityPublic {
+		return true, nil
+	}
+	if userId == 0 {
+		return false, nil
+	}
+	isParticipant, err := isActivityParticipant(activity, userId)
+	if err != nil || isParticipant {
+		return isParticipant, err
+	}
+	switch activity.Visibility {
+	case models.ActivityVisibilityFriends:
+		return controllers.AreFriends(activity.UserId, userId)
+	case models.ActivityVisibilityInvite:
+		return controllers.HasActivityInvitation(activity.Id, userId)
+	}
+	return false, nil
+}
+
+// requireActivityViewer 校验用户可以查看活动，userId 为0表示未登录，失败时已写入响应。
+// 无权查看时与活动不存在一样返回404，不暴露隐藏活动的存在
+func requireActivityViewer(c *gin.Context, activity *models.Activity, userId int64) bool {
+	canView, err := canViewActivity(activity, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity visibility"})
+		return false
+	}
+	if !canView {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return false
+	}
+	return true
+}
+
+// isValidActivitySettings 校验活动可见性与加入方式取值
+func isValidActivitySettings(visibility, joinMode int) bool {
+	return visibility >= models.ActivityVisibilityPublic && visibility <= models.ActivityVisibilityInvite &&
+		joinMode >= models.ActivityJoinOpen && joinMode <= models.ActivityJoinApproval
+}
+
+// isActivityOrganizer 判断用户是否为活动组织者
+func isActivityOrganizer(activity *models.Activity, userId int64) bool {
+	return activity.UserId == userId
+}
+
+// canManageActivity 判断用户是否可以管理活动（组织者或联合组织者）
+func canManageActivity(activity *models.Activity, userId int64) (bool, error) {
+	if isActivityOrganizer(activity, userId) {
+		return true, nil
+	}
+	return controllers.IsActivityManager(activity.Id, userId)
+}
+
+// isActivityParticipant 判断用户是否参与活动（组织者或成员）
+func isActivityParticipant(activity *models.Activity, userId int64) (bool, error) {
+	if isActivityOrganizer(activity, userId) {
+		return true, nil
+	}
+	return controllers.IsActivityMember(activity.Id, userId)
+}
+
+// getActivityParticipantIds 获取活动所有参与者（组织者及成员）的用户Id
+func getActivityParticipantIds(activity *models.Activity) ([]int64, error) {
+	members, err := controllers.GetActivityMembersByActivityId(activity.Id)
+	if err != nil {
+		return nil, err
+	}
+	userIds := []int64{activity.UserId}
+	for _, member := range members {
+		if member.UserId != activity.UserId {
+			userIds = append(userIds, member.UserId)
+		}
+	}
+	return userIds, nil
+}
+
+// loadActivityForParticipant 解析路径中的活动并校验JWT及参与者身份，失败时已写入响应
+func loadActivityForParticipant(c *gin.Context) (*models.Activity, *models.User, bool) {
+	activityId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return nil, nil, false
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return nil, nil, false
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return nil, nil, false
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return nil, nil, false
+	}
+	isParticipant, err := isActivityParticipant(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity membership"})
+		return nil, nil, false
+	}
+	if !isParticipant {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only activity members can access this resource"})
+		return nil, nil, false
+	}
+	return dbActivity, jwtUser, true
+}
+
+// joinActivity 将用户加入活动，直接加入与接受邀请共用此逻辑
+func joinActivity(activity *models.Activity, userId int64) error {
+	member := &models.ActivityMember{
+		UserId:     userId,
+		ActivityId: activity.Id,
+		CreateTime: utils.GetCurrentTime(),
+	}
+	if err := controllers.JoinActivity(member); err != nil {
+		return err
+	}
+	// 同步加入活动群聊，失败不影响加入活动
+	if err := controllers.AddActivityChatRoomMember(activity.Id, userId, member.CreateTime); err != nil {
+		log.Printf("同步用户 %d 加入活动 %d 群聊失败: %v", userId, activity.Id, err)
+	}
+	notifyActivityJoined(activity, userId)
+	return nil
+}
+
 type simpleActivity struct {
 	Id   int64  `json:"id"`
 	Name string `json:"name"`
@@ -54,17 +214,18 @@ func (s *simpleActivity) LoadFromModelActivity(activity models.Activity) {
 }
 
 // @Summary 获取所有活动Id
-// @Description 获取所有活动的id与名称
+// @Description 获取当前用户可见的所有活动，未登录时只返回公开活动
 // @Tags 活动相关接口
 // @Accept json
 // @Produce json
+// @Param Authorization header string false "JWT Token"
 // @Success 200 {array} models.Activity
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /v1/activity [get]
 func GetAllActivitie(c *gin.Context) {
 	// 获取所有活动ID
-	activitys, err := controllers.GetAllActivities()
+	activitys, err := controllers.GetVisibleActivities(getOptionalJWTUserId(c))
 	if err != nil {
 		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activities not found"})
 		return
@@ -79,7 +240,7 @@ func GetAllActivitie(c *gin.Context) {
 }
 
 // @Summary 修改活动
-// @Description 修改指定活动
+// @Description 修改指定活动，活动组织者或联合组织者可操作，修改会记入历史，时间或地点变更时通知成员
 // @Tags 活动相关接口
 // @Accept json
 // @Produce json
@@ -119,8 +280,13 @@ func UpdateActivity(c *gin.Context) {
 		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
 		return
 	}
-	// 检查用户是否有权限修改活动
-	if dbActivity.UserId != jwtUser.Id {
+	// 检查用户是否有权限修改活动，组织者与联合组织者均可修改
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
 		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you do not have permission to update this activity"})
 		return
 	}
@@ -140,6 +306,8 @@ func UpdateActivity(c *gin.Context) {
 		IfDelete   int     `json:"ifDelete"`
 		Lat        float64 `json:"lat"`
 		Lon        float64 `json:"lon"`
+		Visibility *int    `json:"visibility"`
+		JoinMode   *int    `json:"joinMode"`
 	}
 
 	if err := c.ShouldBindJSON(&activityInput); err != nil {
@@ -154,7 +322,7 @@ func UpdateActivity(c *gin.Context) {
 	activity.Addr = activityInput.Addr
 	activity.Intro = activityInput.Intro
 	activity.HeadImg = activityInput.HeadImg
-	activity.UserId = jwtUser.Id                                            // 使用JWT用户ID
+	activity.UserId = dbActivity.UserId                                     // 保持原组织者，转让需走专门接口
 	activity.CreateTime = dbActivity.CreateTime                             // 保持原创建时间
 	activity.UpdateTime = utils.GetCurrentTime()                            // 更新为当前时间
 	activity.StartTime = utils.ParseTimeFromString(activityInput.StartTime) // 解析开始时间
@@ -163,19 +331,33 @@ func UpdateActivity(c *gin.Context) {
 	activity.Lat = activityInput.Lat
 	activity.Lon = activityInput.Lon
 
+	before := *dbActivity
 	dbActivity.UpdateActivityFields(activity)
+	// 可见性与加入方式的零值有意义，单独处理
+	if activityInput.Visibility != nil {
+		dbActivity.Visibility = *activityInput.Visibility
+	}
+	if activityInput.JoinMode != nil {
+		dbActivity.JoinMode = *activityInput.JoinMode
+	}
+	if !isValidActivitySettings(dbActivity.Visibility, dbActivity.JoinMode) {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid visibility or join mode"})
+		return
+	}
 
-	// 更新活动信息
-	if err := controllers.UpdateActivity(dbActivity); err != nil {
+	// 更新活动信息并记录修订
+	changes := models.DiffActivityFields(before, *dbActivity)
+	if err := controllers.UpdateActivityWithRevision(dbActivity, jwtUser.Id, changes); err != nil {
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update activity"})
 		return
 	}
+	notifyActivityChanges(dbActivity, jwtUser.Id, changes)
 
 	c.JSON(http.StatusOK, &simpleActivity{Id: activity.Id, Name: activity.Name})
 }
 
 // @Summary 创建活动
-// @Description 创建新的活动
+// @Description 创建新的活动，state 为 4 时创建草稿活动，开始时间可通过时间投票确定
 // @Tags 活动相关接口
 // @Accept json
 // @Produce json
@@ -212,6 +394,8 @@ func CreateActivity(c *gin.Context) {
 		IfDelete   int     `json:"ifDelete"`
 		Lat        float64 `json:"lat"`
 		Lon        float64 `json:"lon"`
+		Visibility *int    `json:"visibility"`
+		JoinMode   *int    `json:"joinMode"`
 	}
 
 	if err := c.ShouldBindJSON(&activityInput); err != nil {
@@ -230,21 +414,39 @@ func CreateActivity(c *gin.Context) {
 	activity.CreateTime = utils.GetCurrentTime()                            // 保持原创建时间
 	activity.UpdateTime = utils.GetCurrentTime()                            // 更新为当前时间
 	activity.StartTime = utils.ParseTimeFromString(activityInput.StartTime) // 解析开始时间
-	activity.State = 0
+	activity.State = models.ActivityStateNotStarted
+	// 草稿活动的开始时间通过时间投票确定
+	if activityInput.State == models.ActivityStateDraft {
+		activity.State = models.ActivityStateDraft
+	}
 	activity.IfDelete = 0
 	activity.Lat = activityInput.Lat
 	activity.Lon = activityInput.Lon
+	if activityInput.Visibility != nil {
+		activity.Visibility = *activityInput.Visibility
+	}
+	if activityInput.JoinMode != nil {
+		activity.JoinMode = *activityInput.JoinMode
+	}
+	if !isValidActivitySettings(activity.Visibility, activity.JoinMode) {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid visibility or join mode"})
+		return
+	}
 
 	// 创建活动
 	if err := controllers.AddActivity(&activity); err != nil {
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to create activity"})
 		return
 	}
+	// 创建活动群聊，失败时会在首次访问群聊时补建
+	if _, err := controllers.EnsureActivityChatRoom(&activity, nil, activity.CreateTime); err != nil {
+		log.Printf("创建活动 %d 群聊失败: %v", activity.Id, err)
+	}
 	c.JSON(http.StatusOK, activity)
 }
 
 // @Summary 删除活动
-// @Description 软删除指定活动
+// @Description 软删除指定活动，活动组织者或联合组织者可操作
 // @Tags 活动相关接口
 // @Accept json
 // @Produce json
@@ -286,14 +488,20 @@ func DeleteActivity(c *gin.Context) {
 		return
 	}
 
-	// 检查用户是否有权限删除该活动
-	if dbActivity.UserId != jwtUser.Id {
+	// 检查用户是否有权限删除该活动，组织者与联合组织者均可删除
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
 		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you do not have permission to delete this activity"})
 		return
 	}
 
 	if err = controllers.DeleteActivityById(activityId); err != nil {
 		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "cannot delete activity"})
+		return
 	}
 
 	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "activity deleted successfully"})
@@ -305,6 +513,7 @@ func DeleteActivity(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param id path integer true "活动id"
+// @Param Authorization header string false "JWT Token，非公开活动需要"
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
@@ -322,6 +531,21 @@ func GetActivityMembers(c *gin.Context) {
 		return
 	}
 
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	canView, err := canViewActivity(dbActivity, getOptionalJWTUserId(c))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity visibility"})
+		return
+	}
+	if !canView {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+
 	// 获取活动成员
 	members, err := controllers.GetActivityMembersByActivityId(activityId)
 	if err != nil {
@@ -333,7 +557,7 @@ func GetActivityMembers(c *gin.Context) {
 }
 
 // @Summary 加入活动
-// @Description 加入指定活动
+// @Description 加入指定活动，需审批的活动会提交入团申请等待组织者处理
 // @Tags 活动相关接口
 // @Accept json
 // @Produce json
@@ -368,14 +592,58 @@ func JoinActivity(c *gin.Context) {
 		return
 	}
 
-	// 创建活动成员记录
-	member := &models.ActivityMember{
-		UserId:     jwtUser.Id,
-		ActivityId: activityId,
-		CreateTime: utils.GetCurrentTime(),
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
 	}
 
-	if err := controllers.AddActivityMember(member); err != nil {
+	canView, err := canViewActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity visibility"})
+		return
+	}
+	if !canView {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+
+	isParticipant, err := isActivityParticipant(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity member"})
+		return
+	}
+	if isParticipant {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "already joined the activity"})
+		return
+	}
+
+	// 需审批的活动创建入团申请
+	if dbActivity.JoinMode == models.ActivityJoinApproval {
+		request := &models.ActivityJoinRequest{
+			ActivityId: activityId,
+			UserId:     jwtUser.Id,
+			Status:     models.JoinRequestPending,
+			CreateTime: utils.GetCurrentTime(),
+			UpdateTime: utils.GetCurrentTime(),
+		}
+		if err := controllers.AddActivityJoinRequest(request); err != nil {
+			if errors.Is(err, custom_errors.ErrJoinRequestPending) {
+				c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "join request already submitted"})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to submit join request"})
+			return
+		}
+		c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "join request submitted, waiting for approval"})
+		return
+	}
+
+	if err := joinActivity(dbActivity, jwtUser.Id); err != nil {
+		if errors.Is(err, custom_errors.ErrAlreadyActivityMember) {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "already joined the activity"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to join activity"})
 		return
 	}
@@ -419,10 +687,24 @@ func LeaveActivity(c *gin.Context) {
 		return
 	}
 
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	// 组织者需先转让活动才能退出
+	if isActivityOrganizer(dbActivity, jwtUser.Id) {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "organizer must transfer ownership before leaving"})
+		return
+	}
+
 	if err := controllers.DeleteActivityMember(activityId, jwtUser.Id); err != nil {
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to leave activity"})
 		return
 	}
+	if err := controllers.RemoveActivityChatRoomMember(activityId, jwtUser.Id); err != nil {
+		log.Printf("同步用户 %d 退出活动 %d 群聊失败: %v", jwtUser.Id, activityId, err)
+	}
 
 	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "left activity successfully"})
 }
@@ -472,11 +754,12 @@ func GetUserActivities(c *gin.Context) {
 }
 
 // @Summary 获取活动评论
-// @Description 获取指定活动的所有评论
+// @Description 获取指定活动的所有评论，只有可以查看活动的用户能获取，登录时不返回与自己存在屏蔽关系的用户的评论
 // @Tags 活动相关接口
 // @Accept json
 // @Produce json
 // @Param id path integer true "活动id"
+// @Param Authorization header string false "JWT Token"
 // @Success 200 {array} models.ActivityComment
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
@@ -494,6 +777,16 @@ func GetActivityComments(c *gin.Context) {
 		return
 	}
 
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	userId := getOptionalJWTUserId(c)
+	if !requireActivityViewer(c, dbActivity, userId) {
+		return
+	}
+
 	// 获取活动评论
 	comments, err := controllers.GetActivityCommentsByActivityId(activityId)
 	if err != nil {
@@ -501,15 +794,43 @@ func GetActivityComments(c *gin.Context) {
 		return
 	}
 
+	// 屏蔽双方互相看不到对方的评论
+	if userId != 0 {
+		comments, err = filterBlockedComments(userId, comments)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check block status"})
+			return
+		}
+	}
+
 	c.JSON(http.StatusOK, comments)
 }
 
+// filterBlockedComments 去除与用户存在屏蔽关系的人发表的评论
+func filterBlockedComments(userId int64, comments []models.ActivityComment) ([]models.ActivityComment, error) {
+	blockedIds, err := controllers.GetBlockRelatedUserIds(userId)
+	if err != nil || len(blockedIds) == 0 {
+		return comments, err
+	}
+	blocked := make(map[int64]bool)
+	for _, blockedId := range blockedIds {
+		blocked[blockedId] = true
+	}
+	visible := make([]models.ActivityComment, 0, len(comments))
+	for _, comment := range comments {
+		if !blocked[comment.UserId] {
+			visible = append(visible, comment)
+		}
+	}
+	return visible, nil
+}
+
 type activityCommentResponse struct {
 	Comment string `json:"comment"`
 }
 
 // @Summary 添加活动评论
-// @Description 添加指定活动的评论
+// @Description 添加指定活动的评论，非公开活动只有可以查看活动的用户（成员、组织者的好友或受邀用户）能评论
 // @Tags 活动相关接口
 // @Accept json
 // @Produce json
@@ -551,6 +872,15 @@ func AddActivityComment(c *gin.Context) {
 		return
 	}
 
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	if !requireActivityViewer(c, dbActivity, jwtUser.Id) {
+		return
+	}
+
 	activityComment := models.ActivityComment{
 		UserId:     jwtUser.Id,
 		ActivityId: activityId,
@@ -562,12 +892,13 @@ func AddActivityComment(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to add comment"})
 		return
 	}
+	notifyActivityComment(&activityComment)
 
 	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "comment added successfully"})
 }
 
 // @Summary 删除活动评论
-// @Description 删除指定活动的指定评论
+// @Description 删除指定评论，评论作者、活动组织者或联合组织者可操作
 // @Tags 活动相关接口
 // @Accept json
 // @Produce json
@@ -602,11 +933,28 @@ func DeleteActivityComment(c *gin.Context) {
 		return
 	}
 	// 获取评论信息
-	_, err = controllers.GetActivityCommentsByActivityIdAndUserId(commentId, jwtUser.Id)
+	comment, err := controllers.GetActivityCommentById(commentId)
 	if err != nil {
-		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "comment not found or you do not have permission to delete it"})
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "comment not found"})
 		return
 	}
+	// 评论作者本人可删除，组织者与联合组织者可删除活动下的任意评论
+	if comment.UserId != jwtUser.Id {
+		dbActivity, err := controllers.GetActivityById(comment.ActivityId)
+		if err != nil {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+			return
+		}
+		canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+			return
+		}
+		if !canManage {
+			c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you do not have permission to delete this comment"})
+			return
+		}
+	}
 	if err = controllers.DeleteActivityComment(commentId); err != nil {
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to delete comment"})
 		return
@@ -0,0 +1,357 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+type announcementResponse struct {
+	models.ActivityAnnouncement
+	Read      bool `json:"read"`      // 当前用户是否已读
+	ReadCount int  `json:"readCount"` // 已读人数
+}
+
+type announcementRequest struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+	Pinned  *int   `json:"pinned"` // 0: 不置顶, 1: 置顶
+}
+
+// loadActivityAnnouncement 在已校验的活动下解析路径中的公告，失败时已写入响应
+func loadActivityAnnouncement(c *gin.Context, activity *models.Activity) (*models.ActivityAnnouncement, bool) {
+	announcementId, err := utils.StringToInt64(c.Param("announcementId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid announcement id format"})
+		return nil, false
+	}
+	announcement, err := controllers.GetActivityAnnouncementById(announcementId)
+	if err != nil || announcement.ActivityId != activity.Id {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "announcement not found"})
+		return nil, false
+	}
+	return announcement, true
+}
+
+// requireActivityManager 校验当前用户为活动组织者或联合组织者，失败时已写入响应
+func requireActivityManager(c *gin.Context, activity *models.Activity, userId int64) bool {
+	canManage, err := canManageActivity(activity, userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return false
+	}
+	if !canManage {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can manage announcements"})
+		return false
+	}
+	return true
+}
+
+// @Summary 发布活动公告
+// @Description 活动组织者或联合组织者发布公告，公告会推送到每位成员的通知中
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param announcement body announcementRequest true "公告内容"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ActivityAnnouncement
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/announcement [put]
+func AddActivityAnnouncement(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok || !requireActivityManager(c, dbActivity, jwtUser.Id) {
+		return
+	}
+
+	var req announcementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	req.Title = strings.TrimSpace(req.Title)
+	if req.Title == "" || strings.TrimSpace(req.Content) == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "title and content are required"})
+		return
+	}
+
+	now := utils.GetCurrentTime()
+	announcement := &models.ActivityAnnouncement{
+		ActivityId: dbActivity.Id,
+		AuthorId:   jwtUser.Id,
+		Title:      req.Title,
+		Content:    req.Content,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if req.Pinned != nil && *req.Pinned == 1 {
+		announcement.Pinned = 1
+	}
+	if err := controllers.AddActivityAnnouncement(announcement); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to add announcement"})
+		return
+	}
+
+	// 推送到除发布人外所有参与者的通知中，推送失败不影响公告发布
+	participantIds, err := getActivityParticipantIds(dbActivity)
+	if err != nil {
+		log.Printf("获取活动 %d 参与者失败: %v", dbActivity.Id, err)
+	}
+	var notifications []models.Notification
+	for _, userId := range participantIds {
+		if userId == jwtUser.Id {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserId:     userId,
+			Type:       models.NotificationActivityAnnouncement,
+			Content:    fmt.Sprintf("活动「%s」发布了公告：%s", dbActivity.Name, announcement.Title),
+			ActivityId: dbActivity.Id,
+			RelatedId:  announcement.Id,
+			ActorId:    jwtUser.Id,
+			CreateTime: now,
+		})
+	}
+	notify(notifications)
+
+	c.JSON(http.StatusOK, announcement)
+}
+
+// @Summary 获取活动公告
+// @Description 分页获取活动公告，置顶公告在前，其余按时间倒序，并返回当前用户是否已读及已读人数
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]announcementResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/announcement [get]
+func GetActivityAnnouncements(c *gin.Context) {
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+
+	announcements, total, err := controllers.GetActivityAnnouncements(dbActivity.Id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get announcements"})
+		return
+	}
+	var announcementIds []int64
+	for _, announcement := range announcements {
+		announcementIds = append(announcementIds, announcement.Id)
+	}
+	reads, err := controllers.GetActivityAnnouncementReadsByIds(announcementIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get announcement reads"})
+		return
+	}
+	readCounts := make(map[int64]int)
+	readByMe := make(map[int64]bool)
+	for _, read := range reads {
+		readCounts[read.AnnouncementId]++
+		if read.UserId == jwtUser.Id {
+			readByMe[read.AnnouncementId] = true
+		}
+	}
+
+	announcementResponses := []announcementResponse{}
+	for _, announcement := range announcements {
+		announcementResponses = append(announcementResponses, announcementResponse{
+			ActivityAnnouncement: announcement,
+			Read:                 readByMe[announcement.Id],
+			ReadCount:            readCounts[announcement.Id],
+		})
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: announcementResponses})
+}
+
+// @Summary 修改活动公告
+// @Description 活动组织者或联合组织者修改公告内容或置顶状态，未传的字段保持不变
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param announcementId path integer true "公告id"
+// @Param announcement body announcementRequest true "公告内容"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ActivityAnnouncement
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/announcement/{announcementId} [post]
+func UpdateActivityAnnouncement(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok || !requireActivityManager(c, dbActivity, jwtUser.Id) {
+		return
+	}
+	announcement, ok := loadActivityAnnouncement(c, dbActivity)
+	if !ok {
+		return
+	}
+
+	var req announcementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	if title := strings.TrimSpace(req.Title); title != "" {
+		announcement.Title = title
+	}
+	if strings.TrimSpace(req.Content) != "" {
+		announcement.Content = req.Content
+	}
+	if req.Pinned != nil {
+		if *req.Pinned != 0 && *req.Pinned != 1 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "pinned must be 0 or 1"})
+			return
+		}
+		announcement.Pinned = *req.Pinned
+	}
+	announcement.UpdateTime = utils.GetCurrentTime()
+	if err := controllers.UpdateActivityAnnouncement(announcement); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update announcement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, announcement)
+}
+
+// @Summary 删除活动公告
+// @Description 活动组织者或联合组织者删除公告
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param announcementId path integer true "公告id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/announcement/{announcementId} [delete]
+func DeleteActivityAnnouncement(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok || !requireActivityManager(c, dbActivity, jwtUser.Id) {
+		return
+	}
+	announcement, ok := loadActivityAnnouncement(c, dbActivity)
+	if !ok {
+		return
+	}
+
+	if err := controllers.DeleteActivityAnnouncement(announcement.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to delete announcement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "announcement deleted successfully"})
+}
+
+// @Summary 标记公告已读
+// @Description 活动参与者标记公告已读，对应的通知也会一并标记为已读
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param announcementId path integer true "公告id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/announcement/{announcementId}/read [post]
+func MarkActivityAnnouncementRead(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+	announcement, ok := loadActivityAnnouncement(c, dbActivity)
+	if !ok {
+		return
+	}
+
+	if err := controllers.MarkActivityAnnouncementRead(announcement.Id, jwtUser.Id, utils.GetCurrentTime()); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to mark announcement read"})
+		return
+	}
+	if err := controllers.MarkNotificationsReadByRelatedId(jwtUser.Id, models.NotificationActivityAnnouncement, announcement.Id); err != nil {
+		log.Printf("标记公告 %d 通知已读失败: %v", announcement.Id, err)
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "announcement marked as read"})
+}
+
+type announcementReadStatusResponse struct {
+	Reads         []models.ActivityAnnouncementRead `json:"reads"`         // 已读记录
+	UnreadUserIds []int64                           `json:"unreadUserIds"` // 尚未阅读的参与者
+}
+
+// @Summary 获取公告阅读情况
+// @Description 活动组织者或联合组织者查看公告的已读记录及尚未阅读的参与者
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param announcementId path integer true "公告id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} announcementReadStatusResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/announcement/{announcementId}/read [get]
+func GetActivityAnnouncementReadStatus(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok || !requireActivityManager(c, dbActivity, jwtUser.Id) {
+		return
+	}
+	announcement, ok := loadActivityAnnouncement(c, dbActivity)
+	if !ok {
+		return
+	}
+
+	reads, err := controllers.GetActivityAnnouncementReads(announcement.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get announcement reads"})
+		return
+	}
+	participantIds, err := getActivityParticipantIds(dbActivity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get activity members"})
+		return
+	}
+
+	readUsers := make(map[int64]bool)
+	for _, read := range reads {
+		readUsers[read.UserId] = true
+	}
+	response := announcementReadStatusResponse{Reads: reads, UnreadUserIds: []int64{}}
+	for _, userId := range participantIds {
+		// 发布人无需阅读自己的公告
+		if userId != announcement.AuthorId && !readUsers[userId] {
+			response.UnreadUserIds = append(response.UnreadUserIds, userId)
+		}
+	}
+
+	c.JSON(http.StatusOK, response)
+}
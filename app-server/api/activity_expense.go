@@ -0,0 +1,428 @@
+package api
+
+import (
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+// 金额在接口中以 "12.34" 形式的字符串传递，避免浮点误差
+
+type expenseShareResponse struct {
+	UserId int64  `json:"userId"`
+	Amount string `json:"amount"`
+}
+
+type expenseResponse struct {
+	Id          int64                  `json:"id"`
+	ActivityId  int64                  `json:"activityId"`
+	PayerId     int64                  `json:"payerId"`
+	CreatorId   int64                  `json:"creatorId"`
+	Amount      string                 `json:"amount"`
+	Currency    string                 `json:"currency"`
+	Description string                 `json:"description"`
+	CreateTime  time.Time              `json:"createTime"`
+	Shares      []expenseShareResponse `json:"shares"`
+}
+
+type settlementResponse struct {
+	Id         int64     `json:"id"`
+	ActivityId int64     `json:"activityId"`
+	FromUserId int64     `json:"fromUserId"`
+	ToUserId   int64     `json:"toUserId"`
+	Amount     string    `json:"amount"`
+	Currency   string    `json:"currency"`
+	CreatorId  int64     `json:"creatorId"`
+	CreateTime time.Time `json:"createTime"`
+}
+
+func settlementResponseFromModel(settlement models.ActivitySettlement) settlementResponse {
+	return settlementResponse{
+		Id:         settlement.Id,
+		ActivityId: settlement.ActivityId,
+		FromUserId: settlement.FromUserId,
+		ToUserId:   settlement.ToUserId,
+		Amount:     utils.FormatMoney(settlement.Amount),
+		Currency:   settlement.Currency,
+		CreatorId:  settlement.CreatorId,
+		CreateTime: settlement.CreateTime,
+	}
+}
+
+type memberBalanceResponse struct {
+	UserId  int64  `json:"userId"`
+	Balance string `json:"balance"` // 正数为应收，负数为应付
+}
+
+type transferResponse struct {
+	FromUserId int64  `json:"fromUserId"`
+	ToUserId   int64  `json:"toUserId"`
+	Amount     string `json:"amount"`
+}
+
+type currencyBalanceResponse struct {
+	Currency  string                  `json:"currency"`
+	Balances  []memberBalanceResponse `json:"balances"`
+	Transfers []transferResponse      `json:"transfers"` // 建议的结算转账
+}
+
+// normalizeCurrency 校验并规范化三位字母币种代码
+func normalizeCurrency(currency string) (string, bool) {
+	currency = strings.ToUpper(strings.TrimSpace(currency))
+	if len(currency) != 3 {
+		return "", false
+	}
+	for _, ch := range currency {
+		if ch < 'A' || ch > 'Z' {
+			return "", false
+		}
+	}
+	return currency, true
+}
+
+type addExpenseRequest struct {
+	PayerId        int64   `json:"payerId"`                     // 付款人Id，默认为当前用户
+	Amount         string  `json:"amount" binding:"required"`   // 金额，如 "12.34"
+	Currency       string  `json:"currency" binding:"required"` // 币种，如 "CNY"
+	Description    string  `json:"description"`                 // 费用说明
+	ParticipantIds []int64 `json:"participantIds"`              // 分摊人Id，默认为全部活动参与者
+}
+
+// @Summary 记录活动费用
+// @Description 活动参与者记录一笔费用，金额在分摊人之间平均分摊，除不尽的部分按用户Id顺序每人多摊1分
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param expense body addExpenseRequest true "费用信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} expenseResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/expense [put]
+func AddActivityExpense(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+
+	var req addExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	amount, err := utils.ParseMoney(req.Amount)
+	if err != nil || amount == 0 {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "amount must be a positive number with at most two decimals"})
+		return
+	}
+	currency, ok := normalizeCurrency(req.Currency)
+	if !ok {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "currency must be a three-letter code"})
+		return
+	}
+	if req.PayerId == 0 {
+		req.PayerId = jwtUser.Id
+	}
+
+	participantIds, err := getActivityParticipantIds(dbActivity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get activity members"})
+		return
+	}
+	if !slices.Contains(participantIds, req.PayerId) {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "payer must be an activity member"})
+		return
+	}
+	shareUserIds := participantIds
+	if len(req.ParticipantIds) > 0 {
+		shareUserIds = nil
+		for _, userId := range req.ParticipantIds {
+			if !slices.Contains(participantIds, userId) {
+				c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "participants must be activity members"})
+				return
+			}
+			if !slices.Contains(shareUserIds, userId) {
+				shareUserIds = append(shareUserIds, userId)
+			}
+		}
+	}
+	sort.Slice(shareUserIds, func(i, j int) bool { return shareUserIds[i] < shareUserIds[j] })
+	if int64(len(shareUserIds)) > amount {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "amount is too small to split"})
+		return
+	}
+
+	expense := &models.ActivityExpense{
+		ActivityId:  dbActivity.Id,
+		PayerId:     req.PayerId,
+		CreatorId:   jwtUser.Id,
+		Amount:      amount,
+		Currency:    currency,
+		Description: req.Description,
+		CreateTime:  utils.GetCurrentTime(),
+	}
+	var shares []models.ActivityExpenseShare
+	for i, shareAmount := range utils.SplitAmount(amount, len(shareUserIds)) {
+		shares = append(shares, models.ActivityExpenseShare{UserId: shareUserIds[i], Amount: shareAmount})
+	}
+	if err := controllers.AddActivityExpense(expense, shares); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to add expense"})
+		return
+	}
+
+	c.JSON(http.StatusOK, buildExpenseResponses([]models.ActivityExpense{*expense}, shares)[0])
+}
+
+// buildExpenseResponses 组装费用及其分摊明细
+func buildExpenseResponses(expenses []models.ActivityExpense, shares []models.ActivityExpenseShare) []expenseResponse {
+	shareMap := make(map[int64][]expenseShareResponse)
+	for _, share := range shares {
+		shareMap[share.ExpenseId] = append(shareMap[share.ExpenseId], expenseShareResponse{
+			UserId: share.UserId,
+			Amount: utils.FormatMoney(share.Amount),
+		})
+	}
+	expenseResponses := []expenseResponse{}
+	for _, expense := range expenses {
+		expenseResponses = append(expenseResponses, expenseResponse{
+			Id:          expense.Id,
+			ActivityId:  expense.ActivityId,
+			PayerId:     expense.PayerId,
+			CreatorId:   expense.CreatorId,
+			Amount:      utils.FormatMoney(expense.Amount),
+			Currency:    expense.Currency,
+			Description: expense.Description,
+			CreateTime:  expense.CreateTime,
+			Shares:      shareMap[expense.Id],
+		})
+	}
+	return expenseResponses
+}
+
+// @Summary 获取活动费用记录
+// @Description 分页获取活动的费用历史及分摊明细，按时间倒序，仅活动参与者可查看
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]expenseResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/expense [get]
+func GetActivityExpenses(c *gin.Context) {
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+	dbActivity, _, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+
+	expenses, total, err := controllers.GetActivityExpenses(dbActivity.Id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get expenses"})
+		return
+	}
+	var expenseIds []int64
+	for _, expense := range expenses {
+		expenseIds = append(expenseIds, expense.Id)
+	}
+	shares, err := controllers.GetActivityExpenseSharesByExpenseIds(expenseIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get expense shares"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: buildExpenseResponses(expenses, shares)})
+}
+
+// @Summary 获取活动费用余额
+// @Description 按币种计算每位参与者的净余额（正数为应收，负数为应付）及最少笔数的结算转账建议
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {array} currencyBalanceResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/expense/balance [get]
+func GetActivityExpenseBalances(c *gin.Context) {
+	dbActivity, _, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+
+	balances, err := controllers.GetActivityExpenseBalances(dbActivity.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to calculate balances"})
+		return
+	}
+
+	var currencies []string
+	for currency := range balances {
+		currencies = append(currencies, currency)
+	}
+	sort.Strings(currencies)
+
+	balanceResponses := []currencyBalanceResponse{}
+	for _, currency := range currencies {
+		temp := currencyBalanceResponse{Currency: currency, Balances: []memberBalanceResponse{}, Transfers: []transferResponse{}}
+		var userIds []int64
+		for userId := range balances[currency] {
+			userIds = append(userIds, userId)
+		}
+		sort.Slice(userIds, func(i, j int) bool { return userIds[i] < userIds[j] })
+		for _, userId := range userIds {
+			temp.Balances = append(temp.Balances, memberBalanceResponse{
+				UserId:  userId,
+				Balance: utils.FormatMoney(balances[currency][userId]),
+			})
+		}
+		for _, transfer := range utils.SimplifyDebts(balances[currency]) {
+			temp.Transfers = append(temp.Transfers, transferResponse{
+				FromUserId: transfer.FromUserId,
+				ToUserId:   transfer.ToUserId,
+				Amount:     utils.FormatMoney(transfer.Amount),
+			})
+		}
+		balanceResponses = append(balanceResponses, temp)
+	}
+
+	c.JSON(http.StatusOK, balanceResponses)
+}
+
+type addSettlementRequest struct {
+	FromUserId int64  `json:"fromUserId"`                  // 付款人Id，默认为当前用户
+	ToUserId   int64  `json:"toUserId" binding:"required"` // 收款人Id
+	Amount     string `json:"amount" binding:"required"`   // 金额，如 "12.34"
+	Currency   string `json:"currency" binding:"required"` // 币种
+}
+
+// @Summary 标记结算已付
+// @Description 付款人或收款人将一笔结算转账标记为已付，金额不能超过双方当前的应付与应收
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param settlement body addSettlementRequest true "结算信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} settlementResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/settlement [put]
+func AddActivitySettlement(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+
+	var req addSettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	if req.FromUserId == 0 {
+		req.FromUserId = jwtUser.Id
+	}
+	if req.FromUserId == req.ToUserId {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "cannot settle with yourself"})
+		return
+	}
+	if jwtUser.Id != req.FromUserId && jwtUser.Id != req.ToUserId {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only the payer or the receiver can mark a settlement paid"})
+		return
+	}
+	amount, err := utils.ParseMoney(req.Amount)
+	if err != nil || amount == 0 {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "amount must be a positive number with at most two decimals"})
+		return
+	}
+	currency, ok := normalizeCurrency(req.Currency)
+	if !ok {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "currency must be a three-letter code"})
+		return
+	}
+
+	balances, err := controllers.GetActivityExpenseBalances(dbActivity.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to calculate balances"})
+		return
+	}
+	if -balances[currency][req.FromUserId] < amount || balances[currency][req.ToUserId] < amount {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "amount exceeds the outstanding balance"})
+		return
+	}
+
+	settlement := &models.ActivitySettlement{
+		ActivityId: dbActivity.Id,
+		FromUserId: req.FromUserId,
+		ToUserId:   req.ToUserId,
+		Amount:     amount,
+		Currency:   currency,
+		CreatorId:  jwtUser.Id,
+		CreateTime: utils.GetCurrentTime(),
+	}
+	if err := controllers.AddActivitySettlement(settlement); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to add settlement"})
+		return
+	}
+
+	c.JSON(http.StatusOK, settlementResponseFromModel(*settlement))
+}
+
+// @Summary 获取活动结算记录
+// @Description 分页获取活动已付结算的历史记录，按时间倒序
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]settlementResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/settlement [get]
+func GetActivitySettlements(c *gin.Context) {
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+	dbActivity, _, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+
+	settlements, total, err := controllers.GetActivitySettlements(dbActivity.Id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get settlements"})
+		return
+	}
+	settlementResponses := []settlementResponse{}
+	for _, settlement := range settlements {
+		settlementResponses = append(settlementResponses, settlementResponseFromModel(settlement))
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: settlementResponses})
+}
@@ -0,0 +1,411 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+type inviteFriendsRequest struct {
+	FriendIds []int64 `json:"friendIds"` // 被邀请的好友Id列表
+	GroupIds  []int64 `json:"groupIds"`  // 被邀请的好友分组Id列表，分组中的所有好友都会被邀请
+}
+
+type skippedInvitation struct {
+	UserId int64  `json:"userId"`
+	Reason string `json:"reason"`
+}
+
+type inviteFriendsResponse struct {
+	Invited []int64             `json:"invited"`
+	Skipped []skippedInvitation `json:"skipped"`
+}
+
+// @Summary 邀请好友参加活动
+// @Description 活动成员邀请一个或多个好友或整个好友分组参加活动，friendIds 与 groupIds 至少指定一项。
+// @Description 需审批才能加入的活动只有组织者或联合组织者可以邀请。非好友、已是成员或已有待处理邀请的用户会被跳过
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param invitation body inviteFriendsRequest true "被邀请的好友"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} inviteFriendsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/invitation [put]
+func InviteFriendsToActivity(c *gin.Context) {
+	// 获取活动ID
+	activityIdStr := c.Param("id")
+	if activityIdStr == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity id is required"})
+		return
+	}
+	activityId, err := utils.StringToInt64(activityIdStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req inviteFriendsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	if len(req.FriendIds) == 0 && len(req.GroupIds) == 0 {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "friendIds or groupIds is required"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	// 只有活动成员可以发出邀请
+	isParticipant, err := isActivityParticipant(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity member"})
+		return
+	}
+	if !isParticipant {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only activity members can invite friends"})
+		return
+	}
+	// 接受邀请即可加入活动，需审批的活动只允许组织者邀请，避免普通成员借邀请绕过审批
+	if dbActivity.JoinMode == models.ActivityJoinApproval {
+		canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+			return
+		}
+		if !canManage {
+			c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can invite friends to activities that require approval"})
+			return
+		}
+	}
+
+	// 展开好友分组，分组必须属于邀请人
+	userIds := req.FriendIds
+	if len(req.GroupIds) > 0 {
+		for _, groupId := range req.GroupIds {
+			group, err := controllers.GetFriendGroupById(groupId)
+			if err != nil || group.UserId != jwtUser.Id {
+				c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "friend group not found"})
+				return
+			}
+		}
+		groupFriendIds, err := controllers.GetFriendGroupMemberIds(jwtUser.Id, req.GroupIds)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend group members"})
+			return
+		}
+		userIds = append(append([]int64{}, req.FriendIds...), groupFriendIds...)
+	}
+
+	result, err := inviteUsersToActivity(dbActivity, jwtUser.Id, userIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to send invitations"})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// inviteUsersToActivity 逐个校验并创建活动邀请，返回成功与跳过的用户
+func inviteUsersToActivity(activity *models.Activity, inviterId int64, userIds []int64) (*inviteFriendsResponse, error) {
+	result := &inviteFriendsResponse{Invited: []int64{}, Skipped: []skippedInvitation{}}
+	seen := make(map[int64]bool)
+	for _, userId := range userIds {
+		if seen[userId] {
+			continue
+		}
+		seen[userId] = true
+
+		if userId == inviterId {
+			result.Skipped = append(result.Skipped, skippedInvitation{UserId: userId, Reason: "cannot invite yourself"})
+			continue
+		}
+		blocked, err := controllers.IsBlockedBetween(inviterId, userId)
+		if err != nil {
+			return nil, err
+		}
+		if blocked {
+			result.Skipped = append(result.Skipped, skippedInvitation{UserId: userId, Reason: "blocked"})
+			continue
+		}
+		isFriend, err := controllers.AreFriends(inviterId, userId)
+		if err != nil {
+			return nil, err
+		}
+		if !isFriend {
+			result.Skipped = append(result.Skipped, skippedInvitation{UserId: userId, Reason: "not your friend"})
+			continue
+		}
+		isParticipant, err := isActivityParticipant(activity, userId)
+		if err != nil {
+			return nil, err
+		}
+		if isParticipant {
+			result.Skipped = append(result.Skipped, skippedInvitation{UserId: userId, Reason: "already a member"})
+			continue
+		}
+		hasPending, err := controllers.HasPendingActivityInvitation(activity.Id, userId)
+		if err != nil {
+			return nil, err
+		}
+		if hasPending {
+			result.Skipped = append(result.Skipped, skippedInvitation{UserId: userId, Reason: "already invited"})
+			continue
+		}
+
+		invitation := &models.ActivityInvitation{
+			ActivityId: activity.Id,
+			InviterId:  inviterId,
+			InviteeId:  userId,
+			Status:     models.InvitationPending,
+			CreateTime: utils.GetCurrentTime(),
+			UpdateTime: utils.GetCurrentTime(),
+		}
+		if err := controllers.AddActivityInvitation(invitation); err != nil {
+			return nil, err
+		}
+		result.Invited = append(result.Invited, userId)
+		pushToUser(models.PushMessage{
+			UserId:   userId,
+			Category: models.PushCategoryInvitation,
+			Title:    "活动邀请",
+			Body:     fmt.Sprintf("%s 邀请你参加活动「%s」", userDisplayName(inviterId), activity.Name),
+			Data:     map[string]string{"invitationId": strconv.FormatInt(invitation.Id, 10), "activityId": strconv.FormatInt(activity.Id, 10)},
+		})
+	}
+	return result, nil
+}
+
+type activityInvitationResponse struct {
+	Id         int64          `json:"id"`
+	Activity   simpleActivity `json:"activity"`
+	InviterId  int64          `json:"inviterId"`
+	Status     int            `json:"status"`
+	CreateTime time.Time      `json:"createTime"`
+}
+
+// @Summary 获取收到的活动邀请
+// @Description 获取当前用户收到的所有待处理活动邀请
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {array} activityInvitationResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/invitation [get]
+func GetActivityInvitations(c *gin.Context) {
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	invitations, err := controllers.GetPendingActivityInvitationsByInviteeId(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get invitations"})
+		return
+	}
+
+	invitationResponses := []activityInvitationResponse{}
+	for _, invitation := range invitations {
+		// 跳过已删除的活动
+		dbActivity, err := controllers.GetActivityById(invitation.ActivityId)
+		if err != nil {
+			continue
+		}
+		temp := activityInvitationResponse{
+			Id:         invitation.Id,
+			InviterId:  invitation.InviterId,
+			Status:     invitation.Status,
+			CreateTime: invitation.CreateTime,
+		}
+		temp.Activity.LoadFromModelActivity(*dbActivity)
+		invitationResponses = append(invitationResponses, temp)
+	}
+
+	c.JSON(http.StatusOK, invitationResponses)
+}
+
+type respondInvitationRequest struct {
+	Status int `json:"status" binding:"required,oneof=1 2"` // 处理结果，1-接受，2-拒绝
+}
+
+// @Summary 处理活动邀请
+// @Description 接受或拒绝收到的活动邀请，接受后加入活动
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param invitationId path integer true "邀请id"
+// @Param response body respondInvitationRequest true "处理结果"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ActivityInvitation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/invitation/{invitationId} [post]
+func RespondActivityInvitation(c *gin.Context) {
+	invitationId, err := utils.StringToInt64(c.Param("invitationId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid invitation id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req respondInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	invitation, err := controllers.GetActivityInvitationById(invitationId)
+	if err != nil || invitation.InviteeId != jwtUser.Id {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "invitation not found"})
+		return
+	}
+	if invitation.Status != models.InvitationPending {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invitation is not pending"})
+		return
+	}
+
+	if req.Status == models.InvitationAccepted {
+		dbActivity, err := controllers.GetActivityById(invitation.ActivityId)
+		if err != nil {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+			return
+		}
+		// 需审批的活动只有组织者能发出邀请，接受邀请视同已获批准，不再走入团审批；已经是成员时仍视为接受邀请
+		if err := joinActivity(dbActivity, jwtUser.Id); err != nil && !errors.Is(err, custom_errors.ErrAlreadyActivityMember) {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to join activity"})
+			return
+		}
+	}
+
+	invitation.Status = req.Status
+	invitation.UpdateTime = utils.GetCurrentTime()
+	if err := controllers.UpdateActivityInvitation(invitation); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update invitation"})
+		return
+	}
+
+	c.JSON(http.StatusOK, invitation)
+}
+
+type invitationStatsResponse struct {
+	Total    int64 `json:"total"`
+	Pending  int64 `json:"pending"`
+	Accepted int64 `json:"accepted"`
+	Declined int64 `json:"declined"`
+}
+
+// @Summary 获取活动邀请统计
+// @Description 活动组织者或联合组织者查看活动邀请的发送与处理情况
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} invitationStatsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/invitation/stats [get]
+func GetActivityInvitationStats(c *gin.Context) {
+	// 获取活动ID
+	activityIdStr := c.Param("id")
+	if activityIdStr == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity id is required"})
+		return
+	}
+	activityId, err := utils.StringToInt64(activityIdStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can view invitation stats"})
+		return
+	}
+
+	counts, err := controllers.CountActivityInvitationsByStatus(activityId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get invitation stats"})
+		return
+	}
+	stats := invitationStatsResponse{
+		Pending:  counts[models.InvitationPending],
+		Accepted: counts[models.InvitationAccepted],
+		Declined: counts[models.InvitationDeclined],
+	}
+	stats.Total = stats.Pending + stats.Accepted + stats.Declined
+
+	c.JSON(http.StatusOK, stats)
+}
@@ -0,0 +1,36 @@
+package api
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInviteFriendsToActivity(t *testing.T) {
+	mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	// 需审批的活动，普通成员不能邀请好友
+	expectJWTUser(mock, 2)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity` WHERE id = ? AND if_delete = 0 ORDER BY `activity`.`id` LIMIT ?")).
+		WithArgs(int64(7), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "join_mode", "if_delete"}).
+			AddRow(7, 1, models.ActivityJoinApproval, 0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_member` WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(int64(7), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_member` WHERE activity_id = ? AND user_id = ? AND role IN (?,?)")).
+		WithArgs(int64(7), int64(2), models.ActivityRoleCoOrganizer, models.ActivityRoleOrganizer).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	c, recorder := newTestContext(t, http.MethodPut, `{"friendIds":[3]}`, 2, gin.Params{{Key: "id", Value: "7"}})
+	InviteFriendsToActivity(c)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
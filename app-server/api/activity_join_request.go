@@ -0,0 +1,164 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+// @Summary 获取入团申请
+// @Description 活动组织者或联合组织者获取活动所有待审批的入团申请
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {array} models.ActivityJoinRequest
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/request [get]
+func GetActivityJoinRequests(c *gin.Context) {
+	// 获取活动ID
+	activityIdStr := c.Param("id")
+	if activityIdStr == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity id is required"})
+		return
+	}
+	activityId, err := utils.StringToInt64(activityIdStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can view join requests"})
+		return
+	}
+
+	requests, err := controllers.GetPendingActivityJoinRequests(activityId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get join requests"})
+		return
+	}
+
+	c.JSON(http.StatusOK, requests)
+}
+
+type reviewJoinRequestRequest struct {
+	Status int `json:"status" binding:"required,oneof=1 2"` // 审批结果，1-通过，2-拒绝
+}
+
+// @Summary 审批入团申请
+// @Description 活动组织者或联合组织者通过或拒绝入团申请，通过后申请人加入活动
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param requestId path integer true "申请id"
+// @Param review body reviewJoinRequestRequest true "审批结果"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ActivityJoinRequest
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/request/{requestId} [post]
+func ReviewActivityJoinRequest(c *gin.Context) {
+	activityId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+	requestId, err := utils.StringToInt64(c.Param("requestId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid request id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req reviewJoinRequestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can review join requests"})
+		return
+	}
+
+	request, err := controllers.GetActivityJoinRequestById(requestId)
+	if err != nil || request.ActivityId != activityId {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "join request not found"})
+		return
+	}
+	if request.Status != models.JoinRequestPending {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "join request is not pending"})
+		return
+	}
+
+	if req.Status == models.JoinRequestApproved {
+		if err := joinActivity(dbActivity, request.UserId); err != nil && !errors.Is(err, custom_errors.ErrAlreadyActivityMember) {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to add activity member"})
+			return
+		}
+	}
+
+	request.Status = req.Status
+	request.UpdateTime = utils.GetCurrentTime()
+	if err := controllers.UpdateActivityJoinRequest(request); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update join request"})
+		return
+	}
+
+	c.JSON(http.StatusOK, request)
+}
@@ -0,0 +1,165 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+type updateMemberRoleRequest struct {
+	Role int `json:"role"` // 1: 设为联合组织者, 0: 降为普通成员
+}
+
+// @Summary 修改成员角色
+// @Description 活动组织者将成员设为联合组织者或降为普通成员
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param userId path integer true "成员用户id"
+// @Param role body updateMemberRoleRequest true "目标角色"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ActivityMember
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/member/{userId}/role [post]
+func UpdateActivityMemberRole(c *gin.Context) {
+	activityId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+	memberUserId, err := utils.StringToInt64(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid user id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req updateMemberRoleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	// 组织者角色只能通过转让获得
+	if req.Role != models.ActivityRoleMember && req.Role != models.ActivityRoleCoOrganizer {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "role must be 0 (member) or 1 (co-organizer)"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	if !isActivityOrganizer(dbActivity, jwtUser.Id) {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only the organizer can change member roles"})
+		return
+	}
+	if memberUserId == dbActivity.UserId {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "cannot change the organizer's role"})
+		return
+	}
+
+	if err := controllers.UpdateActivityMemberRole(activityId, memberUserId, req.Role); err != nil {
+		if errors.Is(err, custom_errors.ErrNotActivityMember) {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "member not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update member role"})
+		return
+	}
+
+	member, err := controllers.GetActivityMember(activityId, memberUserId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get member"})
+		return
+	}
+	c.JSON(http.StatusOK, member)
+}
+
+type transferOwnershipRequest struct {
+	UserId int64 `json:"userId"` // 新组织者用户id，必须是活动成员
+}
+
+// @Summary 转让活动
+// @Description 活动组织者将活动转让给另一名成员，原组织者成为联合组织者
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param owner body transferOwnershipRequest true "新组织者"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.Activity
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/owner [post]
+func TransferActivityOwnership(c *gin.Context) {
+	activityId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req transferOwnershipRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.UserId == 0 {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	if !isActivityOrganizer(dbActivity, jwtUser.Id) {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only the organizer can transfer the activity"})
+		return
+	}
+	if req.UserId == jwtUser.Id {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "you are already the organizer"})
+		return
+	}
+
+	if err := controllers.TransferActivityOwnership(dbActivity, req.UserId, utils.GetCurrentTime()); err != nil {
+		if errors.Is(err, custom_errors.ErrNotActivityMember) {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "new organizer must be a member of the activity"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to transfer activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dbActivity)
+}
@@ -0,0 +1,538 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+type activityPhotoResponse struct {
+	Id          int64     `json:"id"`
+	ActivityId  int64     `json:"activityId"`
+	FileId      int64     `json:"fileId"`
+	ThumbnailId int64     `json:"thumbnailId"`
+	Width       int       `json:"width"`
+	Height      int       `json:"height"`
+	UserId      int64     `json:"userId"`
+	Caption     string    `json:"caption"`
+	SortOrder   int       `json:"sortOrder"`
+	CreateTime  time.Time `json:"createTime"`
+}
+
+// buildActivityPhotoResponses 组装相册图片及其文件的缩略图信息
+func buildActivityPhotoResponses(photos []models.ActivityPhoto) ([]activityPhotoResponse, error) {
+	var fileIds []int64
+	for _, photo := range photos {
+		fileIds = append(fileIds, photo.FileId)
+	}
+	files, err := controllers.GetFilesByIds(fileIds)
+	if err != nil {
+		return nil, err
+	}
+	fileMap := make(map[int64]models.File)
+	var linkIds []int64
+	for _, file := range files {
+		fileMap[file.Id] = file
+		if file.LinkFileId > 0 {
+			linkIds = append(linkIds, file.LinkFileId)
+		}
+	}
+	// 关联文件的缩略图信息记录在原始文件上
+	linkFiles, err := controllers.GetFilesByIds(linkIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range linkFiles {
+		fileMap[file.Id] = file
+	}
+
+	photoResponses := []activityPhotoResponse{}
+	for _, photo := range photos {
+		temp := activityPhotoResponse{
+			Id:         photo.Id,
+			ActivityId: photo.ActivityId,
+			FileId:     photo.FileId,
+			UserId:     photo.UserId,
+			Caption:    photo.Caption,
+			SortOrder:  photo.SortOrder,
+			CreateTime: photo.CreateTime,
+		}
+		if file, ok := fileMap[photo.FileId]; ok {
+			if file.LinkFileId > 0 {
+				file = fileMap[file.LinkFileId]
+			}
+			temp.ThumbnailId = file.ThumbnailId
+			temp.Width = file.Width
+			temp.Height = file.Height
+		}
+		photoResponses = append(photoResponses, temp)
+	}
+	return photoResponses, nil
+}
+
+// @Summary 获取活动相册
+// @Description 分页获取指定活动的相册图片，按排序序号升序，只有可以查看活动的用户能获取
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string false "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]activityPhotoResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/photo [get]
+func GetActivityPhotos(c *gin.Context) {
+	// 获取活动ID
+	activityIdStr := c.Param("id")
+	if activityIdStr == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity id is required"})
+		return
+	}
+	activityId, err := utils.StringToInt64(activityIdStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	if !requireActivityViewer(c, dbActivity, getOptionalJWTUserId(c)) {
+		return
+	}
+
+	photos, total, err := controllers.GetActivityPhotosByActivityId(activityId, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get activity photos"})
+		return
+	}
+	photoResponses, err := buildActivityPhotoResponses(photos)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get photo files"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: photoResponses})
+}
+
+type activityPhotoRequest struct {
+	FileId  int64  `json:"fileId" binding:"required"` // 已上传的文件Id
+	Caption string `json:"caption"`                   // 图片说明
+}
+
+// @Summary 添加活动相册图片
+// @Description 活动成员将自己上传的文件添加到活动相册，内容相同的文件（包括重复上传的同一文件）在同一相册中只能添加一次
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param photo body activityPhotoRequest true "相册图片信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} activityPhotoResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/photo [put]
+func AddActivityPhoto(c *gin.Context) {
+	// 获取活动ID
+	activityIdStr := c.Param("id")
+	if activityIdStr == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity id is required"})
+		return
+	}
+	activityId, err := utils.StringToInt64(activityIdStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req activityPhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	if !requireActivityViewer(c, dbActivity, jwtUser.Id) {
+		return
+	}
+	// 只有活动成员可以上传图片
+	isParticipant, err := isActivityParticipant(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity member"})
+		return
+	}
+	if !isParticipant {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only activity members can add photos"})
+		return
+	}
+
+	// 只能添加自己上传的文件
+	fileInfo, err := controllers.GetFileById(req.FileId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "file not found"})
+		return
+	}
+	if fileInfo.UpLoadUserId != jwtUser.Id {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you can only add files uploaded by yourself"})
+		return
+	}
+	original, err := resolveOriginalFile(fileInfo)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "original file not found"})
+		return
+	}
+	// 缩略图生成失败不影响添加图片
+	if _, err := ensureFileThumbnail(original); err != nil {
+		log.Printf("生成文件 %d 缩略图失败: %v", original.Id, err)
+	}
+
+	photo := &models.ActivityPhoto{
+		ActivityId: activityId,
+		FileId:     fileInfo.Id,
+		UserId:     jwtUser.Id,
+		Caption:    req.Caption,
+		CreateTime: utils.GetCurrentTime(),
+	}
+	if err := controllers.AddActivityPhoto(photo, original.Id); err != nil {
+		if errors.Is(err, custom_errors.ErrPhotoAlreadyAttached) {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "file is already in the album"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to add photo"})
+		return
+	}
+
+	photoResponses, err := buildActivityPhotoResponses([]models.ActivityPhoto{*photo})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get photo files"})
+		return
+	}
+	c.JSON(http.StatusOK, photoResponses[0])
+}
+
+type updateActivityPhotoRequest struct {
+	Caption *string `json:"caption"` // 图片说明
+}
+
+// @Summary 修改活动相册图片
+// @Description 修改相册图片的说明，上传者、活动组织者或联合组织者可操作。调整顺序请使用相册排序接口
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param photoId path integer true "相册图片id"
+// @Param photo body updateActivityPhotoRequest true "相册图片信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ActivityPhoto
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/photo/{photoId} [post]
+func UpdateActivityPhoto(c *gin.Context) {
+	dbActivity, photo, jwtUser, ok := loadActivityPhotoForEdit(c)
+	if !ok {
+		return
+	}
+	// 上传者本人或组织者可以修改
+	if photo.UserId != jwtUser.Id {
+		canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+			return
+		}
+		if !canManage {
+			c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you do not have permission to update this photo"})
+			return
+		}
+	}
+
+	var req updateActivityPhotoRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	if req.Caption != nil {
+		photo.Caption = *req.Caption
+	}
+	if err := controllers.UpdateActivityPhoto(photo); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update photo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, photo)
+}
+
+// @Summary 删除活动相册图片
+// @Description 从活动相册移除图片，上传者可删除自己的图片，活动组织者或联合组织者可删除任意图片
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param photoId path integer true "相册图片id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/photo/{photoId} [delete]
+func DeleteActivityPhoto(c *gin.Context) {
+	dbActivity, photo, jwtUser, ok := loadActivityPhotoForEdit(c)
+	if !ok {
+		return
+	}
+	// 上传者本人或组织者可以删除
+	if photo.UserId != jwtUser.Id {
+		canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+			return
+		}
+		if !canManage {
+			c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you do not have permission to delete this photo"})
+			return
+		}
+	}
+
+	if err := controllers.DeleteActivityPhoto(photo.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to delete photo"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "photo deleted successfully"})
+}
+
+type sortActivityPhotosRequest struct {
+	PhotoIds []int64 `json:"photoIds" binding:"required"` // 按新顺序排列的相册图片Id
+}
+
+// @Summary 调整活动相册顺序
+// @Description 活动组织者或联合组织者按给定的图片id顺序重排相册
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param order body sortActivityPhotosRequest true "图片顺序"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/photo/order [put]
+func SortActivityPhotos(c *gin.Context) {
+	// 获取活动ID
+	activityIdStr := c.Param("id")
+	if activityIdStr == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity id is required"})
+		return
+	}
+	activityId, err := utils.StringToInt64(activityIdStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req sortActivityPhotosRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can reorder photos"})
+		return
+	}
+
+	if err := controllers.SortActivityPhotos(activityId, req.PhotoIds); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to reorder photos"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "photos reordered successfully"})
+}
+
+type activityCoverRequest struct {
+	PhotoId int64 `json:"photoId" binding:"required"` // 相册图片Id
+}
+
+// @Summary 设置活动封面
+// @Description 活动组织者或联合组织者从相册中选择一张图片作为活动封面
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param cover body activityCoverRequest true "封面图片"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.Activity
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/cover [put]
+func SetActivityCover(c *gin.Context) {
+	// 获取活动ID
+	activityIdStr := c.Param("id")
+	if activityIdStr == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity id is required"})
+		return
+	}
+	activityId, err := utils.StringToInt64(activityIdStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req activityCoverRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return
+	}
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can change the cover"})
+		return
+	}
+
+	photo, err := controllers.GetActivityPhotoById(req.PhotoId)
+	if err != nil || photo.ActivityId != activityId {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "photo not found"})
+		return
+	}
+	fileInfo, err := controllers.GetFileById(photo.FileId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "file not found"})
+		return
+	}
+	original, err := resolveOriginalFile(fileInfo)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "original file not found"})
+		return
+	}
+
+	// 封面使用磁盘文件名，可通过基础文件服务访问
+	before := *dbActivity
+	dbActivity.HeadImg = fmt.Sprintf("%d%s", original.Id, original.FileType)
+	dbActivity.UpdateTime = utils.GetCurrentTime()
+	changes := models.DiffActivityFields(before, *dbActivity)
+	if err := controllers.UpdateActivityWithRevision(dbActivity, jwtUser.Id, changes); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update activity"})
+		return
+	}
+
+	c.JSON(http.StatusOK, dbActivity)
+}
+
+// loadActivityPhotoForEdit 解析路径中的活动与相册图片并校验JWT，失败时已写入响应
+func loadActivityPhotoForEdit(c *gin.Context) (*models.Activity, *models.ActivityPhoto, *models.User, bool) {
+	activityId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid activity id format"})
+		return nil, nil, nil, false
+	}
+	photoId, err := utils.StringToInt64(c.Param("photoId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid photo id format"})
+		return nil, nil, nil, false
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return nil, nil, nil, false
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return nil, nil, nil, false
+	}
+
+	dbActivity, err := controllers.GetActivityById(activityId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+		return nil, nil, nil, false
+	}
+	photo, err := controllers.GetActivityPhotoById(photoId)
+	if err != nil || photo.ActivityId != activityId {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "photo not found"})
+		return nil, nil, nil, false
+	}
+	return dbActivity, photo, jwtUser, true
+}
@@ -0,0 +1,48 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"hobbyhub-server/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActivityPhotos(t *testing.T) {
+	mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	params := gin.Params{{Key: "id", Value: "7"}}
+
+	// 测试场景1：未登录用户看不到仅受邀可见活动的相册，与活动不存在一样返回404
+	expectActivity(mock, 7, models.ActivityVisibilityInvite)
+	c, recorder := newTestContext(t, http.MethodGet, "", 0, params)
+	GetActivityPhotos(c)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试场景2：未受邀的非成员不能查看
+	expectActivity(mock, 7, models.ActivityVisibilityInvite)
+	expectJWTUser(mock, 2)
+	expectNotInvitedNonMember(mock, 7, 2)
+	c, recorder = newTestContext(t, http.MethodGet, "", 2, params)
+	GetActivityPhotos(c)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddActivityPhoto(t *testing.T) {
+	mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	// 未受邀的非成员不能向仅受邀可见的活动添加图片
+	expectJWTUser(mock, 2)
+	expectActivity(mock, 7, models.ActivityVisibilityInvite)
+	expectNotInvitedNonMember(mock, 7, 2)
+	c, recorder := newTestContext(t, http.MethodPut, `{"fileId":3}`, 2, gin.Params{{Key: "id", Value: "7"}})
+	AddActivityPhoto(c)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
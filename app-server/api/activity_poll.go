@@ -0,0 +1,400 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+type pollOptionResponse struct {
+	Id        int64     `json:"id"`
+	StartTime time.Time `json:"startTime"`
+	Yes       int64     `json:"yes"`
+	Maybe     int64     `json:"maybe"`
+	No        int64     `json:"no"`
+	MyChoice  int       `json:"myChoice"` // 当前用户的选择，0 表示未投票
+}
+
+type pollResponse struct {
+	Id            int64                `json:"id"`
+	ActivityId    int64                `json:"activityId"`
+	CreatorId     int64                `json:"creatorId"`
+	Status        int                  `json:"status"`
+	FinalOptionId int64                `json:"finalOptionId"`
+	CreateTime    time.Time            `json:"createTime"`
+	Options       []pollOptionResponse `json:"options"`
+}
+
+// buildPollResponse 组装投票的候选时间、实时票数及当前用户的选择
+func buildPollResponse(poll *models.ActivityPoll, userId int64) (*pollResponse, error) {
+	options, err := controllers.GetActivityPollOptions(poll.Id)
+	if err != nil {
+		return nil, err
+	}
+	counts, err := controllers.CountActivityPollVotes(poll.Id)
+	if err != nil {
+		return nil, err
+	}
+	myVotes, err := controllers.GetActivityPollVotesByUserId(poll.Id, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	optionMap := make(map[int64]*pollOptionResponse)
+	response := &pollResponse{
+		Id:            poll.Id,
+		ActivityId:    poll.ActivityId,
+		CreatorId:     poll.CreatorId,
+		Status:        poll.Status,
+		FinalOptionId: poll.FinalOptionId,
+		CreateTime:    poll.CreateTime,
+		Options:       make([]pollOptionResponse, len(options)),
+	}
+	for i, option := range options {
+		response.Options[i] = pollOptionResponse{Id: option.Id, StartTime: option.StartTime}
+		optionMap[option.Id] = &response.Options[i]
+	}
+	for _, count := range counts {
+		option, ok := optionMap[count.OptionId]
+		if !ok {
+			continue
+		}
+		switch count.Choice {
+		case models.PollChoiceYes:
+			option.Yes = count.Count
+		case models.PollChoiceMaybe:
+			option.Maybe = count.Count
+		case models.PollChoiceNo:
+			option.No = count.Count
+		}
+	}
+	for _, vote := range myVotes {
+		if option, ok := optionMap[vote.OptionId]; ok {
+			option.MyChoice = vote.Choice
+		}
+	}
+	return response, nil
+}
+
+type createPollRequest struct {
+	StartTimes []string `json:"startTimes" binding:"required"` // 候选开始时间，格式 2006-01-02 15:04:05
+}
+
+// @Summary 发起活动时间投票
+// @Description 活动组织者或联合组织者为草稿状态的活动提出至少两个候选开始时间，同一活动同时只能有一个进行中的投票，确定时间后可再次发起
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param poll body createPollRequest true "候选时间"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} pollResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/poll [put]
+func CreateActivityPoll(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can create a time poll"})
+		return
+	}
+	if dbActivity.State != models.ActivityStateDraft {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "time polls are only available for draft activities"})
+		return
+	}
+
+	var req createPollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	now := utils.GetCurrentTime()
+	seen := make(map[time.Time]bool)
+	var options []models.ActivityPollOption
+	for _, startTimeStr := range req.StartTimes {
+		startTime := utils.ParseTimeFromString(startTimeStr)
+		if startTime.IsZero() {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid start time: " + startTimeStr})
+			return
+		}
+		if seen[startTime] {
+			continue
+		}
+		seen[startTime] = true
+		options = append(options, models.ActivityPollOption{StartTime: startTime})
+	}
+	if len(options) < 2 {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "at least two different start times are required"})
+		return
+	}
+
+	poll := &models.ActivityPoll{
+		ActivityId: dbActivity.Id,
+		CreatorId:  jwtUser.Id,
+		Status:     models.PollOpen,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := controllers.AddActivityPoll(poll, options); err != nil {
+		if errors.Is(err, custom_errors.ErrPollAlreadyExists) {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity already has an open time poll"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to create time poll"})
+		return
+	}
+
+	response, err := buildPollResponse(poll, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get time poll"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary 获取活动时间投票
+// @Description 获取活动最近一次时间投票的候选开始时间、实时票数及当前用户的选择，仅活动参与者可查看
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} pollResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/poll [get]
+func GetActivityPoll(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+	poll, err := controllers.GetActivityPollByActivityId(dbActivity.Id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "time poll not found"})
+		return
+	}
+
+	response, err := buildPollResponse(poll, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get time poll"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+type pollVoteItem struct {
+	OptionId int64 `json:"optionId"`
+	Choice   int   `json:"choice"` // 1: 可以, 2: 可能, 3: 不行
+}
+
+type votePollRequest struct {
+	Votes []pollVoteItem `json:"votes" binding:"required"`
+}
+
+// @Summary 参与活动时间投票
+// @Description 活动参与者对一个或多个候选时间投票，重复投票会覆盖之前的选择
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param votes body votePollRequest true "投票内容"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} pollResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/poll/vote [post]
+func VoteActivityPoll(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+	var req votePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Votes) == 0 {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	poll, err := controllers.GetActivityPollByActivityId(dbActivity.Id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "time poll not found"})
+		return
+	}
+	if poll.Status != models.PollOpen {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "time poll is already finalized"})
+		return
+	}
+	options, err := controllers.GetActivityPollOptions(poll.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get poll options"})
+		return
+	}
+	optionIds := make(map[int64]bool)
+	for _, option := range options {
+		optionIds[option.Id] = true
+	}
+
+	now := utils.GetCurrentTime()
+	voted := make(map[int64]bool)
+	var votes []models.ActivityPollVote
+	for _, item := range req.Votes {
+		if !optionIds[item.OptionId] {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid poll option"})
+			return
+		}
+		if item.Choice < models.PollChoiceYes || item.Choice > models.PollChoiceNo {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "choice must be 1 (yes), 2 (maybe) or 3 (no)"})
+			return
+		}
+		if voted[item.OptionId] {
+			continue
+		}
+		voted[item.OptionId] = true
+		votes = append(votes, models.ActivityPollVote{OptionId: item.OptionId, Choice: item.Choice, UpdateTime: now})
+	}
+	if err := controllers.SaveActivityPollVotes(poll.Id, jwtUser.Id, votes); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to save votes"})
+		return
+	}
+
+	response, err := buildPollResponse(poll, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get time poll"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+type finalizePollRequest struct {
+	OptionId int64 `json:"optionId" binding:"required"` // 最终确定的候选时间Id
+}
+
+// @Summary 确定活动时间
+// @Description 活动组织者或联合组织者选定一个候选时间，活动开始时间随之更新；开始时间变化时通知所有参与者，否则通知所有投票者
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param option body finalizePollRequest true "选定的候选时间"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} pollResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/poll/finalize [post]
+func FinalizeActivityPoll(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+	canManage, err := canManageActivity(dbActivity, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity permission"})
+		return
+	}
+	if !canManage {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only organizers can finalize the time poll"})
+		return
+	}
+	var req finalizePollRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	poll, err := controllers.GetActivityPollByActivityId(dbActivity.Id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "time poll not found"})
+		return
+	}
+	if poll.Status != models.PollOpen {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "time poll is already finalized"})
+		return
+	}
+	options, err := controllers.GetActivityPollOptions(poll.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get poll options"})
+		return
+	}
+	var selected *models.ActivityPollOption
+	for i := range options {
+		if options[i].Id == req.OptionId {
+			selected = &options[i]
+		}
+	}
+	if selected == nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid poll option"})
+		return
+	}
+
+	now := utils.GetCurrentTime()
+	after := *dbActivity
+	after.StartTime = selected.StartTime
+	after.State = models.ActivityStateNotStarted
+	changes := models.DiffActivityFields(*dbActivity, after)
+	if err := controllers.FinalizeActivityPoll(poll, selected, jwtUser.Id, changes, now); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to finalize time poll"})
+		return
+	}
+
+	// 开始时间变更时与修改活动一样通知所有参与者；时间未变时只告知投票者结果。通知失败不影响投票结果
+	startTimeChanged := slices.ContainsFunc(changes, func(change models.ActivityRevisionField) bool {
+		return change.Field == "startTime"
+	})
+	if startTimeChanged {
+		notifyActivityChanges(&after, jwtUser.Id, changes)
+	} else {
+		notifyPollVoters(dbActivity, poll, selected, jwtUser.Id, now)
+	}
+
+	response, err := buildPollResponse(poll, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get time poll"})
+		return
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// notifyPollVoters 通知除确定人外的所有投票者活动时间已确定
+func notifyPollVoters(activity *models.Activity, poll *models.ActivityPoll, selected *models.ActivityPollOption, editorId int64, now time.Time) {
+	voterIds, err := controllers.GetActivityPollVoterIds(poll.Id)
+	if err != nil {
+		log.Printf("获取投票者失败: %v", err)
+		return
+	}
+	var notifications []models.Notification
+	for _, voterId := range voterIds {
+		if voterId == editorId {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserId:     voterId,
+			Type:       models.NotificationActivityTimeFinalized,
+			Content:    fmt.Sprintf("活动「%s」的开始时间已确定为 %s", activity.Name, utils.FormatTimeToString(selected.StartTime)),
+			ActivityId: activity.Id,
+			ActorId:    editorId,
+			CreateTime: now,
+		})
+	}
+	notify(notifications)
+}
@@ -0,0 +1,126 @@
+package api
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+type activityRevisionResponse struct {
+	Id         int64                          `json:"id"`
+	Version    int                            `json:"version"`
+	EditorId   int64                          `json:"editorId"`
+	CreateTime time.Time                      `json:"createTime"`
+	Changes    []models.ActivityRevisionField `json:"changes"`
+}
+
+// @Summary 获取活动修改历史
+// @Description 分页获取活动的修订记录及每次修改的字段，按版本号倒序，仅活动参与者可查看
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]activityRevisionResponse}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/history [get]
+func GetActivityHistory(c *gin.Context) {
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+	dbActivity, _, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+
+	revisions, total, err := controllers.GetActivityRevisions(dbActivity.Id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get activity history"})
+		return
+	}
+	var revisionIds []int64
+	for _, revision := range revisions {
+		revisionIds = append(revisionIds, revision.Id)
+	}
+	fields, err := controllers.GetActivityRevisionFieldsByRevisionIds(revisionIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get activity history"})
+		return
+	}
+	fieldMap := make(map[int64][]models.ActivityRevisionField)
+	for _, field := range fields {
+		fieldMap[field.RevisionId] = append(fieldMap[field.RevisionId], field)
+	}
+
+	revisionResponses := []activityRevisionResponse{}
+	for _, revision := range revisions {
+		revisionResponses = append(revisionResponses, activityRevisionResponse{
+			Id:         revision.Id,
+			Version:    revision.Version,
+			EditorId:   revision.EditorId,
+			CreateTime: revision.CreateTime,
+			Changes:    fieldMap[revision.Id],
+		})
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: revisionResponses})
+}
+
+// notifyActivityChanges 活动开始时间、地址或坐标变更时通知除修改人外的所有参与者，通知失败只记录日志
+func notifyActivityChanges(activity *models.Activity, editorId int64, changes []models.ActivityRevisionField) {
+	var parts []string
+	coordinatesChanged := false
+	for _, change := range changes {
+		switch change.Field {
+		case "startTime":
+			parts = append(parts, fmt.Sprintf("开始时间改为 %s", change.NewValue))
+		case "addr":
+			parts = append(parts, fmt.Sprintf("地点改为 %s", change.NewValue))
+		case "lat", "lon":
+			coordinatesChanged = true
+		}
+	}
+	if coordinatesChanged {
+		parts = append(parts, fmt.Sprintf("坐标改为 (%v, %v)", activity.Lat, activity.Lon))
+	}
+	if len(parts) == 0 {
+		return
+	}
+
+	participantIds, err := getActivityParticipantIds(activity)
+	if err != nil {
+		log.Printf("获取活动 %d 参与者失败: %v", activity.Id, err)
+		return
+	}
+	content := fmt.Sprintf("活动「%s」有变更：%s", activity.Name, strings.Join(parts, "；"))
+	now := utils.GetCurrentTime()
+	var notifications []models.Notification
+	for _, userId := range participantIds {
+		if userId == editorId {
+			continue
+		}
+		notifications = append(notifications, models.Notification{
+			UserId:     userId,
+			Type:       models.NotificationActivityUpdated,
+			Content:    content,
+			ActivityId: activity.Id,
+			ActorId:    editorId,
+			CreateTime: now,
+		})
+	}
+	notify(notifications)
+}
@@ -0,0 +1,123 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+func setupMockDB(t *testing.T) (sqlmock.Sqlmock, func()) {
+	db, mock, err := sqlmock.New()
+	assert.NoError(t, err)
+	gdb, err := gorm.Open(mysql.New(mysql.Config{
+		Conn:                      db,
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{})
+	assert.NoError(t, err)
+	origin := config.DB
+	config.DB = gdb
+	return mock, func() {
+		config.DB = origin
+		db.Close()
+	}
+}
+
+// newTestContext 构造调用接口用的请求上下文，userId 为0时不携带JWT
+func newTestContext(t *testing.T, method, body string, userId int64, params gin.Params) (*gin.Context, *httptest.ResponseRecorder) {
+	gin.SetMode(gin.TestMode)
+	recorder := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(recorder)
+	c.Request = httptest.NewRequest(method, "/", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	if userId != 0 {
+		token, err := utils.GenerateJWT(&models.User{Id: userId})
+		assert.NoError(t, err)
+		c.Request.Header.Set("Authorization", token)
+	}
+	c.Params = params
+	return c, recorder
+}
+
+// expectJWTUser 期望解析JWT时查询用户
+func expectJWTUser(mock sqlmock.Sqlmock, userId int64) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user` WHERE id = ? ORDER BY `user`.`id` LIMIT ?")).
+		WithArgs(userId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(userId, "user"))
+}
+
+// expectActivity 期望查询活动，活动由用户1组织
+func expectActivity(mock sqlmock.Sqlmock, activityId int64, visibility int) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity` WHERE id = ? AND if_delete = 0 ORDER BY `activity`.`id` LIMIT ?")).
+		WithArgs(activityId, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "visibility", "if_delete"}).AddRow(activityId, 1, visibility, 0))
+}
+
+// expectNotInvitedNonMember 期望检查仅受邀可见活动时，用户既不是成员也没有邀请
+func expectNotInvitedNonMember(mock sqlmock.Sqlmock, activityId, userId int64) {
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_member` WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(activityId, userId).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_invitation` WHERE activity_id = ? AND invitee_id = ? AND status IN (?,?)")).
+		WithArgs(activityId, userId, models.InvitationPending, models.InvitationAccepted).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+}
+
+func TestGetActivityComments(t *testing.T) {
+	mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	params := gin.Params{{Key: "id", Value: "7"}}
+
+	// 测试场景1：未登录用户看不到仅受邀可见活动的评论，与活动不存在一样返回404
+	expectActivity(mock, 7, models.ActivityVisibilityInvite)
+	c, recorder := newTestContext(t, http.MethodGet, "", 0, params)
+	GetActivityComments(c)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试场景2：未受邀的非成员不能查看
+	expectActivity(mock, 7, models.ActivityVisibilityInvite)
+	expectJWTUser(mock, 2)
+	expectNotInvitedNonMember(mock, 7, 2)
+	c, recorder = newTestContext(t, http.MethodGet, "", 2, params)
+	GetActivityComments(c)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试场景3：公开活动的评论所有人可见
+	expectActivity(mock, 7, models.ActivityVisibilityPublic)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_comment` WHERE activity_id = ? ORDER BY create_time DESC")).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "user_id", "content"}).AddRow(1, 7, 3, "不错"))
+	c, recorder = newTestContext(t, http.MethodGet, "", 0, params)
+	GetActivityComments(c)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "不错")
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestAddActivityComment(t *testing.T) {
+	mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	// 未受邀的非成员不能评论仅受邀可见的活动
+	expectJWTUser(mock, 2)
+	expectActivity(mock, 7, models.ActivityVisibilityInvite)
+	expectNotInvitedNonMember(mock, 7, 2)
+	c, recorder := newTestContext(t, http.MethodPut, `{"comment":"我也想去"}`, 2, gin.Params{{Key: "id", Value: "7"}})
+	AddActivityComment(c)
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
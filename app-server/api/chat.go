@@ -1,8 +1,9 @@
 package api
 
 import (
+	"log"
 	"net/http"
-	"sort"
+	"strconv"
 	"time"
 
 	"hobbyhub-server/controllers"
@@ -12,12 +13,18 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// defaultChatHistoryLimit 聊天记录每次默认返回的条数
+const defaultChatHistoryLimit = 20
+
 // @Summary 获取聊天记录
-// @Description 根据两个用户的id获取聊天记录列表
+// @Description 按消息Id倒序获取与指定用户的聊天记录，不包含自己已删除的消息。
+// @Description 使用 before_id 翻页：下一页传入本页最小的消息Id，返回条数少于 limit 时表示没有更早的记录。
 // @Tags 聊天相关接口
 // @Accept json
 // @Produce json
 // @Param to_user_id query int true "对方用户ID"
+// @Param before_id query int false "只返回Id小于该值的消息，不传则从最新消息开始"
+// @Param limit query int false "返回条数，默认为20，最大为100"
 // @Param starttime query string false "开始时间，格式为YYYY-MM-DD HH:MM:SS"
 // @Param endtime query string false "结束时间，格式为YYYY-MM-DD HH:MM:SS"
 // @Param Authorization header string true "JWT Token"
@@ -59,82 +66,66 @@ func GetChatHistory(c *gin.Context) {
 		return
 	}
 
-	// 获取从fromUserId到toUserId的聊天记录
-	chatsForward, err := controllers.GetAllChatByFromUserIdToUserId(fromUserId, toUserId)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get chat history"})
-		return
-	}
-	// 过滤已删除记录
-	var filteredChatsForward []models.Chat
-	for _, chat := range chatsForward {
-		if chat.StatusFrom != 0 {
-			filteredChatsForward = append(filteredChatsForward, chat)
+	var beforeId int64
+	if beforeIdStr := c.Query("before_id"); beforeIdStr != "" {
+		beforeId, err = utils.StringToInt64(beforeIdStr)
+		if err != nil || beforeId < 1 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid before_id"})
+			return
 		}
 	}
-	chatsForward = filteredChatsForward
-	// 获取从toUserId到fromUserId的聊天记录
-	chatsBackward, err := controllers.GetAllChatByFromUserIdToUserId(toUserId, fromUserId)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get chat history"})
-		return
-	}
-	// 过滤已删除记录
-	var filteredChatsBackward []models.Chat
-	for _, chat := range chatsBackward {
-		if chat.StatusTo != 0 {
-			filteredChatsBackward = append(filteredChatsBackward, chat)
+	limit := defaultChatHistoryLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = utils.StringToInt(limitStr)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid limit"})
+			return
+		}
+		if limit > utils.MaxPageSize {
+			limit = utils.MaxPageSize
 		}
-	}
-	chatsBackward = filteredChatsBackward
-	// 合并两个方向的聊天记录
-	allChats := append(chatsForward, chatsBackward...)
-
-	// 按创建时间降序排序
-	if len(allChats) > 0 {
-		sort.Slice(allChats, func(i, j int) bool {
-			return allChats[i].CreateTime.After(allChats[j].CreateTime)
-		})
 	}
 
 	// 处理时间过滤
-	if startTime != "" || endTime != "" {
-		var startTimeObj, endTimeObj time.Time
-
-		if startTime != "" {
-			startTimeObj = utils.ParseTimeFromString(startTime)
+	var startTimeObj, endTimeObj time.Time
+	if startTime != "" {
+		if startTimeObj = utils.ParseTimeFromString(startTime); startTimeObj.IsZero() {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid starttime"})
+			return
 		}
-
-		if endTime != "" {
-			endTimeObj = utils.ParseTimeFromString(endTime)
+	}
+	if endTime != "" {
+		if endTimeObj = utils.ParseTimeFromString(endTime); endTimeObj.IsZero() {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid endtime"})
+			return
 		}
+	}
 
-		var filteredChats []models.Chat
-		for _, chat := range allChats {
-			if startTime != "" && chat.CreateTime.Before(startTimeObj) {
-				continue
-			}
-
-			if endTime != "" && chat.CreateTime.After(endTimeObj) {
-				continue
-			}
+	chats, err := controllers.GetChatHistory(fromUserId, toUserId, beforeId, startTimeObj, endTimeObj, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get chat history"})
+		return
+	}
 
-			filteredChats = append(filteredChats, chat)
-		}
+	// 对方发来的消息已被拉取，视为送达
+	markChatsDelivered(fromUserId, chats)
+	attachChatPreviews(chats)
 
-		c.JSON(http.StatusOK, filteredChats)
-	} else {
-		c.JSON(http.StatusOK, allChats)
-	}
+	c.JSON(http.StatusOK, chats)
 }
 
 type newChatRequest struct {
-	UserIdTo int64  `json:"user_id_to" binding:"required"` // 接收者用户Id
-	Content  string `json:"content" binding:"required"`    // 聊天内容
+	UserIdTo   int64    `json:"user_id_to" binding:"required"` // 接收者用户Id
+	MsgType    string   `json:"msg_type"`                      // 消息类型：text（默认）、image、file、activity、location
+	Content    string   `json:"content"`                       // 聊天内容，文本消息必填，其他类型可作为附言
+	FileId     int64    `json:"file_id"`                       // 图片或文件消息的文件Id，须为自己上传或会话中收到的文件
+	ActivityId int64    `json:"activity_id"`                   // 活动卡片消息的活动Id
+	Lat        *float64 `json:"lat"`                           // 位置消息的纬度
+	Lon        *float64 `json:"lon"`                           // 位置消息的经度
 }
 
 // @Summary 发送聊天消息
-// @Description 发送聊天消息
+// @Description 发送聊天消息，支持文本、图片、文件、活动卡片及位置消息，图片和文件消息返回附件预览信息
 // @Tags 聊天相关接口
 // @Accept json
 // @Produce json
@@ -164,18 +155,11 @@ func SendChat(c *gin.Context) {
 		return
 	}
 	// 验证接收者用户Id是否是发送者用户的好友
-	friends, err := controllers.GetAllFriendsByUserId(jwtUser.Id)
+	isFriend, err := controllers.AreFriends(jwtUser.Id, req.UserIdTo)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friends list"})
 		return
 	}
-	isFriend := false
-	for _, friend := range friends {
-		if friend.FriendId == req.UserIdTo && friend.Status == 1 { // 1表示好友关系已建立
-			isFriend = true
-			break
-		}
-	}
 	if !isFriend {
 		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you can only send messages to your friends"})
 		return
@@ -189,11 +173,30 @@ func SendChat(c *gin.Context) {
 		StatusFrom: 1,
 		StatusTo:   1,
 	}
+	if !prepareChatMessage(c, chat, &req) {
+		return
+	}
+	// 接收者有设备在线时消息会被实时推送，直接视为送达
+	if utils.ChatHub.DeviceCount(req.UserIdTo) > 0 {
+		chat.DeliverTime = &chat.CreateTime
+	}
 
 	if err := controllers.AddChat(chat); err != nil {
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to create chat message"})
 		return
 	}
+	attachChatPreview(chat)
+	// 推送给接收者及发送者的其他设备
+	event := models.WSEvent{Type: models.WSEventChatMessage, Data: chat}
+	utils.ChatHub.Publish(chat.UserIdTo, event)
+	utils.ChatHub.Publish(chat.UserIdFrom, event)
+	pushToUser(models.PushMessage{
+		UserId:   chat.UserIdTo,
+		Category: models.PushCategoryChat,
+		Title:    userDisplayName(chat.UserIdFrom),
+		Body:     chatPushBody(chat),
+		Data:     map[string]string{"chatId": strconv.FormatInt(chat.Id, 10), "userId": strconv.FormatInt(chat.UserIdFrom, 10)},
+	})
 
 	c.JSON(http.StatusOK, *chat)
 }
@@ -232,5 +235,175 @@ func DeleteChat(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to delete chat message"})
 		return
 	}
+	// 删除只对自己生效，同步到自己的其他设备
+	utils.ChatHub.Publish(jwtUser.Id, models.WSEvent{Type: models.WSEventChatDeleted, Data: models.ChatDeletedEvent{Id: chatId}})
 	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "chat message deleted successfully"})
 }
+
+// markChatsDelivered 将 userId 收到的未送达消息标记为已送达，并向各发送者推送送达回执
+func markChatsDelivered(userId int64, chats []models.Chat) {
+	now := utils.GetCurrentTime()
+	lastIds := make(map[int64]int64)
+	for i := range chats {
+		if chats[i].UserIdTo != userId || chats[i].DeliverTime != nil {
+			continue
+		}
+		chats[i].DeliverTime = &now
+		if chats[i].Id > lastIds[chats[i].UserIdFrom] {
+			lastIds[chats[i].UserIdFrom] = chats[i].Id
+		}
+	}
+	for fromUserId, lastId := range lastIds {
+		if _, err := controllers.MarkChatsDelivered(userId, fromUserId, lastId, now); err != nil {
+			log.Printf("标记用户 %d 的消息送达失败: %v", userId, err)
+			continue
+		}
+		utils.ChatHub.Publish(fromUserId, models.WSEvent{
+			Type: models.WSEventChatDelivered,
+			Data: models.ChatReceiptEvent{UserId: userId, LastId: lastId, Time: now},
+		})
+	}
+}
+
+type markChatReadRequest struct {
+	UserId int64 `json:"user_id" binding:"required"` // 会话对方用户Id
+	LastId int64 `json:"last_id" binding:"required"` // 已读到的最后一条消息Id
+}
+
+type markChatReadResponse struct {
+	Updated int64 `json:"updated"` // 本次标记为已读的消息数
+}
+
+// @Summary 标记消息已读
+// @Description 将与指定用户会话中 Id 不大于 last_id 的对方消息标记为已读，并向对方推送已读回执
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param read body markChatReadRequest true "会话及已读位置"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} markChatReadResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Router /v1/chat/read [post]
+func MarkChatRead(c *gin.Context) {
+	var req markChatReadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	now := utils.GetCurrentTime()
+	updated, err := controllers.MarkChatsRead(jwtUser.Id, req.UserId, req.LastId, now)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to mark messages read"})
+		return
+	}
+	if updated > 0 {
+		// 通知发送者已读，同时让自己的其他设备清除未读
+		event := models.WSEvent{Type: models.WSEventChatRead, Data: models.ChatReceiptEvent{UserId: jwtUser.Id, LastId: req.LastId, Time: now}}
+		utils.ChatHub.Publish(req.UserId, event)
+		utils.ChatHub.Publish(jwtUser.Id, event)
+	}
+
+	c.JSON(http.StatusOK, markChatReadResponse{Updated: updated})
+}
+
+type chatUnreadResponse struct {
+	Total         int64                    `json:"total"`         // 未读消息总数
+	Conversations []models.ChatUnreadCount `json:"conversations"` // 各会话的未读数，只包含有未读消息的会话
+}
+
+// @Summary 获取未读消息数
+// @Description 获取未读私聊消息总数及各会话的未读数
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} chatUnreadResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /v1/chat/unread [get]
+func GetChatUnreadCount(c *gin.Context) {
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	counts, err := controllers.CountUnreadChats(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to count unread messages"})
+		return
+	}
+	response := chatUnreadResponse{Conversations: []models.ChatUnreadCount{}}
+	for _, count := range counts {
+		response.Total += count.Count
+		response.Conversations = append(response.Conversations, count)
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary 获取会话列表
+// @Description 分页获取私聊会话列表，每个聊天对象一行，包含最近一条可见消息、未读数及对方资料，按最近消息时间倒序
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]models.ChatConversation}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /v1/chat/conversations [get]
+func GetChatConversations(c *gin.Context) {
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	conversations, total, err := controllers.GetChatConversations(jwtUser.Id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get conversations"})
+		return
+	}
+	lastMessages := make([]models.Chat, len(conversations))
+	for i := range conversations {
+		lastMessages[i] = conversations[i].LastMessage
+	}
+	attachChatPreviews(lastMessages)
+	for i := range conversations {
+		conversations[i].LastMessage = lastMessages[i]
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: conversations})
+}
@@ -0,0 +1,152 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+// canUseFileInChat 判断用户能否在聊天中发送该文件：自己上传的，或自己参与的会话中已发送过的（转发）
+func canUseFileInChat(fileInfo *models.File, userId int64) (bool, error) {
+	if fileInfo.UpLoadUserId == userId {
+		return true, nil
+	}
+	_, allowed, err := controllers.GetChatFileAccess(fileInfo.Id, userId)
+	return allowed, err
+}
+
+// prepareChatMessage 按消息类型校验请求并填充消息字段，失败时已写入响应
+func prepareChatMessage(c *gin.Context, chat *models.Chat, req *newChatRequest) bool {
+	if req.MsgType == "" {
+		req.MsgType = models.ChatMsgText
+	}
+	chat.MsgType = req.MsgType
+
+	switch req.MsgType {
+	case models.ChatMsgText:
+		if strings.TrimSpace(req.Content) == "" {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "content is required for text messages"})
+			return false
+		}
+	case models.ChatMsgImage, models.ChatMsgFile:
+		fileInfo, err := controllers.GetFileById(req.FileId)
+		if err != nil {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "file not found"})
+			return false
+		}
+		canUse, err := canUseFileInChat(fileInfo, chat.UserIdFrom)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check file access"})
+			return false
+		}
+		if !canUse {
+			c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you can only send files uploaded by yourself"})
+			return false
+		}
+		if req.MsgType == models.ChatMsgImage {
+			if !utils.IsImageType(fileInfo.FileType) {
+				c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "file is not an image"})
+				return false
+			}
+			// 图片消息需要宽高及缩略图用于预览
+			if _, err := ensureFileThumbnail(fileInfo); err != nil {
+				c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid image file"})
+				return false
+			}
+		}
+		chat.FileId = fileInfo.Id
+	case models.ChatMsgActivity:
+		activity, err := controllers.GetActivityById(req.ActivityId)
+		if err != nil {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+			return false
+		}
+		canView, err := canViewActivity(activity, chat.UserIdFrom)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check activity visibility"})
+			return false
+		}
+		if !canView {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "activity not found"})
+			return false
+		}
+		chat.ActivityId = activity.Id
+	case models.ChatMsgLocation:
+		if req.Lat == nil || req.Lon == nil || *req.Lat < -90 || *req.Lat > 90 || *req.Lon < -180 || *req.Lon > 180 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "valid lat and lon are required for location messages"})
+			return false
+		}
+		chat.Lat = *req.Lat
+		chat.Lon = *req.Lon
+	default:
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid msg_type"})
+		return false
+	}
+	return true
+}
+
+// attachChatPreviews 为图片和文件消息填充附件预览，宽高和缩略图取自实际存储的原始文件
+func attachChatPreviews(chats []models.Chat) {
+	var fileIds []int64
+	for _, chat := range chats {
+		if chat.FileId != 0 {
+			fileIds = append(fileIds, chat.FileId)
+		}
+	}
+	if len(fileIds) == 0 {
+		return
+	}
+	files, err := controllers.GetFilesByIds(fileIds)
+	if err != nil {
+		log.Printf("获取聊天附件失败: %v", err)
+		return
+	}
+	var linkIds []int64
+	for _, file := range files {
+		if file.LinkFileId != 0 {
+			linkIds = append(linkIds, file.LinkFileId)
+		}
+	}
+	originals, err := controllers.GetFilesByIds(linkIds)
+	if err != nil {
+		log.Printf("获取聊天附件原始文件失败: %v", err)
+		return
+	}
+	originalById := make(map[int64]models.File)
+	for _, file := range originals {
+		originalById[file.Id] = file
+	}
+
+	attachments := make(map[int64]*models.ChatAttachment)
+	for _, file := range files {
+		original := file
+		if linked, ok := originalById[file.LinkFileId]; ok {
+			original = linked
+		}
+		attachments[file.Id] = &models.ChatAttachment{
+			FileId:      file.Id,
+			FileName:    file.FileName,
+			FileType:    file.FileType,
+			FileSize:    file.FileSize,
+			Width:       original.Width,
+			Height:      original.Height,
+			ThumbnailId: original.ThumbnailId,
+		}
+	}
+	for i := range chats {
+		chats[i].Attachment = attachments[chats[i].FileId]
+	}
+}
+
+// attachChatPreview 为单条消息填充附件预览
+func attachChatPreview(chat *models.Chat) {
+	chats := []models.Chat{*chat}
+	attachChatPreviews(chats)
+	chat.Attachment = chats[0].Attachment
+}
@@ -0,0 +1,198 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+// defaultChatRecallWindow 未配置时消息可撤回和编辑的时长
+const defaultChatRecallWindow = 2 * time.Minute
+
+type editChatRequest struct {
+	Content string `json:"content" binding:"required"` // 新的消息内容
+}
+
+// chatRecallWindow 返回配置的撤回和编辑时限
+func chatRecallWindow() time.Duration {
+	seconds := config.GetConfig().Chat.RecallWindow
+	if seconds <= 0 {
+		return defaultChatRecallWindow
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// loadOwnChatInWindow 解析JWT并获取路径中自己发送、仍在时限内且未撤回的消息，失败时已写入响应
+func loadOwnChatInWindow(c *gin.Context) (*models.Chat, bool) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return nil, false
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return nil, false
+	}
+	chatId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid chat id"})
+		return nil, false
+	}
+	chat, err := controllers.GetChatById(chatId)
+	if err != nil || chat.UserIdFrom != jwtUser.Id || chat.StatusFrom == 0 {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "chat message not found"})
+		return nil, false
+	}
+	if chat.RecallTime != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "chat message has been recalled"})
+		return nil, false
+	}
+	if utils.GetCurrentTime().Sub(chat.CreateTime) > chatRecallWindow() {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "the time window for recalling or editing this message has expired"})
+		return nil, false
+	}
+	return chat, true
+}
+
+// publishChatUpdate 将撤回或编辑后的消息推送给会话双方
+func publishChatUpdate(eventType string, chat *models.Chat) {
+	event := models.WSEvent{Type: eventType, Data: chat}
+	utils.ChatHub.Publish(chat.UserIdTo, event)
+	utils.ChatHub.Publish(chat.UserIdFrom, event)
+}
+
+// @Summary 撤回聊天消息
+// @Description 发送方在时限内撤回消息，双方的消息内容和附件均被清除，消息类型变为 recalled 作为“消息已撤回”占位。
+// @Description 撤回后通过 chat_recalled 事件推送给双方。
+// @Tags 聊天相关接口
+// @Produce json
+// @Param id path int true "消息Id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.Chat
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/chat/{id}/recall [post]
+func RecallChat(c *gin.Context) {
+	chat, ok := loadOwnChatInWindow(c)
+	if !ok {
+		return
+	}
+
+	if err := controllers.RecallChat(chat, utils.GetCurrentTime()); err != nil {
+		if errors.Is(err, custom_errors.ErrChatRecalled) {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "chat message has been recalled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to recall chat message"})
+		return
+	}
+
+	publishChatUpdate(models.WSEventChatRecalled, chat)
+	c.JSON(http.StatusOK, chat)
+}
+
+// @Summary 编辑聊天消息
+// @Description 发送方在撤回时限内编辑文本消息，编辑前的内容保存到编辑历史。编辑后通过 chat_edited 事件推送给双方。
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param id path int true "消息Id"
+// @Param chat body editChatRequest true "新的消息内容"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.Chat
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/chat/{id} [put]
+func EditChat(c *gin.Context) {
+	var req editChatRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Content) == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	chat, ok := loadOwnChatInWindow(c)
+	if !ok {
+		return
+	}
+	if chat.MsgType != models.ChatMsgText {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "only text messages can be edited"})
+		return
+	}
+	if chat.Content == req.Content {
+		c.JSON(http.StatusOK, chat)
+		return
+	}
+
+	if err := controllers.EditChat(chat, req.Content, utils.GetCurrentTime()); err != nil {
+		if errors.Is(err, custom_errors.ErrChatRecalled) {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "chat message has been recalled"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to edit chat message"})
+		return
+	}
+
+	publishChatUpdate(models.WSEventChatEdited, chat)
+	c.JSON(http.StatusOK, chat)
+}
+
+// @Summary 获取聊天消息的编辑历史
+// @Description 会话双方可查看消息每次编辑前的内容，按编辑时间升序。撤回的消息没有编辑历史。
+// @Tags 聊天相关接口
+// @Produce json
+// @Param id path int true "消息Id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {array} models.ChatEdit
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/chat/{id}/edits [get]
+func GetChatEdits(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+	chatId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid chat id"})
+		return
+	}
+
+	chat, err := controllers.GetChatById(chatId)
+	visible := err == nil &&
+		((chat.UserIdFrom == jwtUser.Id && chat.StatusFrom != 0) || (chat.UserIdTo == jwtUser.Id && chat.StatusTo != 0))
+	if !visible {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "chat message not found"})
+		return
+	}
+
+	edits, err := controllers.GetChatEdits(chat.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get chat edit history"})
+		return
+	}
+	c.JSON(http.StatusOK, edits)
+}
@@ -0,0 +1,515 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const maxChatRoomNameLength = 64
+
+// loadChatRoomForMember 解析路径中的群聊Id及JWT，并校验当前用户为群成员，失败时已写入响应
+func loadChatRoomForMember(c *gin.Context) (*models.ChatRoom, *models.User, bool) {
+	roomId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid chat room id format"})
+		return nil, nil, false
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return nil, nil, false
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return nil, nil, false
+	}
+
+	room, err := controllers.GetChatRoomById(roomId)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "chat room not found"})
+		return nil, nil, false
+	}
+	isMember, err := controllers.IsChatRoomMember(room.Id, jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check chat room membership"})
+		return nil, nil, false
+	}
+	if !isMember {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only chat room members can access this resource"})
+		return nil, nil, false
+	}
+	return room, jwtUser, true
+}
+
+// checkFriendsForChatRoom 校验被拉入群聊的用户都是操作者的好友，失败时已写入响应
+func checkFriendsForChatRoom(c *gin.Context, userId int64, memberIds []int64) bool {
+	for _, memberId := range memberIds {
+		if memberId == userId {
+			continue
+		}
+		isFriend, err := controllers.AreFriends(userId, memberId)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check friendship"})
+			return false
+		}
+		if !isFriend {
+			c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you can only add your friends to a chat room"})
+			return false
+		}
+	}
+	return true
+}
+
+type createChatRoomRequest struct {
+	Name    string  `json:"name"`     // 群聊名称
+	UserIds []int64 `json:"user_ids"` // 初始成员，必须是创建者的好友
+}
+
+// @Summary 创建群聊
+// @Description 创建普通群聊并拉入好友，创建者成为群主
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param room body createChatRoomRequest true "群聊信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ChatRoom
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /v1/chat/room [post]
+func CreateChatRoom(c *gin.Context) {
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req createChatRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > maxChatRoomNameLength {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "name is required and must not exceed 64 characters"})
+		return
+	}
+	if !checkFriendsForChatRoom(c, jwtUser.Id, req.UserIds) {
+		return
+	}
+
+	now := utils.GetCurrentTime()
+	room := &models.ChatRoom{
+		Type:       models.ChatRoomTypeGroup,
+		Name:       req.Name,
+		OwnerId:    jwtUser.Id,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := controllers.CreateChatRoom(room, req.UserIds); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to create chat room"})
+		return
+	}
+
+	c.JSON(http.StatusOK, room)
+}
+
+// @Summary 获取我的群聊
+// @Description 分页获取当前用户加入的群聊（含活动群聊），按最后活跃时间倒序
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]models.ChatRoom}
+// @Failure 400 {object} models.ErrorResponse
+// @Router /v1/chat/room [get]
+func GetChatRooms(c *gin.Context) {
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	rooms, total, err := controllers.GetChatRoomsByUserId(jwtUser.Id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get chat rooms"})
+		return
+	}
+	if rooms == nil {
+		rooms = []models.ChatRoom{}
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: rooms})
+}
+
+type chatRoomDetailResponse struct {
+	models.ChatRoom
+	Members []models.ChatRoomMember `json:"members"` // 群成员
+}
+
+// @Summary 获取群聊详情
+// @Description 获取群聊信息及成员列表，仅群成员可查看
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "群聊id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} chatRoomDetailResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/chat/room/{id} [get]
+func GetChatRoom(c *gin.Context) {
+	room, _, ok := loadChatRoomForMember(c)
+	if !ok {
+		return
+	}
+
+	members, err := controllers.GetChatRoomMembers(room.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get chat room members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, chatRoomDetailResponse{ChatRoom: *room, Members: members})
+}
+
+type updateChatRoomRequest struct {
+	Name string `json:"name"` // 新的群聊名称
+}
+
+// @Summary 修改群聊名称
+// @Description 群主修改普通群聊名称，活动群聊名称跟随活动
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "群聊id"
+// @Param room body updateChatRoomRequest true "群聊名称"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ChatRoom
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/chat/room/{id} [post]
+func UpdateChatRoom(c *gin.Context) {
+	room, jwtUser, ok := loadChatRoomForMember(c)
+	if !ok {
+		return
+	}
+	if room.Type != models.ChatRoomTypeGroup || room.OwnerId != jwtUser.Id {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only the owner can update a group chat"})
+		return
+	}
+
+	var req updateChatRoomRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" || utf8.RuneCountInString(req.Name) > maxChatRoomNameLength {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "name is required and must not exceed 64 characters"})
+		return
+	}
+
+	room.Name = req.Name
+	if err := controllers.UpdateChatRoom(room); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update chat room"})
+		return
+	}
+
+	c.JSON(http.StatusOK, room)
+}
+
+type addChatRoomMembersRequest struct {
+	UserIds []int64 `json:"user_ids"` // 新成员，必须是操作者的好友
+}
+
+// @Summary 添加群成员
+// @Description 群主将好友拉入普通群聊，活动群聊成员随活动参与者同步
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "群聊id"
+// @Param members body addChatRoomMembersRequest true "新成员"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/chat/room/{id}/member [put]
+func AddChatRoomMembers(c *gin.Context) {
+	room, jwtUser, ok := loadChatRoomForMember(c)
+	if !ok {
+		return
+	}
+	if room.Type != models.ChatRoomTypeGroup {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "activity chat members follow the activity"})
+		return
+	}
+	if room.OwnerId != jwtUser.Id {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only the owner can add members"})
+		return
+	}
+
+	var req addChatRoomMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.UserIds) == 0 {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	if !checkFriendsForChatRoom(c, jwtUser.Id, req.UserIds) {
+		return
+	}
+
+	if err := controllers.AddChatRoomMembers(room, req.UserIds, utils.GetCurrentTime()); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to add chat room members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "members added successfully"})
+}
+
+// @Summary 移除群成员
+// @Description 群主移除普通群聊成员，成员也可移除自己以退出群聊；群主退出时由最早加入的成员接任
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "群聊id"
+// @Param userId path integer true "成员用户id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/chat/room/{id}/member/{userId} [delete]
+func RemoveChatRoomMember(c *gin.Context) {
+	room, jwtUser, ok := loadChatRoomForMember(c)
+	if !ok {
+		return
+	}
+	memberUserId, err := utils.StringToInt64(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid user id format"})
+		return
+	}
+	if room.Type != models.ChatRoomTypeGroup {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "leave the activity to leave its chat"})
+		return
+	}
+	if memberUserId != jwtUser.Id && room.OwnerId != jwtUser.Id {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "only the owner can remove other members"})
+		return
+	}
+
+	if err := controllers.RemoveChatRoomMember(room, memberUserId); err != nil {
+		if errors.Is(err, custom_errors.ErrNotChatRoomMember) {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "member not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to remove chat room member"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "member removed successfully"})
+}
+
+type sendChatRoomMessageRequest struct {
+	Content string `json:"content" binding:"required"` // 消息内容
+}
+
+// @Summary 发送群消息
+// @Description 群成员向群聊发送消息
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "群聊id"
+// @Param message body sendChatRoomMessageRequest true "消息内容"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ChatRoomMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/chat/room/{id}/message [post]
+func SendChatRoomMessage(c *gin.Context) {
+	room, jwtUser, ok := loadChatRoomForMember(c)
+	if !ok {
+		return
+	}
+
+	var req sendChatRoomMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Content) == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	message := &models.ChatRoomMessage{
+		RoomId:     room.Id,
+		UserIdFrom: jwtUser.Id,
+		Content:    req.Content,
+		CreateTime: utils.GetCurrentTime(),
+	}
+	if err := controllers.AddChatRoomMessage(message); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to send message"})
+		return
+	}
+	if members, err := controllers.GetChatRoomMembers(room.Id); err == nil {
+		senderName := userDisplayName(jwtUser.Id)
+		for _, member := range members {
+			utils.ChatHub.Publish(member.UserId, models.WSEvent{Type: models.WSEventChatRoomMessage, Data: message})
+			if member.UserId != jwtUser.Id {
+				pushToUser(models.PushMessage{
+					UserId:   member.UserId,
+					Category: models.PushCategoryChat,
+					Title:    room.Name,
+					Body:     senderName + ": " + message.Content,
+					Data:     map[string]string{"roomId": strconv.FormatInt(room.Id, 10)},
+				})
+			}
+		}
+	}
+
+	c.JSON(http.StatusOK, message)
+}
+
+// @Summary 获取群聊记录
+// @Description 分页获取群聊消息，按时间倒序，不包含自己已删除的消息
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "群聊id"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]models.ChatRoomMessage}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/chat/room/{id}/message [get]
+func GetChatRoomMessages(c *gin.Context) {
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+	room, jwtUser, ok := loadChatRoomForMember(c)
+	if !ok {
+		return
+	}
+
+	messages, total, err := controllers.GetChatRoomMessages(room.Id, jwtUser.Id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get chat room messages"})
+		return
+	}
+	if messages == nil {
+		messages = []models.ChatRoomMessage{}
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: messages})
+}
+
+// @Summary 删除群消息
+// @Description 从自己的群聊记录中删除一条消息，其他成员仍可见
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "群聊id"
+// @Param messageId path integer true "消息id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/chat/room/{id}/message/{messageId} [delete]
+func DeleteChatRoomMessage(c *gin.Context) {
+	room, jwtUser, ok := loadChatRoomForMember(c)
+	if !ok {
+		return
+	}
+	messageId, err := utils.StringToInt64(c.Param("messageId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Invalid message id format"})
+		return
+	}
+	message, err := controllers.GetChatRoomMessageById(messageId)
+	if err != nil || message.RoomId != room.Id {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "message not found"})
+		return
+	}
+
+	if err := controllers.DeleteChatRoomMessageForUser(message.Id, jwtUser.Id, utils.GetCurrentTime()); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to delete message"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "message deleted successfully"})
+}
+
+// @Summary 获取活动群聊
+// @Description 获取活动对应的群聊，活动参与者自动成为群成员
+// @Tags 活动相关接口
+// @Accept json
+// @Produce json
+// @Param id path integer true "活动id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.ChatRoom
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /v1/activity/{id}/chat [get]
+func GetActivityChatRoom(c *gin.Context) {
+	dbActivity, jwtUser, ok := loadActivityForParticipant(c)
+	if !ok {
+		return
+	}
+
+	participantIds, err := getActivityParticipantIds(dbActivity)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get activity members"})
+		return
+	}
+	room, err := controllers.EnsureActivityChatRoom(dbActivity, participantIds, utils.GetCurrentTime())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get activity chat room"})
+		return
+	}
+	// 补齐群聊创建后因同步失败而缺失的参与者
+	if err := controllers.AddChatRoomMembers(room, []int64{jwtUser.Id}, utils.GetCurrentTime()); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to join activity chat room"})
+		return
+	}
+
+	c.JSON(http.StatusOK, room)
+}
@@ -0,0 +1,129 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const (
+	defaultChatSearchContext = 2   // 默认返回命中消息前后各多少条消息
+	maxChatSearchContext     = 10  // 前后消息条数上限
+	maxChatSearchKeyword     = 100 // 关键词最大长度（字符数）
+	chatSnippetRadius        = 20  // 片段中关键词前后保留的字符数
+)
+
+// @Summary 搜索聊天记录
+// @Description 在自己可见且未撤回的私聊消息中搜索关键词，按消息Id倒序分页。
+// @Description SQLite 使用 FTS5、MySQL 使用 FULLTEXT 索引，未建立全文索引时使用模糊匹配。
+// @Description 每条结果包含以 <em></em> 高亮关键词的片段，以及同一会话中前后若干条消息用于跳转定位。
+// @Tags 聊天相关接口
+// @Produce json
+// @Param q query string true "搜索关键词"
+// @Param to_user_id query int false "只搜索与该用户的会话"
+// @Param starttime query string false "开始时间，格式为YYYY-MM-DD HH:MM:SS"
+// @Param endtime query string false "结束时间，格式为YYYY-MM-DD HH:MM:SS"
+// @Param context query int false "返回命中消息前后各多少条消息，默认为2，最大为10，0表示不返回"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页条数，默认为10，最大为100"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]models.ChatSearchResult}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/chat/search [get]
+func SearchChats(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	keyword := strings.TrimSpace(c.Query("q"))
+	if keyword == "" || utf8.RuneCountInString(keyword) > maxChatSearchKeyword {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid search keyword"})
+		return
+	}
+
+	var counterpartId int64
+	if toUserIdStr := c.Query("to_user_id"); toUserIdStr != "" {
+		counterpartId, err = utils.StringToInt64(toUserIdStr)
+		if err != nil || counterpartId < 1 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid to_user_id"})
+			return
+		}
+	}
+
+	var startTime, endTime time.Time
+	if startTimeStr := c.Query("starttime"); startTimeStr != "" {
+		if startTime = utils.ParseTimeFromString(startTimeStr); startTime.IsZero() {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid starttime"})
+			return
+		}
+	}
+	if endTimeStr := c.Query("endtime"); endTimeStr != "" {
+		if endTime = utils.ParseTimeFromString(endTimeStr); endTime.IsZero() {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid endtime"})
+			return
+		}
+	}
+
+	contextSize := defaultChatSearchContext
+	if contextStr := c.Query("context"); contextStr != "" {
+		contextSize, err = utils.StringToInt(contextStr)
+		if err != nil || contextSize < 0 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid context"})
+			return
+		}
+		contextSize = min(contextSize, maxChatSearchContext)
+	}
+
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+
+	chats, total, err := controllers.SearchChats(jwtUser.Id, counterpartId, keyword, startTime, endTime, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to search chat messages"})
+		return
+	}
+	attachChatPreviews(chats)
+
+	results := make([]models.ChatSearchResult, 0, len(chats))
+	for _, chat := range chats {
+		result := models.ChatSearchResult{
+			Message:       chat,
+			Snippet:       utils.HighlightSnippet(chat.Content, keyword, chatSnippetRadius),
+			ContextBefore: []models.Chat{},
+			ContextAfter:  []models.Chat{},
+		}
+		if contextSize > 0 {
+			before, after, err := controllers.GetChatContext(jwtUser.Id, &chat, contextSize)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get chat context"})
+				return
+			}
+			attachChatPreviews(before)
+			attachChatPreviews(after)
+			result.ContextBefore = before
+			result.ContextAfter = after
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: results})
+}
@@ -159,6 +159,7 @@ func UploadFile(c *gin.Context) {
 // @Param id path int true "文件ID"
 // @Success 200 {file} binary
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /v1/file/{id} [get]
 func DownloadFile(c *gin.Context) {
@@ -169,7 +170,7 @@ func DownloadFile(c *gin.Context) {
 		return
 	}
 
-	_, err := utils.ParseJWT(jwtToken)
+	jwtUser, err := utils.ParseJWT(jwtToken)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
 		return
@@ -192,6 +193,19 @@ func DownloadFile(c *gin.Context) {
 		return
 	}
 
+	// 作为私聊附件发送的文件（及其缩略图）仅上传者和会话双方可下载
+	if fileInfo.UpLoadUserId != jwtUser.Id {
+		attached, allowed, err := controllers.GetChatFileAccess(fileInfo.Id, jwtUser.Id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check file access"})
+			return
+		}
+		if attached && !allowed {
+			c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you do not have access to this file"})
+			return
+		}
+	}
+
 	if fileInfo.LinkFileId > 0 {
 		// 如果是关联文件，获取原始文件信息
 		originalFile, err := controllers.GetFileById(fileInfo.LinkFileId)
@@ -251,6 +265,7 @@ func DeleteFile(c *gin.Context) {
 		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "You do not have permission to delete this file"})
 		return
 	}
+	original := fileInfo // 实际存储在磁盘上的原始文件
 	if fileInfo.LinkFileId > 0 {
 		linkFile, err := controllers.GetFileById(fileInfo.LinkFileId)
 		if err != nil {
@@ -266,6 +281,7 @@ func DeleteFile(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "Failed to delete file record from database"})
 			return
 		}
+		original = linkFile
 	} else if fileInfo.LinkFileId == 0 {
 		destPath := filepath.Join(config.GetConfig().File.UploadPath, fmt.Sprintf("%d%s", fileInfo.Id, fileInfo.FileType))
 		if err := os.Remove(destPath); err != nil {
@@ -276,6 +292,11 @@ func DeleteFile(c *gin.Context) {
 			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "Failed to delete file record from database"})
 			return
 		}
+		removeFileThumbnail(fileInfo)
+		// 磁盘文件已删除，使用该文件作为封面的活动都清除封面
+		if err := controllers.ClearActivityCovers(fmt.Sprintf("%d%s", fileInfo.Id, fileInfo.FileType)); err != nil {
+			log.Printf("清除使用文件 %d 的活动封面失败: %v", fileId, err)
+		}
 	} else {
 		fileInfo.UpLoadUserId = 0 // 清除上传用户ID
 		if err := controllers.UpdateFile(fileInfo); err != nil {
@@ -283,9 +304,104 @@ func DeleteFile(c *gin.Context) {
 			return
 		}
 	}
+	// 清理引用该文件的活动相册图片，封面为该图片的活动一并清除封面
+	if err := controllers.DeleteActivityPhotosByFileId(fileId, fmt.Sprintf("%d%s", original.Id, original.FileType)); err != nil {
+		log.Printf("清理文件 %d 关联的活动相册失败: %v", fileId, err)
+	}
 	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "File deleted successfully"})
 }
 
+// resolveOriginalFile 如果是关联文件，返回实际存储在磁盘上的原始文件
+func resolveOriginalFile(fileInfo *models.File) (*models.File, error) {
+	if fileInfo.LinkFileId > 0 {
+		return controllers.GetFileById(fileInfo.LinkFileId)
+	}
+	return fileInfo, nil
+}
+
+// removeFileThumbnail 原始文件从磁盘删除后，删除不再被其他文件使用的缩略图
+func removeFileThumbnail(original *models.File) {
+	if original.ThumbnailId == 0 {
+		return
+	}
+	thumbnail, err := controllers.GetFileById(original.ThumbnailId)
+	if err != nil {
+		return
+	}
+	// 缩略图也可能被相同内容的上传关联，或与其他图片的缩略图相同
+	if thumbnail.LinkFileId != 0 {
+		return
+	}
+	count, err := controllers.CountFilesByThumbnailId(thumbnail.Id)
+	if err != nil || count > 0 {
+		return
+	}
+	destPath := filepath.Join(config.GetConfig().File.UploadPath, fmt.Sprintf("%d%s", thumbnail.Id, thumbnail.FileType))
+	if err := os.Remove(destPath); err != nil {
+		log.Printf("删除缩略图文件 %d 失败: %v", thumbnail.Id, err)
+		return
+	}
+	if err := controllers.DeleteFileById(thumbnail.Id); err != nil {
+		log.Printf("删除缩略图记录 %d 失败: %v", thumbnail.Id, err)
+	}
+}
+
+// ensureFileThumbnail 为图片文件生成缩略图并记录宽高，已生成过则直接返回原始文件
+func ensureFileThumbnail(fileInfo *models.File) (*models.File, error) {
+	original, err := resolveOriginalFile(fileInfo)
+	if err != nil {
+		return nil, err
+	}
+	if original.ThumbnailId != 0 || !utils.IsImageType(original.FileType) {
+		return original, nil
+	}
+
+	fileConfig := config.GetConfig().File
+	thumbnailSize := fileConfig.ThumbnailSize
+	if thumbnailSize <= 0 {
+		thumbnailSize = 256
+	}
+	src, err := os.Open(filepath.Join(fileConfig.UploadPath, fmt.Sprintf("%d%s", original.Id, original.FileType)))
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	data, width, height, err := utils.GenerateThumbnail(src, thumbnailSize)
+	if err != nil {
+		return nil, err
+	}
+
+	thumbHash := fmt.Sprintf("%x", sha256.Sum256(data))
+	thumbnail, err := controllers.GetFileByHash(thumbHash)
+	if err != nil {
+		// 缩略图作为普通文件记录保存，可通过文件下载接口获取
+		thumbnail = &models.File{
+			FileName:     "thumb_" + strings.TrimSuffix(original.FileName, filepath.Ext(original.FileName)) + ".jpg",
+			FileType:     ".jpg",
+			FileSize:     int64(len(data)),
+			FileHash:     thumbHash,
+			CreateTime:   utils.GetCurrentTime(),
+			UpLoadUserId: original.UpLoadUserId,
+		}
+		if err := controllers.AddFile(thumbnail); err != nil {
+			return nil, err
+		}
+		destPath := filepath.Join(fileConfig.UploadPath, fmt.Sprintf("%d%s", thumbnail.Id, thumbnail.FileType))
+		if err := os.WriteFile(destPath, data, 0644); err != nil {
+			controllers.DeleteFileById(thumbnail.Id)
+			return nil, err
+		}
+	}
+
+	original.Width = width
+	original.Height = height
+	original.ThumbnailId = thumbnail.Id
+	if err := controllers.UpdateFile(original); err != nil {
+		return nil, err
+	}
+	return original, nil
+}
+
 func ServeBasicFile(c *gin.Context) {
 	filename := c.Param("filename")
 	log.Printf("Serving file: %s", filename)
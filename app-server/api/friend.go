@@ -1,46 +1,66 @@
 package api
 
 import (
+	"errors"
 	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
 	"hobbyhub-server/models"
 	"hobbyhub-server/utils"
+	"log"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 )
 
 type FriendResponse struct {
-	Id         int64     `json:"id" binding:"required"`          // 记录ID
+	Id         int64     `json:"id" binding:"required"`          // 好友关系记录ID
 	FriendId   int64     `json:"friend_id" binding:"required"`   // 好友ID
 	Status     int       `json:"status" binding:"required"`      // 好友状态（0: 拒绝, 1: 接受, 2: 等待接受, 3：已发出申请）
 	CreateTime time.Time `json:"create_time" binding:"required"` // 创建时间
+
+	Presence *models.Presence `json:"presence,omitempty"`  // 在线状态，仅已成为好友时返回
+	Remark   string           `json:"remark,omitempty"`    // 自己给好友设置的备注名
+	GroupIds []int64          `json:"group_ids,omitempty"` // 好友所在的分组Id
 }
 
-func infoFromModelsFriend(friend models.Friend) FriendResponse {
-	// 将 models.Friend 转换为 FriendResponse
+// friendResponseFor 将好友关系转换为从 userId 一方看到的 FriendResponse
+func friendResponseFor(friendship *models.Friendship, userId int64) FriendResponse {
 	return FriendResponse{
-		Id:         friend.Id,
-		FriendId:   friend.FriendId,
-		Status:     friend.Status,
-		CreateTime: friend.CreateTime,
+		Id:         friendship.Id,
+		FriendId:   friendship.OtherUserId(userId),
+		Status:     friendship.StatusFor(userId),
+		CreateTime: friendship.CreateTime,
 	}
 }
-func infoFromModelsFriends(friends []models.Friend) []FriendResponse {
-	// 将 []models.Friend 转换为 []FriendResponse
-	var friendResponses []FriendResponse
-	for _, friend := range friends {
-		friendResponses = append(friendResponses, infoFromModelsFriend(friend))
+
+// respondFriendActionError 将好友关系操作的错误转换为响应，非校验错误使用 fallback 作为错误信息
+func respondFriendActionError(c *gin.Context, err error, fallback string) {
+	switch {
+	case errors.Is(err, custom_errors.ErrFriendRequestNotFound):
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: err.Error()})
+	case errors.Is(err, custom_errors.ErrFriendSelf),
+		errors.Is(err, custom_errors.ErrFriendAlreadyExists),
+		errors.Is(err, custom_errors.ErrFriendRequestAlreadySent),
+		errors.Is(err, custom_errors.ErrFriendRequestExpired),
+		errors.Is(err, custom_errors.ErrNotFriends):
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: fallback})
 	}
-	return friendResponses
 }
 
 // @Summary 获取好友列表
-// @Description 通过用户ID获取好友列表
+// @Description 获取当前用户的所有好友关系，包括待处理及被拒绝的申请，已成为好友的记录附带在线状态、备注名及所在分组。
+// @Description 指定 group_id 时只返回该分组中的好友
 // @Tags 好友相关接口
 // @Produce json
+// @Param group_id query int false "好友分组Id"
 // @Param Authorization header string true "JWT Token"
-// @Success 200 {array} models.User
+// @Success 200 {array} FriendResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /v1/friend [get]
@@ -59,27 +79,85 @@ func GetFriendList(c *gin.Context) {
 		return
 	}
 
-	friends, err := controllers.GetAllFriendsByUserId(jwtUser.Id)
+	var groupId int64
+	if groupIdStr := c.Query("group_id"); groupIdStr != "" {
+		groupId, err = utils.StringToInt64(groupIdStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid group id"})
+			return
+		}
+		group, err := controllers.GetFriendGroupById(groupId)
+		if err != nil || group.UserId != jwtUser.Id {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "friend group not found"})
+			return
+		}
+	}
+
+	friendships, err := controllers.GetFriendshipsByUserId(jwtUser.Id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "no friends"})
 		return
 	}
+	remarks, err := controllers.GetFriendRemarks(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend remarks"})
+		return
+	}
+	members, err := controllers.GetFriendGroupMembersByUserId(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend groups"})
+		return
+	}
+	groupIds := make(map[int64][]int64)
+	for _, member := range members {
+		groupIds[member.FriendId] = append(groupIds[member.FriendId], member.GroupId)
+	}
 
-	c.JSON(http.StatusOK, infoFromModelsFriends(friends))
+	// 批量获取已成为好友的用户的在线状态
+	var friendIds []int64
+	responses := make([]FriendResponse, 0, len(friendships))
+	for i := range friendships {
+		response := friendResponseFor(&friendships[i], jwtUser.Id)
+		if response.Status == models.FriendStatusAccepted {
+			response.Remark = remarks[response.FriendId]
+			response.GroupIds = groupIds[response.FriendId]
+		}
+		if groupId != 0 && !slices.Contains(response.GroupIds, groupId) {
+			continue
+		}
+		if response.Status == models.FriendStatusAccepted {
+			friendIds = append(friendIds, response.FriendId)
+		}
+		responses = append(responses, response)
+	}
+	presences, err := lookupPresences(friendIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get presence"})
+		return
+	}
+
+	for i := range responses {
+		if presence, ok := presences[responses[i].FriendId]; ok && responses[i].Status == models.FriendStatusAccepted {
+			responses[i].Presence = &presence
+		}
+	}
+	c.JSON(http.StatusOK, responses)
 }
 
 type FriendRequest struct {
-	UserId int64 `json:"user_id" binding:"required"` // 好友用户ID
+	UserId  int64  `json:"user_id" binding:"required"` // 好友用户ID
+	Message string `json:"message"`                    // 申请附言，最多100个字符
 }
 
 // @Summary 发送好友申请
-// @Description 通过用户ID发送好友申请
+// @Description 通过用户ID发送好友申请，可附带附言。对方已向自己发出申请时直接成为好友；被拒绝或已过期的申请可以重新发送
 // @Tags 好友相关接口
 // @Produce json
 // @Param friendRequest body FriendRequest true "好友申请信息"
 // @Param Authorization header string true "JWT Token"
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /v1/friend [post]
 func SendFriendRequest(c *gin.Context) {
@@ -105,65 +183,59 @@ func SendFriendRequest(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "Unable to send friend request to oneself"})
 		return
 	}
+	request.Message = strings.TrimSpace(request.Message)
+	if utf8.RuneCountInString(request.Message) > maxFriendRequestMessageLength {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "friend request message is too long"})
+		return
+	}
 
-	friend1, friend2, _ := controllers.GetFriendByUserIdAndFriendId(jwtUser.Id, request.UserId)
-	if friend1.Id != 0 && friend2.Id != 0 {
-		if friend1.Status == 1 && friend2.Status == 1 {
-			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "friend already exists"})
-		} else if friend1.Status == 3 && friend2.Status == 2 {
-			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "friend request already sent"})
-		} else if friend1.Status == 2 && friend2.Status == 3 {
-			friend1.Status = 1 // 更新状态为接受
-			friend2.Status = 1 // 更新状态为接受
-			if err := controllers.UpdateFriendSynchronize(friend1, friend2); err != nil {
-				c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update friend status"})
-				return
-			}
-			c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend request updated successfully"})
-			return
-		} else if friend1.Status == 0 || friend2.Status == 0 {
-			friend1.Status = 3 // 更新状态为已发出申请
-			friend2.Status = 2 // 更新状态为等待接受
-			if err := controllers.UpdateFriendSynchronize(friend1, friend2); err != nil {
-				c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update friend status"})
-				return
-			}
-			c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend request sent successfully"})
-			return
-		} else {
-			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "friend request error"})
-			return
-		}
-	} else {
-		friend := &models.Friend{
-			UserId:     jwtUser.Id,
-			FriendId:   request.UserId,
-			Status:     3,
-			CreateTime: utils.GetCurrentTime(),
-		}
+	// 任意一方屏蔽了对方时不能发送好友申请
+	blocked, err := controllers.IsBlockedBetween(jwtUser.Id, request.UserId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check block status"})
+		return
+	}
+	if blocked {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "unable to send friend request to this user"})
+		return
+	}
+	if _, err := controllers.GetUserByUserId(request.UserId); err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "user not found"})
+		return
+	}
 
-		if err := controllers.AddFriend(friend); err != nil {
-			c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to send friend request"})
-			return
-		}
+	now := utils.GetCurrentTime()
+	friendship, err := controllers.ApplyFriendAction(jwtUser.Id, request.UserId, models.FriendActionRequest, request.Message, now, friendRequestExpireBefore(now))
+	if err != nil {
+		respondFriendActionError(c, err, "failed to send friend request")
+		return
+	}
 
-		c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend request sent successfully"})
+	if friendship.Status == models.FriendshipAccepted {
+		// 对方已向自己发出申请，直接成为好友
+		utils.ChatHub.Publish(request.UserId, models.WSEvent{Type: models.WSEventFriendResponse, Data: models.FriendEvent{UserId: jwtUser.Id, Status: models.FriendStatusAccepted}})
+		notifyFriendEvent(request.UserId, jwtUser.Id, models.NotificationFriendAccepted, "")
+		c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend request updated successfully"})
 		return
 	}
+	utils.ChatHub.Publish(request.UserId, models.WSEvent{Type: models.WSEventFriendRequest, Data: models.FriendEvent{UserId: jwtUser.Id, Status: models.FriendStatusIncoming}})
+	notifyFriendEvent(request.UserId, jwtUser.Id, models.NotificationFriendRequest, request.Message)
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend request sent successfully"})
 }
 
 type UpdateFriendRequest struct {
-	FriendId int64 `json:"friend_id" binding:"required"`        // 好友ID
-	Status   int   `json:"status" binding:"required,oneof=0 1"` // 申请状态，0-拒绝，1-同意
+	FriendId int64 `json:"friend_id" binding:"required"`        // 申请人ID
+	Status   *int  `json:"status" binding:"required,oneof=0 1"` // 处理结果，0-拒绝，1-同意
 }
 
-// @Summary 更新好友状态
-// @Description 通过ID更新好友状态
+// @Summary 处理好友申请
+// @Description 同意或拒绝对方发给自己的好友申请，不能处理自己发出的申请
 // @Tags 好友相关接口
 // @Produce json
 // @Param updatefriendRequest body UpdateFriendRequest true "好友申请信息"
 // @Param Authorization header string true "JWT Token"
-// @Success 200 {object} models.Friend
+// @Success 200 {object} FriendResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
 // @Router /v1/friend [put]
@@ -186,33 +258,33 @@ func UpdateFriendStatus(c *gin.Context) {
 		return
 	}
 
-	friend := &models.Friend{
-		Status: request.Status,
+	action := models.FriendActionReject
+	if *request.Status == models.FriendStatusAccepted {
+		action = models.FriendActionAccept
 	}
-
-	dbFriend1, dbFriend2, err := controllers.GetFriendByUserIdAndFriendId(jwtUser.Id, request.FriendId)
+	now := utils.GetCurrentTime()
+	friendship, err := controllers.ApplyFriendAction(jwtUser.Id, request.FriendId, action, "", now, friendRequestExpireBefore(now))
 	if err != nil {
-		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "friend not found"})
+		respondFriendActionError(c, err, "failed to update friend status")
 		return
 	}
-	if dbFriend1.Status != 2 && dbFriend1.Status != 3 {
-		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "friend request not in pending state"})
-		return
+
+	utils.ChatHub.Publish(request.FriendId, models.WSEvent{Type: models.WSEventFriendResponse, Data: models.FriendEvent{UserId: jwtUser.Id, Status: *request.Status}})
+	if action == models.FriendActionAccept {
+		notifyFriendEvent(request.FriendId, jwtUser.Id, models.NotificationFriendAccepted, "")
 	}
-	dbFriend1.UpdateFriendFields(*friend)
-	dbFriend2.UpdateFriendFields(*friend)
-	if err := controllers.UpdateFriendSynchronize(dbFriend1, dbFriend2); err != nil {
-		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update friend status"})
-		return
+	// 申请已处理，对应的通知不再需要处理
+	if err := controllers.MarkNotificationsReadByRelatedId(jwtUser.Id, models.NotificationFriendRequest, request.FriendId); err != nil {
+		log.Printf("将好友申请通知标记为已读失败: %v", err)
 	}
-	c.JSON(http.StatusOK, infoFromModelsFriend(*dbFriend1))
+	c.JSON(http.StatusOK, friendResponseFor(friendship, jwtUser.Id))
 }
 
 // @Summary 删除好友
-// @Description 通过ID删除好友状态
+// @Description 解除与好友的关系，只能删除自己参与且已成为好友的关系，对方通过 friend_removed 事件收到通知
 // @Tags 好友相关接口
 // @Produce json
-// @Param id path integer true "需要删除的Friend 记录ID"
+// @Param id path integer true "好友关系记录ID"
 // @Param Authorization header string true "JWT Token"
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
@@ -231,30 +303,31 @@ func DeleteFriend(c *gin.Context) {
 		return
 	}
 
-	_, err := utils.ParseJWT(jwtToken)
+	jwtUser, err := utils.ParseJWT(jwtToken)
 	if err != nil {
 		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "unauthorized access"})
 		return
 	}
 
-	friendId, err := utils.StringToInt64(id)
+	friendshipId, err := utils.StringToInt64(id)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid friend ID format"})
 		return
 	}
 
-	dbFriend, err := controllers.GetFriendById(friendId)
-	if err != nil {
+	// 只能删除自己参与的好友关系
+	friendship, err := controllers.GetFriendshipById(friendshipId)
+	if err != nil || (friendship.UserLowId != jwtUser.Id && friendship.UserHighId != jwtUser.Id) {
 		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "friend not found"})
 		return
 	}
 
-	dbFriend.Status = 0 // 设置状态为0，表示删除好友
-
-	if err := controllers.UpdateFriend(dbFriend); err != nil {
-		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to delete friend"})
+	friendId := friendship.OtherUserId(jwtUser.Id)
+	if _, err := controllers.ApplyFriendAction(jwtUser.Id, friendId, models.FriendActionUnfriend, "", utils.GetCurrentTime(), time.Time{}); err != nil {
+		respondFriendActionError(c, err, "failed to delete friend")
 		return
 	}
+	utils.ChatHub.Publish(friendId, models.WSEvent{Type: models.WSEventFriendRemoved, Data: models.FriendEvent{UserId: jwtUser.Id, Status: models.FriendStatusRejected}})
 
 	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend deleted successfully"})
 }
@@ -0,0 +1,417 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const (
+	maxFriendGroups          = 50 // 每个用户最多创建的好友分组数
+	maxFriendGroupNameLength = 20 // 分组名称最大长度（字符数）
+	maxFriendRemarkLength    = 30 // 好友备注名最大长度（字符数）
+)
+
+type friendGroupRequest struct {
+	Name string `json:"name"` // 分组名称，最多20个字符
+}
+
+type reorderFriendGroupsRequest struct {
+	GroupIds []int64 `json:"group_ids"` // 按新顺序排列的全部分组Id
+}
+
+type friendGroupMembersRequest struct {
+	FriendIds []int64 `json:"friend_ids"` // 分组中的全部好友Id，为空表示清空分组
+}
+
+type friendRemarkRequest struct {
+	FriendId int64  `json:"friend_id" binding:"required"` // 好友ID
+	Remark   string `json:"remark"`                       // 备注名，最多30个字符，为空表示清除备注
+}
+
+// loadOwnFriendGroup 解析JWT及路径中的分组，分组不属于当前用户时返回404，失败时已写入响应
+func loadOwnFriendGroup(c *gin.Context) (*models.FriendGroup, *models.User, bool) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return nil, nil, false
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return nil, nil, false
+	}
+	groupId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid group id"})
+		return nil, nil, false
+	}
+	group, err := controllers.GetFriendGroupById(groupId)
+	if err != nil || group.UserId != jwtUser.Id {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "friend group not found"})
+		return nil, nil, false
+	}
+	return group, jwtUser, true
+}
+
+// validateFriendGroupName 校验分组名称并检查是否与用户的其他分组重名，失败时已写入响应
+func validateFriendGroupName(c *gin.Context, userId int64, name string, excludeId int64) bool {
+	if name == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "group name is required"})
+		return false
+	}
+	if utf8.RuneCountInString(name) > maxFriendGroupNameLength {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "group name is too long"})
+		return false
+	}
+	exists, err := controllers.FriendGroupNameExists(userId, name, excludeId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check group name"})
+		return false
+	}
+	if exists {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "group name already exists"})
+		return false
+	}
+	return true
+}
+
+// @Summary 获取好友分组
+// @Description 获取当前用户的所有好友分组及其中的好友，按用户设置的顺序排列
+// @Tags 好友相关接口
+// @Produce json
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {array} models.FriendGroupInfo
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/group [get]
+func GetFriendGroups(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	groups, err := controllers.GetFriendGroupsByUserId(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend groups"})
+		return
+	}
+	members, err := controllers.GetFriendGroupMembersByUserId(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend groups"})
+		return
+	}
+	friendIds := make(map[int64][]int64)
+	for _, member := range members {
+		friendIds[member.GroupId] = append(friendIds[member.GroupId], member.FriendId)
+	}
+
+	result := make([]models.FriendGroupInfo, 0, len(groups))
+	for _, group := range groups {
+		ids := friendIds[group.Id]
+		if ids == nil {
+			ids = []int64{}
+		}
+		result = append(result, models.FriendGroupInfo{Id: group.Id, Name: group.Name, SortOrder: group.SortOrder, FriendIds: ids})
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary 创建好友分组
+// @Description 创建一个新的好友分组，排在已有分组之后。分组名称不能重复，每个用户最多50个分组
+// @Tags 好友相关接口
+// @Accept json
+// @Produce json
+// @Param group body friendGroupRequest true "分组信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.FriendGroupInfo
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/group [post]
+func CreateFriendGroup(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req friendGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if !validateFriendGroupName(c, jwtUser.Id, name, 0) {
+		return
+	}
+	groups, err := controllers.GetFriendGroupsByUserId(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend groups"})
+		return
+	}
+	if len(groups) >= maxFriendGroups {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: fmt.Sprintf("at most %d friend groups are allowed", maxFriendGroups)})
+		return
+	}
+
+	now := utils.GetCurrentTime()
+	group := &models.FriendGroup{UserId: jwtUser.Id, Name: name, CreateTime: now, UpdateTime: now}
+	if err := controllers.CreateFriendGroup(group); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to create friend group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.FriendGroupInfo{Id: group.Id, Name: group.Name, SortOrder: group.SortOrder, FriendIds: []int64{}})
+}
+
+// @Summary 重命名好友分组
+// @Description 修改自己的好友分组名称
+// @Tags 好友相关接口
+// @Accept json
+// @Produce json
+// @Param id path int true "分组Id"
+// @Param group body friendGroupRequest true "分组信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/group/{id} [put]
+func UpdateFriendGroup(c *gin.Context) {
+	group, jwtUser, ok := loadOwnFriendGroup(c)
+	if !ok {
+		return
+	}
+
+	var req friendGroupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	name := strings.TrimSpace(req.Name)
+	if !validateFriendGroupName(c, jwtUser.Id, name, group.Id) {
+		return
+	}
+
+	group.Name = name
+	group.UpdateTime = utils.GetCurrentTime()
+	if err := controllers.UpdateFriendGroup(group); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update friend group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend group updated successfully"})
+}
+
+// @Summary 删除好友分组
+// @Description 删除自己的好友分组，分组中的好友关系不受影响
+// @Tags 好友相关接口
+// @Produce json
+// @Param id path int true "分组Id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/group/{id} [delete]
+func DeleteFriendGroup(c *gin.Context) {
+	group, _, ok := loadOwnFriendGroup(c)
+	if !ok {
+		return
+	}
+
+	if err := controllers.DeleteFriendGroup(group.Id); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to delete friend group"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend group deleted successfully"})
+}
+
+// @Summary 调整好友分组顺序
+// @Description 按给定顺序重新排列自己的全部好友分组，group_ids 必须恰好包含当前的所有分组
+// @Tags 好友相关接口
+// @Accept json
+// @Produce json
+// @Param order body reorderFriendGroupsRequest true "分组顺序"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/group/order [put]
+func ReorderFriendGroups(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req reorderFriendGroupsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	groups, err := controllers.GetFriendGroupsByUserId(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend groups"})
+		return
+	}
+	owned := make(map[int64]bool, len(groups))
+	for _, group := range groups {
+		owned[group.Id] = true
+	}
+	seen := make(map[int64]bool, len(req.GroupIds))
+	for _, groupId := range req.GroupIds {
+		if !owned[groupId] || seen[groupId] {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "group_ids must list each of your groups exactly once"})
+			return
+		}
+		seen[groupId] = true
+	}
+	if len(seen) != len(owned) {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "group_ids must list each of your groups exactly once"})
+		return
+	}
+
+	if err := controllers.ReorderFriendGroups(jwtUser.Id, req.GroupIds, utils.GetCurrentTime()); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to reorder friend groups"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend groups reordered successfully"})
+}
+
+// @Summary 设置好友分组成员
+// @Description 用给定的好友替换分组中的全部成员，只能加入已互为好友的用户，一个好友可以同时在多个分组中
+// @Tags 好友相关接口
+// @Accept json
+// @Produce json
+// @Param id path int true "分组Id"
+// @Param members body friendGroupMembersRequest true "分组成员"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.FriendGroupInfo
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/group/{id}/member [put]
+func SetFriendGroupMembers(c *gin.Context) {
+	group, jwtUser, ok := loadOwnFriendGroup(c)
+	if !ok {
+		return
+	}
+
+	var req friendGroupMembersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	acceptedIds, err := controllers.GetAcceptedFriendIds(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friends"})
+		return
+	}
+	isFriend := make(map[int64]bool, len(acceptedIds))
+	for _, friendId := range acceptedIds {
+		isFriend[friendId] = true
+	}
+	friendIds := make([]int64, 0, len(req.FriendIds))
+	seen := make(map[int64]bool, len(req.FriendIds))
+	for _, friendId := range req.FriendIds {
+		if seen[friendId] {
+			continue
+		}
+		seen[friendId] = true
+		if !isFriend[friendId] {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: fmt.Sprintf("user %d is not your friend", friendId)})
+			return
+		}
+		friendIds = append(friendIds, friendId)
+	}
+
+	if err := controllers.SetFriendGroupMembers(group, friendIds, utils.GetCurrentTime()); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update friend group members"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.FriendGroupInfo{Id: group.Id, Name: group.Name, SortOrder: group.SortOrder, FriendIds: friendIds})
+}
+
+// @Summary 设置好友备注
+// @Description 给已互为好友的用户设置只有自己可见的备注名，备注为空时清除备注
+// @Tags 好友相关接口
+// @Accept json
+// @Produce json
+// @Param remark body friendRemarkRequest true "备注信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/remark [put]
+func SetFriendRemark(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	var req friendRemarkRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	remark := strings.TrimSpace(req.Remark)
+	if utf8.RuneCountInString(remark) > maxFriendRemarkLength {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "remark is too long"})
+		return
+	}
+	isFriend, err := controllers.AreFriends(jwtUser.Id, req.FriendId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check friendship"})
+		return
+	}
+	if !isFriend {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "you can only set remarks for your friends"})
+		return
+	}
+
+	if err := controllers.SetFriendRemark(jwtUser.Id, req.FriendId, remark, utils.GetCurrentTime()); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to set friend remark"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend remark updated successfully"})
+}
@@ -0,0 +1,179 @@
+package api
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const (
+	maxFriendRequestMessageLength = 100              // 好友申请附言最大长度（字符数）
+	friendRequestExpiryInterval   = 10 * time.Minute // 清理过期好友申请的间隔
+)
+
+// friendRequestTTL 返回配置的好友申请有效期，0表示永不过期
+func friendRequestTTL() time.Duration {
+	days := config.GetConfig().Friend.RequestExpireDays
+	if days <= 0 {
+		return 0
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// friendRequestExpireBefore 返回 now 时刻的过期界限，申请时间早于该时间的待处理申请已过期，永不过期时返回零值
+func friendRequestExpireBefore(now time.Time) time.Time {
+	ttl := friendRequestTTL()
+	if ttl == 0 {
+		return time.Time{}
+	}
+	return now.Add(-ttl)
+}
+
+// StartFriendRequestExpiry 定期将过期的好友申请置为拒绝状态。
+// 接口在处理申请时也会检查是否过期，定期清理只是让好友列表中的状态保持准确
+func StartFriendRequestExpiry() {
+	if friendRequestTTL() == 0 {
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(friendRequestExpiryInterval)
+		defer ticker.Stop()
+		for {
+			expireFriendRequests()
+			<-ticker.C
+		}
+	}()
+}
+
+func expireFriendRequests() {
+	now := utils.GetCurrentTime()
+	expired, err := controllers.ExpireFriendRequests(friendRequestExpireBefore(now), now)
+	if err != nil {
+		log.Printf("清理过期好友申请失败: %v", err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("已将 %d 条过期好友申请置为拒绝", expired)
+	}
+}
+
+// listFriendRequests 分页返回用户收到或发出的未过期好友申请，并计算过期时间
+func listFriendRequests(c *gin.Context, incoming bool) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+
+	ttl := friendRequestTTL()
+	requests, total, err := controllers.GetFriendRequests(jwtUser.Id, incoming, friendRequestExpireBefore(utils.GetCurrentTime()), page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend requests"})
+		return
+	}
+	if ttl > 0 {
+		for i := range requests {
+			expireTime := requests[i].RequestTime.Add(ttl)
+			requests[i].ExpireTime = &expireTime
+		}
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: requests})
+}
+
+// @Summary 获取收到的好友申请
+// @Description 分页获取别人发给自己、尚未处理且未过期的好友申请，按申请时间倒序
+// @Tags 好友相关接口
+// @Produce json
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页条数，默认为10，最大为100"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]models.FriendRequestInfo}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/request/incoming [get]
+func GetIncomingFriendRequests(c *gin.Context) {
+	listFriendRequests(c, true)
+}
+
+// @Summary 获取发出的好友申请
+// @Description 分页获取自己发出、对方尚未处理且未过期的好友申请，按申请时间倒序
+// @Tags 好友相关接口
+// @Produce json
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页条数，默认为10，最大为100"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]models.FriendRequestInfo}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/request/outgoing [get]
+func GetOutgoingFriendRequests(c *gin.Context) {
+	listFriendRequests(c, false)
+}
+
+// @Summary 撤回好友申请
+// @Description 撤回自己发给指定用户、对方尚未处理的好友申请，对方通过 friend_cancelled 事件收到通知
+// @Tags 好友相关接口
+// @Produce json
+// @Param userId path int true "申请对象的用户Id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/request/{userId} [delete]
+func CancelFriendRequest(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+	userId, err := utils.StringToInt64(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid user id"})
+		return
+	}
+
+	now := utils.GetCurrentTime()
+	if _, err := controllers.ApplyFriendAction(jwtUser.Id, userId, models.FriendActionCancel, "", now, friendRequestExpireBefore(now)); err != nil {
+		if errors.Is(err, custom_errors.ErrFriendRequestExpired) {
+			err = custom_errors.ErrFriendRequestNotFound
+		}
+		respondFriendActionError(c, err, "failed to cancel friend request")
+		return
+	}
+	// 对方收到的申请通知不再需要处理
+	if err := controllers.MarkNotificationsReadByRelatedId(userId, models.NotificationFriendRequest, jwtUser.Id); err != nil {
+		log.Printf("将好友申请通知标记为已读失败: %v", err)
+	}
+	utils.ChatHub.Publish(userId, models.WSEvent{Type: models.WSEventFriendCancelled, Data: models.FriendEvent{UserId: jwtUser.Id, Status: models.FriendStatusRejected}})
+
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "friend request cancelled successfully"})
+}
@@ -0,0 +1,250 @@
+package api
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const (
+	defaultSuggestionLimit = 20
+	maxSuggestionLimit     = 50
+
+	suggestionNearbyRadiusKm = 20.0 // 超过该距离不作为推荐理由
+	suggestionCloseRadiusKm  = 5.0  // 该距离内的用户获得更高的分数
+
+	// 各推荐理由的权重，每个共同好友、共同活动或相同兴趣分别计分
+	suggestionMutualFriendScore   = 3
+	suggestionSharedActivityScore = 2
+	suggestionSharedInterestScore = 1
+	suggestionCloseScore          = 2
+	suggestionNearbyScore         = 1
+)
+
+// friendSuggestionCandidate 计算中的推荐对象
+type friendSuggestionCandidate struct {
+	userId        int64
+	mutualFriends int
+	suggestion    models.FriendSuggestion
+}
+
+// addReason 为推荐对象追加理由并累加分数
+func (c *friendSuggestionCandidate) addReason(reason models.SuggestionReason, score int) {
+	c.suggestion.Reasons = append(c.suggestion.Reasons, reason)
+	c.suggestion.Score += score
+}
+
+// buildFriendSuggestions 按共同好友、共同活动、相同兴趣及距离为用户计算推荐，
+// 排除自己、已是好友或有待处理申请的用户、屏蔽关系中的用户及设置了不出现在搜索结果中的用户
+func buildFriendSuggestions(user *models.User, limit int) ([]models.FriendSuggestion, error) {
+	relatedIds, err := controllers.GetFriendRelatedUserIds(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	blockedIds, err := controllers.GetBlockRelatedUserIds(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	excludeIds := append(append([]int64{user.Id}, relatedIds...), blockedIds...)
+	excluded := make(map[int64]bool, len(excludeIds))
+	for _, userId := range excludeIds {
+		excluded[userId] = true
+	}
+
+	candidates := make(map[int64]*friendSuggestionCandidate)
+	candidate := func(userId int64) *friendSuggestionCandidate {
+		if candidates[userId] == nil {
+			candidates[userId] = &friendSuggestionCandidate{userId: userId, suggestion: models.FriendSuggestion{Reasons: []models.SuggestionReason{}}}
+		}
+		return candidates[userId]
+	}
+
+	mutualCounts, err := controllers.GetMutualFriendCounts(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	for userId, count := range mutualCounts {
+		if excluded[userId] {
+			continue
+		}
+		c := candidate(userId)
+		c.mutualFriends = count
+		c.addReason(models.SuggestionReason{
+			Type:  models.SuggestionMutualFriends,
+			Count: count,
+			Text:  fmt.Sprintf("%d 位共同好友", count),
+		}, count*suggestionMutualFriendScore)
+	}
+
+	activityCounts, err := controllers.GetSharedActivityCounts(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	for userId, count := range activityCounts {
+		if excluded[userId] {
+			continue
+		}
+		candidate(userId).addReason(models.SuggestionReason{
+			Type:  models.SuggestionSharedActivities,
+			Count: count,
+			Text:  fmt.Sprintf("一起参加过 %d 个活动", count),
+		}, count*suggestionSharedActivityScore)
+	}
+
+	sharedInterests, err := controllers.GetSharedInterests(user.Id)
+	if err != nil {
+		return nil, err
+	}
+	for userId, tags := range sharedInterests {
+		if excluded[userId] {
+			continue
+		}
+		candidate(userId).addReason(models.SuggestionReason{
+			Type:  models.SuggestionSharedInterests,
+			Count: len(tags),
+			Tags:  tags,
+			Text:  "共同兴趣：" + strings.Join(tags, "、"),
+		}, len(tags)*suggestionSharedInterestScore)
+	}
+
+	// 不出现在搜索结果中的用户也不被推荐
+	settings, err := controllers.GetUserSettings(slices.Sorted(maps.Keys(candidates)))
+	if err != nil {
+		return nil, err
+	}
+	for _, setting := range settings {
+		if setting.HideFromSearch {
+			delete(candidates, setting.UserId)
+		}
+	}
+
+	// 隐藏距离及不出现在搜索结果中的用户由数据库排除，不会产生距离理由
+	usersById := make(map[int64]models.User)
+	if utils.HasLocation(user.Lat, user.Lon) {
+		filter := models.UserSearchFilter{ExcludeIds: excludeIds}
+		setDistanceFilter(&filter, user.Lat, user.Lon, suggestionNearbyRadiusKm)
+		nearbyUsers, err := controllers.GetNearbyUsers(filter)
+		if err != nil {
+			return nil, err
+		}
+		for _, nearby := range nearbyUsers {
+			distance := utils.DistanceKm(user.Lat, user.Lon, nearby.Lat, nearby.Lon)
+			if distance > suggestionNearbyRadiusKm {
+				continue
+			}
+			usersById[nearby.Id] = nearby
+			score := suggestionNearbyScore
+			if distance <= suggestionCloseRadiusKm {
+				score = suggestionCloseScore
+			}
+			distanceKm := approximateDistanceKm(distance)
+			candidate(nearby.Id).addReason(models.SuggestionReason{
+				Type:       models.SuggestionNearby,
+				DistanceKm: distanceKm,
+				Text:       fmt.Sprintf("距离约 %d 公里", distanceKm),
+			}, score)
+		}
+	}
+
+	ranked := make([]*friendSuggestionCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		ranked = append(ranked, c)
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		if ranked[i].suggestion.Score != ranked[j].suggestion.Score {
+			return ranked[i].suggestion.Score > ranked[j].suggestion.Score
+		}
+		if ranked[i].mutualFriends != ranked[j].mutualFriends {
+			return ranked[i].mutualFriends > ranked[j].mutualFriends
+		}
+		return ranked[i].userId < ranked[j].userId
+	})
+
+	// 补充非附近用户的公开信息，已删除的用户不推荐
+	var missingIds []int64
+	for _, c := range ranked[:min(len(ranked), limit)] {
+		if _, ok := usersById[c.userId]; !ok {
+			missingIds = append(missingIds, c.userId)
+		}
+	}
+	users, err := controllers.GetUsersByIds(missingIds)
+	if err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		usersById[u.Id] = u
+	}
+
+	suggestions := make([]models.FriendSuggestion, 0, limit)
+	for _, c := range ranked {
+		if len(suggestions) == limit {
+			break
+		}
+		u, ok := usersById[c.userId]
+		if !ok {
+			continue
+		}
+		c.suggestion.User = models.NewPublicUser(u)
+		suggestions = append(suggestions, c.suggestion)
+	}
+	return suggestions, nil
+}
+
+// @Summary 获取好友推荐
+// @Description 推荐可能认识的用户，按共同好友、一起参加过的活动、相同兴趣标签及距离综合排序，每条推荐附带理由。
+// @Description 已是好友、有待处理好友申请、屏蔽关系中的用户及设置了不出现在搜索结果中的用户不会被推荐。
+// @Description 共同活动只统计公开活动。距离理由只在双方都设置了位置且对方未隐藏距离时出现，且只给出大致公里数。
+// @Tags 好友相关接口
+// @Produce json
+// @Param limit query int false "返回数量，默认为20，最大为50"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {array} models.FriendSuggestion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/suggestions [get]
+func GetFriendSuggestions(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	limit := defaultSuggestionLimit
+	if limitStr := c.Query("limit"); limitStr != "" {
+		limit, err = utils.StringToInt(limitStr)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid limit"})
+			return
+		}
+		limit = min(limit, maxSuggestionLimit)
+	}
+
+	user, err := controllers.GetUserByUserId(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "user not found"})
+		return
+	}
+
+	suggestions, err := buildFriendSuggestions(user, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friend suggestions"})
+		return
+	}
+	c.JSON(http.StatusOK, suggestions)
+}
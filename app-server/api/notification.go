@@ -0,0 +1,288 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+// commentExcerptLength 评论通知中评论摘要的最大字符数
+const commentExcerptLength = 50
+
+type unreadNotificationResponse struct {
+	UnreadCount int64 `json:"unreadCount"` // 未读通知数量
+}
+
+type markAllNotificationsReadResponse struct {
+	Updated int64 `json:"updated"` // 本次标记为已读的通知数量
+}
+
+// notify 保存站内通知，并通过实时连接及离线推送送达接收人。通知失败不影响触发通知的操作，只记录日志
+func notify(notifications []models.Notification) {
+	if len(notifications) == 0 {
+		return
+	}
+	if err := controllers.AddNotifications(notifications); err != nil {
+		log.Printf("保存 %s 通知失败: %v", notifications[0].Type, err)
+		return
+	}
+	for _, notification := range notifications {
+		utils.ChatHub.Publish(notification.UserId, models.WSEvent{Type: models.WSEventNotification, Data: notification})
+	}
+	pushNotifications(notifications)
+}
+
+// actorPayload 获取触发通知的用户信息，用户不存在时只包含用户Id
+func actorPayload(userId int64) models.UserNotificationPayload {
+	payload := models.UserNotificationPayload{UserId: userId}
+	if user, err := controllers.GetUserByUserId(userId); err == nil {
+		payload.Username = user.Username
+		payload.Name = user.Name
+		payload.HeadImg = user.HeadImg
+	}
+	return payload
+}
+
+// payloadDisplayName 通知内容中展示的用户名称，未设置姓名时使用用户名
+func payloadDisplayName(payload models.UserNotificationPayload) string {
+	if payload.Name != "" {
+		return payload.Name
+	}
+	return payload.Username
+}
+
+// notifyFriendEvent 通知用户收到好友申请或好友申请被同意，message 为好友申请附言
+func notifyFriendEvent(userId, actorId int64, notificationType, message string) {
+	payload := models.FriendNotificationPayload{UserNotificationPayload: actorPayload(actorId), Message: message}
+	content := fmt.Sprintf("%s 请求添加你为好友", payloadDisplayName(payload.UserNotificationPayload))
+	if message != "" {
+		content += "：" + message
+	}
+	if notificationType == models.NotificationFriendAccepted {
+		content = fmt.Sprintf("%s 已通过你的好友申请", payloadDisplayName(payload.UserNotificationPayload))
+	}
+	notification := models.Notification{
+		UserId:     userId,
+		Type:       notificationType,
+		Content:    content,
+		RelatedId:  actorId,
+		ActorId:    actorId,
+		CreateTime: utils.GetCurrentTime(),
+	}
+	notification.SetPayload(payload)
+	notify([]models.Notification{notification})
+}
+
+// notifyActivityJoined 通知活动创建者有人加入了活动
+func notifyActivityJoined(activity *models.Activity, userId int64) {
+	if activity.UserId == userId {
+		return
+	}
+	payload := models.ActivityJoinedNotificationPayload{UserNotificationPayload: actorPayload(userId), ActivityName: activity.Name}
+	notification := models.Notification{
+		UserId:     activity.UserId,
+		Type:       models.NotificationActivityJoined,
+		Content:    fmt.Sprintf("%s 加入了活动「%s」", payloadDisplayName(payload.UserNotificationPayload), activity.Name),
+		ActivityId: activity.Id,
+		RelatedId:  userId,
+		ActorId:    userId,
+		CreateTime: utils.GetCurrentTime(),
+	}
+	notification.SetPayload(payload)
+	notify([]models.Notification{notification})
+}
+
+// notifyActivityComment 通知活动创建者活动收到了评论，屏蔽关系中的用户不通知
+func notifyActivityComment(comment *models.ActivityComment) {
+	activity, err := controllers.GetActivityById(comment.ActivityId)
+	if err != nil || activity.UserId == comment.UserId {
+		return
+	}
+	blocked, err := controllers.IsBlockedBetween(activity.UserId, comment.UserId)
+	if err != nil || blocked {
+		return
+	}
+
+	excerpt := comment.Content
+	if utf8.RuneCountInString(excerpt) > commentExcerptLength {
+		excerpt = string([]rune(excerpt)[:commentExcerptLength]) + "…"
+	}
+	payload := models.ActivityCommentNotificationPayload{
+		UserNotificationPayload: actorPayload(comment.UserId),
+		ActivityName:            activity.Name,
+		CommentId:               comment.Id,
+		Excerpt:                 excerpt,
+	}
+	notification := models.Notification{
+		UserId:     activity.UserId,
+		Type:       models.NotificationActivityComment,
+		Content:    fmt.Sprintf("%s 评论了活动「%s」：%s", payloadDisplayName(payload.UserNotificationPayload), activity.Name, excerpt),
+		ActivityId: activity.Id,
+		RelatedId:  comment.Id,
+		ActorId:    comment.UserId,
+		CreateTime: utils.GetCurrentTime(),
+	}
+	notification.SetPayload(payload)
+	notify([]models.Notification{notification})
+}
+
+// @Summary 获取通知
+// @Description 分页获取当前用户收到的通知，按时间倒序，并返回未读通知数量。
+// @Description payload 按通知类型解析：friend_request、friend_accepted 为触发用户信息及申请附言 message，
+// @Description activity_joined 额外包含 activityName，activity_comment 额外包含 activityName、commentId 及评论摘要 excerpt。
+// @Description 新通知也会通过实时连接的 notification 事件推送。
+// @Tags 通知相关接口
+// @Accept json
+// @Produce json
+// @Param unread query bool false "为 true 时只返回未读通知"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页数量，默认为10"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.NotificationPage{items=[]models.Notification}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /v1/notification [get]
+func GetNotifications(c *gin.Context) {
+	// 验证JWT并获取用户Id
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+
+	unreadOnly := c.Query("unread") == "true" || c.Query("unread") == "1"
+
+	notifications, total, err := controllers.GetNotificationsByUserId(jwtUser.Id, unreadOnly, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get notifications"})
+		return
+	}
+	unreadCount, err := controllers.CountUnreadNotifications(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to count unread notifications"})
+		return
+	}
+
+	c.JSON(http.StatusOK, &models.NotificationPage{
+		PageResponse: models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: notifications},
+		UnreadCount:  unreadCount,
+	})
+}
+
+// @Summary 获取未读通知数量
+// @Description 获取当前用户的未读通知数量，用于显示角标
+// @Tags 通知相关接口
+// @Produce json
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} unreadNotificationResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/notification/unread-count [get]
+func GetUnreadNotificationCount(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	unreadCount, err := controllers.CountUnreadNotifications(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to count unread notifications"})
+		return
+	}
+	c.JSON(http.StatusOK, unreadNotificationResponse{UnreadCount: unreadCount})
+}
+
+// @Summary 标记通知已读
+// @Description 将自己的一条通知标记为已读，已读的通知重复标记不报错
+// @Tags 通知相关接口
+// @Produce json
+// @Param id path int true "通知Id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.Notification
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/notification/{id}/read [post]
+func MarkNotificationRead(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+	notificationId, err := utils.StringToInt64(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid notification id"})
+		return
+	}
+
+	notification, err := controllers.MarkNotificationRead(jwtUser.Id, notificationId)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "notification not found"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to mark notification read"})
+		return
+	}
+	c.JSON(http.StatusOK, notification)
+}
+
+// @Summary 全部标记已读
+// @Description 将自己所有未读通知标记为已读
+// @Tags 通知相关接口
+// @Produce json
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} markAllNotificationsReadResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/notification/read-all [post]
+func MarkAllNotificationsRead(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	updated, err := controllers.MarkAllNotificationsRead(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to mark notifications read"})
+		return
+	}
+	c.JSON(http.StatusOK, markAllNotificationsReadResponse{Updated: updated})
+}
@@ -0,0 +1,326 @@
+package api
+
+import (
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+type updatePrivacyRequest struct {
+	HidePresence *bool `json:"hidePresence"` // 不向好友展示在线状态
+	HideLastSeen *bool `json:"hideLastSeen"` // 不向好友展示最近在线时间
+	HideTyping   *bool `json:"hideTyping"`   // 不向对方发送正在输入提示
+
+	HideFromSearch *bool `json:"hideFromSearch"` // 不出现在用户搜索结果中
+	HideDistance   *bool `json:"hideDistance"`   // 不在用户搜索中展示与对方的距离，也不参与按距离筛选
+}
+
+type typingRequest struct {
+	UserId int64 `json:"userId"` // 私聊对象Id，与 roomId 二选一
+	RoomId int64 `json:"roomId"` // 群聊Id，与 userId 二选一
+}
+
+// TrackPresence 携带有效 JWT 的请求都视为用户活动，用于计算在线状态
+func TrackPresence() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if jwtToken := c.GetHeader("Authorization"); jwtToken != "" {
+			if jwtUser, err := utils.ParseJWT(jwtToken); err == nil {
+				touchPresence(jwtUser.Id)
+			}
+		}
+		c.Next()
+	}
+}
+
+// touchPresence 记录用户活动，并按间隔写入最近在线时间
+func touchPresence(userId int64) {
+	now := utils.GetCurrentTime()
+	if !utils.UserPresence.Touch(userId, now) {
+		return
+	}
+	if err := controllers.UpdateUserLastSeen(userId, now); err != nil {
+		log.Printf("更新用户 %d 最近在线时间失败: %v", userId, err)
+	}
+}
+
+// lookupPresences 批量计算用户展示给好友的在线状态，并按各自的隐私设置隐藏
+func lookupPresences(userIds []int64) (map[int64]models.Presence, error) {
+	settings, err := controllers.GetUserSettings(userIds)
+	if err != nil {
+		return nil, err
+	}
+	settingByUser := make(map[int64]models.UserSetting)
+	for _, setting := range settings {
+		settingByUser[setting.UserId] = setting
+	}
+	stored, err := controllers.GetUserPresences(userIds)
+	if err != nil {
+		return nil, err
+	}
+	lastSeenByUser := make(map[int64]models.UserPresence)
+	for _, presence := range stored {
+		lastSeenByUser[presence.UserId] = presence
+	}
+
+	now := utils.GetCurrentTime()
+	presences := make(map[int64]models.Presence)
+	for _, userId := range userIds {
+		setting := settingByUser[userId]
+		presence := models.Presence{UserId: userId, Status: models.PresenceOffline}
+		if !setting.HidePresence {
+			presence.Status = utils.UserPresence.Status(userId, utils.ChatHub.DeviceCount(userId) > 0, now)
+		}
+		if !setting.HideLastSeen {
+			// 本进程内的活动时间比数据库中的更新
+			if lastActive, ok := utils.UserPresence.LastActive(userId); ok {
+				presence.LastSeenTime = &lastActive
+			} else if stored, ok := lastSeenByUser[userId]; ok {
+				presence.LastSeenTime = &stored.LastSeenTime
+			}
+		}
+		presences[userId] = presence
+	}
+	return presences, nil
+}
+
+// publishPresence 将用户当前的在线状态推送给所有在线好友
+func publishPresence(userId int64) {
+	friendIds, err := controllers.GetAcceptedFriendIds(userId)
+	if err != nil {
+		log.Printf("获取用户 %d 的好友失败: %v", userId, err)
+		return
+	}
+	if len(friendIds) == 0 {
+		return
+	}
+	presences, err := lookupPresences([]int64{userId})
+	if err != nil {
+		log.Printf("获取用户 %d 在线状态失败: %v", userId, err)
+		return
+	}
+	event := models.WSEvent{Type: models.WSEventPresence, Data: presences[userId]}
+	for _, friendId := range friendIds {
+		utils.ChatHub.Publish(friendId, event)
+	}
+}
+
+// forwardTyping 将正在输入提示转发给私聊对象或群聊其他成员，返回发送者是否有权向该会话发送
+func forwardTyping(userId int64, typing models.TypingEvent) (bool, error) {
+	var recipients []int64
+	switch {
+	case typing.RoomId != 0:
+		members, err := controllers.GetChatRoomMembers(typing.RoomId)
+		if err != nil {
+			return false, err
+		}
+		isMember := false
+		for _, member := range members {
+			if member.UserId == userId {
+				isMember = true
+			} else {
+				recipients = append(recipients, member.UserId)
+			}
+		}
+		if !isMember {
+			return false, nil
+		}
+	case typing.UserId != 0 && typing.UserId != userId:
+		isFriend, err := controllers.AreFriends(userId, typing.UserId)
+		if err != nil || !isFriend {
+			return false, err
+		}
+		recipients = []int64{typing.UserId}
+	default:
+		return false, nil
+	}
+
+	setting, err := controllers.GetUserSetting(userId)
+	if err != nil {
+		return false, err
+	}
+	if setting.HideTyping {
+		return true, nil
+	}
+	event := models.WSEvent{Type: models.WSEventTyping, Data: models.TypingEvent{UserId: userId, RoomId: typing.RoomId}}
+	for _, recipientId := range recipients {
+		utils.ChatHub.Publish(recipientId, event)
+	}
+	return true, nil
+}
+
+// @Summary 获取好友在线状态
+// @Description 批量获取所有好友的在线状态（online、away、offline）及最近在线时间，按好友的隐私设置隐藏
+// @Tags 好友相关接口
+// @Produce json
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {array} models.Presence
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/presence [get]
+func GetFriendPresences(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	friendIds, err := controllers.GetAcceptedFriendIds(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get friends"})
+		return
+	}
+	presences, err := lookupPresences(friendIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get presence"})
+		return
+	}
+
+	result := make([]models.Presence, 0, len(friendIds))
+	for _, friendId := range friendIds {
+		result = append(result, presences[friendId])
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary 获取隐私设置
+// @Description 获取自己的在线状态、最近在线时间、正在输入提示及用户搜索的隐私设置
+// @Tags 用户相关接口
+// @Produce json
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.UserSetting
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/privacy [get]
+func GetPrivacySetting(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	setting, err := controllers.GetUserSetting(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get privacy setting"})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
+
+// @Summary 修改隐私设置
+// @Description 修改在线状态、最近在线时间、正在输入提示及用户搜索的隐私设置，未传的字段保持不变。修改后立即向好友推送新的在线状态。
+// @Tags 用户相关接口
+// @Accept json
+// @Produce json
+// @Param setting body updatePrivacyRequest true "隐私设置"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.UserSetting
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/privacy [post]
+func UpdatePrivacySetting(c *gin.Context) {
+	var req updatePrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	setting, err := controllers.GetUserSetting(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get privacy setting"})
+		return
+	}
+	if req.HidePresence != nil {
+		setting.HidePresence = *req.HidePresence
+	}
+	if req.HideLastSeen != nil {
+		setting.HideLastSeen = *req.HideLastSeen
+	}
+	if req.HideTyping != nil {
+		setting.HideTyping = *req.HideTyping
+	}
+	if req.HideFromSearch != nil {
+		setting.HideFromSearch = *req.HideFromSearch
+	}
+	if req.HideDistance != nil {
+		setting.HideDistance = *req.HideDistance
+	}
+	setting.UpdateTime = utils.GetCurrentTime()
+	if err := controllers.SaveUserSetting(setting); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update privacy setting"})
+		return
+	}
+
+	publishPresence(jwtUser.Id)
+	c.JSON(http.StatusOK, setting)
+}
+
+// @Summary 发送正在输入提示
+// @Description 通知私聊对象或群聊其他成员自己正在输入，对方通过 typing 事件收到。客户端应在输入期间每隔几秒发送一次，
+// @Description 停止发送即视为结束输入。已建立实时连接时也可直接发送 {"type":"typing","data":{"userId":2}}。
+// @Tags 聊天相关接口
+// @Accept json
+// @Produce json
+// @Param typing body typingRequest true "会话"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/chat/typing [post]
+func SendTyping(c *gin.Context) {
+	var req typingRequest
+	if err := c.ShouldBindJSON(&req); err != nil || (req.UserId == 0) == (req.RoomId == 0) {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "exactly one of userId and roomId is required"})
+		return
+	}
+
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	allowed, err := forwardTyping(jwtUser.Id, models.TypingEvent{UserId: req.UserId, RoomId: req.RoomId})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to send typing indicator"})
+		return
+	}
+	if !allowed {
+		c.JSON(http.StatusForbidden, &models.ErrorResponse{ErrorMessage: "you can only send typing indicators to your friends or chat rooms you joined"})
+		return
+	}
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "typing indicator sent"})
+}
@@ -0,0 +1,335 @@
+package api
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const (
+	maxDeviceTokenLength = 255
+	maxPushBodyLength    = 100 // 推送正文最大字符数，超出部分截断
+)
+
+// devicePlatforms 允许登记的设备平台
+var devicePlatforms = []string{"android", "ios", "harmony", "web"}
+
+type registerDeviceRequest struct {
+	Platform string `json:"platform" binding:"required"` // 设备平台：android、ios、harmony、web
+	Token    string `json:"token" binding:"required"`    // 推送服务下发的设备令牌
+}
+
+type updatePushSettingRequest struct {
+	MuteChatPush       *bool `json:"muteChatPush"`       // 关闭聊天消息推送
+	MuteFriendPush     *bool `json:"muteFriendPush"`     // 关闭好友申请推送
+	MuteInvitationPush *bool `json:"muteInvitationPush"` // 关闭活动邀请推送
+	MuteActivityPush   *bool `json:"muteActivityPush"`   // 关闭活动变更推送
+}
+
+// SetupPush 按配置创建全局推送分发器，失效的令牌会被自动删除
+func SetupPush(cfg config.PushConfig) {
+	var provider utils.PushProvider
+	switch cfg.Provider {
+	case "webhook":
+		if cfg.WebhookURL == "" {
+			log.Printf("未配置 push.webhook_url，不发送离线推送")
+			provider = utils.NoopPushProvider{}
+		} else {
+			provider = utils.NewWebhookPushProvider(cfg.WebhookURL, cfg.WebhookSecret)
+		}
+	case "log":
+		provider = utils.LogPushProvider{}
+	default:
+		provider = utils.NoopPushProvider{}
+	}
+
+	dispatcher := utils.NewPushDispatcher(provider, cfg.Workers, cfg.MaxRetries, time.Duration(cfg.RetryBackoff)*time.Millisecond)
+	dispatcher.OnInvalidToken = func(device models.DeviceToken) {
+		if err := controllers.DeleteDeviceTokenById(device.Id); err != nil {
+			log.Printf("删除失效的推送令牌 %d 失败: %v", device.Id, err)
+		}
+	}
+	utils.Push = dispatcher
+	log.Printf("离线推送使用 %s", provider.Name())
+}
+
+// pushToUser 向没有实时连接的用户的所有设备发送推送，用户关闭了该类推送时不发送。
+// 查询在后台进行，不阻塞请求
+func pushToUser(message models.PushMessage) {
+	if _, ok := utils.Push.Provider().(utils.NoopPushProvider); ok {
+		return
+	}
+	// 在线用户已通过实时连接收到事件
+	if utils.ChatHub.DeviceCount(message.UserId) > 0 {
+		return
+	}
+	go func() {
+		setting, err := controllers.GetUserSetting(message.UserId)
+		if err != nil {
+			log.Printf("获取用户 %d 推送设置失败: %v", message.UserId, err)
+			return
+		}
+		if setting.PushMuted(message.Category) {
+			return
+		}
+		devices, err := controllers.GetDeviceTokens(message.UserId)
+		if err != nil {
+			log.Printf("获取用户 %d 的推送设备失败: %v", message.UserId, err)
+			return
+		}
+		message.Body = truncateRunes(message.Body, maxPushBodyLength)
+		for _, device := range devices {
+			utils.Push.Dispatch(device, message)
+		}
+	}()
+}
+
+// pushNotifications 将站内通知同时推送给离线用户，好友通知按好友类别推送，其余按活动类别推送
+func pushNotifications(notifications []models.Notification) {
+	for _, notification := range notifications {
+		category, title := models.PushCategoryActivity, "活动通知"
+		if notification.Type == models.NotificationFriendRequest || notification.Type == models.NotificationFriendAccepted {
+			category, title = models.PushCategoryFriend, "好友"
+		}
+		data := map[string]string{
+			"type":           notification.Type,
+			"notificationId": strconv.FormatInt(notification.Id, 10),
+		}
+		if notification.ActivityId != 0 {
+			data["activityId"] = strconv.FormatInt(notification.ActivityId, 10)
+		}
+		if notification.ActorId != 0 {
+			data["userId"] = strconv.FormatInt(notification.ActorId, 10)
+		}
+		pushToUser(models.PushMessage{
+			UserId:   notification.UserId,
+			Category: category,
+			Title:    title,
+			Body:     notification.Content,
+			Data:     data,
+		})
+	}
+}
+
+// userDisplayName 推送标题中展示的用户名称，未设置姓名时使用用户名
+func userDisplayName(userId int64) string {
+	if name := payloadDisplayName(actorPayload(userId)); name != "" {
+		return name
+	}
+	return "HobbyHub"
+}
+
+// chatPushBody 聊天消息在推送中展示的内容，非文本消息只展示类型
+func chatPushBody(chat *models.Chat) string {
+	switch chat.MsgType {
+	case models.ChatMsgImage:
+		return "[图片]"
+	case models.ChatMsgFile:
+		return "[文件]"
+	case models.ChatMsgActivity:
+		return "[活动]"
+	case models.ChatMsgLocation:
+		return "[位置]"
+	}
+	return chat.Content
+}
+
+// truncateRunes 按字符数截断字符串
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit]) + "…"
+}
+
+// @Summary 登记推送设备
+// @Description 登记当前设备的推送令牌，没有实时连接时将通过推送服务收到聊天消息、好友申请、活动邀请及活动变更。
+// @Description 同一令牌再次登记时转移给当前用户，退出登录时应删除令牌。
+// @Tags 用户相关接口
+// @Accept json
+// @Produce json
+// @Param device body registerDeviceRequest true "设备信息"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.DeviceToken
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/device [post]
+func RegisterDevice(c *gin.Context) {
+	var req registerDeviceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	req.Token = strings.TrimSpace(req.Token)
+	if req.Token == "" || len(req.Token) > maxDeviceTokenLength {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid device token"})
+		return
+	}
+	validPlatform := false
+	for _, platform := range devicePlatforms {
+		if req.Platform == platform {
+			validPlatform = true
+			break
+		}
+	}
+	if !validPlatform {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid platform"})
+		return
+	}
+
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	device, err := controllers.RegisterDeviceToken(jwtUser.Id, req.Platform, req.Token, utils.GetCurrentTime())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to register device"})
+		return
+	}
+	c.JSON(http.StatusOK, device)
+}
+
+// @Summary 获取推送设备
+// @Description 获取自己登记的所有推送设备，最近登记的在前
+// @Tags 用户相关接口
+// @Produce json
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {array} models.DeviceToken
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/device [get]
+func GetDevices(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	devices, err := controllers.GetDeviceTokens(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get devices"})
+		return
+	}
+	c.JSON(http.StatusOK, devices)
+}
+
+// @Summary 删除推送设备
+// @Description 删除自己登记的推送令牌，该设备之后不再收到推送
+// @Tags 用户相关接口
+// @Produce json
+// @Param token query string true "设备令牌"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/device [delete]
+func DeleteDevice(c *gin.Context) {
+	token := strings.TrimSpace(c.Query("token"))
+	if token == "" {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "token is required"})
+		return
+	}
+
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	deleted, err := controllers.DeleteDeviceToken(jwtUser.Id, token)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to delete device"})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "device not found"})
+		return
+	}
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "device deleted successfully"})
+}
+
+// @Summary 修改推送设置
+// @Description 按类别关闭或开启离线推送（聊天消息、好友申请、活动邀请、活动变更），未传的字段保持不变。
+// @Description 推送设置与隐私设置保存在同一记录中，可通过获取隐私设置接口查看。
+// @Tags 用户相关接口
+// @Accept json
+// @Produce json
+// @Param setting body updatePushSettingRequest true "推送设置"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.UserSetting
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/push-setting [post]
+func UpdatePushSetting(c *gin.Context) {
+	var req updatePushSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	setting, err := controllers.GetUserSetting(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get push setting"})
+		return
+	}
+	if req.MuteChatPush != nil {
+		setting.MuteChatPush = *req.MuteChatPush
+	}
+	if req.MuteFriendPush != nil {
+		setting.MuteFriendPush = *req.MuteFriendPush
+	}
+	if req.MuteInvitationPush != nil {
+		setting.MuteInvitationPush = *req.MuteInvitationPush
+	}
+	if req.MuteActivityPush != nil {
+		setting.MuteActivityPush = *req.MuteActivityPush
+	}
+	setting.UpdateTime = utils.GetCurrentTime()
+	if err := controllers.SaveUserSetting(setting); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update push setting"})
+		return
+	}
+	c.JSON(http.StatusOK, setting)
+}
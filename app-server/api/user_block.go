@@ -0,0 +1,146 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+type blockUserRequest struct {
+	UserId int64 `json:"user_id" binding:"required"` // 要屏蔽的用户Id
+}
+
+// @Summary 屏蔽用户
+// @Description 屏蔽后双方不能互相发送好友申请、私聊消息或活动邀请，也互相看不到对方的活动评论。
+// @Description 屏蔽会解除双方的好友关系并拒绝双方之间待处理的活动邀请，取消屏蔽后好友关系不会自动恢复。
+// @Tags 好友相关接口
+// @Accept json
+// @Produce json
+// @Param block body blockUserRequest true "要屏蔽的用户"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/block [post]
+func BlockUser(c *gin.Context) {
+	var req blockUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	if req.UserId == jwtUser.Id {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "cannot block yourself"})
+		return
+	}
+	if _, err := controllers.GetUserByUserId(req.UserId); err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "user not found"})
+		return
+	}
+
+	if err := controllers.BlockUser(jwtUser.Id, req.UserId, utils.GetCurrentTime()); err != nil {
+		if errors.Is(err, custom_errors.ErrUserAlreadyBlocked) {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "user is already blocked"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to block user"})
+		return
+	}
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "user blocked successfully"})
+}
+
+// @Summary 获取屏蔽列表
+// @Description 分页获取自己屏蔽的用户，按屏蔽时间倒序
+// @Tags 好友相关接口
+// @Produce json
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页条数，默认为10，最大为100"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]models.BlockedUser}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/block [get]
+func GetBlockedUsers(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+
+	blockedUsers, total, err := controllers.GetBlockedUsers(jwtUser.Id, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get blocked users"})
+		return
+	}
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: blockedUsers})
+}
+
+// @Summary 取消屏蔽
+// @Description 取消对指定用户的屏蔽，之前解除的好友关系需要重新申请
+// @Tags 好友相关接口
+// @Produce json
+// @Param userId path int true "被屏蔽的用户Id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/friend/block/{userId} [delete]
+func UnblockUser(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+	blockedUserId, err := utils.StringToInt64(c.Param("userId"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid user id"})
+		return
+	}
+
+	if err := controllers.UnblockUser(jwtUser.Id, blockedUserId); err != nil {
+		if errors.Is(err, custom_errors.ErrUserNotBlocked) {
+			c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "user is not blocked"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to unblock user"})
+		return
+	}
+	c.JSON(http.StatusOK, &models.SuccessResponse{SuccessMessage: "user unblocked successfully"})
+}
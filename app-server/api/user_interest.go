@@ -0,0 +1,130 @@
+package api
+
+import (
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const (
+	maxInterestTags      = 20 // 每个用户最多设置的兴趣标签数量
+	maxInterestTagLength = 20 // 兴趣标签最大长度（字符数）
+)
+
+type updateInterestsRequest struct {
+	Tags []string `json:"tags"` // 兴趣标签，替换原有的全部标签，空数组表示清空
+}
+
+type interestsResponse struct {
+	UserId int64    `json:"userId"`
+	Tags   []string `json:"tags"`
+}
+
+// normalizeInterestTags 去除首尾空白、统一为小写并去重，标签不合法时返回 false
+func normalizeInterestTags(tags []string) ([]string, bool) {
+	if len(tags) > maxInterestTags {
+		return nil, false
+	}
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool)
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || utf8.RuneCountInString(tag) > maxInterestTagLength {
+			return nil, false
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, true
+}
+
+// @Summary 获取兴趣标签
+// @Description 获取指定用户的兴趣标签，未传 userId 时获取自己的
+// @Tags 用户相关接口
+// @Produce json
+// @Param userId query int false "用户Id"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} interestsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/interest [get]
+func GetUserInterests(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	userId := jwtUser.Id
+	if userIdStr := c.Query("userId"); userIdStr != "" {
+		userId, err = utils.StringToInt64(userIdStr)
+		if err != nil || userId < 1 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid user id"})
+			return
+		}
+	}
+
+	tags, err := controllers.GetUserInterests(userId)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get interests"})
+		return
+	}
+	c.JSON(http.StatusOK, interestsResponse{UserId: userId, Tags: tags})
+}
+
+// @Summary 设置兴趣标签
+// @Description 用新的兴趣标签替换自己原有的全部标签，最多20个，每个不超过20个字符，英文统一转为小写。
+// @Description 兴趣标签用于好友推荐及用户搜索。
+// @Tags 用户相关接口
+// @Accept json
+// @Produce json
+// @Param interests body updateInterestsRequest true "兴趣标签"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} interestsResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/interest [put]
+func UpdateUserInterests(c *gin.Context) {
+	var req updateInterestsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid request format"})
+		return
+	}
+	tags, ok := normalizeInterestTags(req.Tags)
+	if !ok {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid interest tags"})
+		return
+	}
+
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	if err := controllers.SetUserInterests(jwtUser.Id, tags, utils.GetCurrentTime()); err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to update interests"})
+		return
+	}
+	c.JSON(http.StatusOK, interestsResponse{UserId: jwtUser.Id, Tags: tags})
+}
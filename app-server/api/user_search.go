@@ -0,0 +1,150 @@
+package api
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gin-gonic/gin"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const (
+	maxUserSearchKeywordLength = 50  // 搜索关键字最大长度（字符数）
+	minUserSearchDistanceKm    = 1   // 按距离筛选的最小半径（公里），只接受整数公里，避免通过小半径反复查询定位对方
+	maxUserSearchDistanceKm    = 100 // 按距离筛选的最大半径（公里）
+)
+
+// approximateDistanceKm 将距离向上取整为公里数，不暴露精确位置
+func approximateDistanceKm(distance float64) int {
+	return max(int(math.Ceil(distance)), 1)
+}
+
+// setDistanceFilter 设置以指定位置为中心、半径为 radiusKm 公里的距离筛选条件
+func setDistanceFilter(filter *models.UserSearchFilter, lat, lon, radiusKm float64) {
+	filter.WithinDistance = true
+	filter.Lat, filter.Lon = lat, lon
+	filter.RadiusDeg = utils.KmToLatDegrees(radiusKm)
+	filter.LonScale = utils.LonScale(lat)
+	filter.MinLat, filter.MaxLat, filter.MinLon, filter.MaxLon = utils.BoundingBox(lat, lon, radiusKm)
+}
+
+// @Summary 搜索用户
+// @Description 按用户名或姓名搜索用户，以关键字开头的排在前面，不传关键字时按注册顺序浏览用户目录。
+// @Description 可按兴趣标签筛选，也可按与自己的距离筛选（需要自己已设置位置），按距离筛选时结果从近到远排列。
+// @Description 设置了不出现在搜索结果中的用户、自己及屏蔽关系中的用户不会出现；对方隐藏距离时不返回距离，也不会出现在按距离筛选的结果中。
+// @Tags 用户相关接口
+// @Produce json
+// @Param q query string false "关键字，匹配用户名或姓名，最多50个字符"
+// @Param tag query string false "兴趣标签"
+// @Param distance query int false "与自己的最大距离（整数公里），1到100"
+// @Param page query int false "页码，默认为1"
+// @Param pageSize query int false "每页条数，默认为10，最大为100"
+// @Param Authorization header string true "JWT Token"
+// @Success 200 {object} models.PageResponse{items=[]models.UserSearchResult}
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /v1/user/search [get]
+func SearchUsers(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+	page, pageSize, err := utils.ParsePagination(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: err.Error()})
+		return
+	}
+
+	filter := models.UserSearchFilter{
+		Keyword: strings.TrimSpace(c.Query("q")),
+		Tag:     strings.ToLower(strings.TrimSpace(c.Query("tag"))),
+	}
+	if utf8.RuneCountInString(filter.Keyword) > maxUserSearchKeywordLength {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "keyword is too long"})
+		return
+	}
+	var radiusKm float64
+	if distanceStr := c.Query("distance"); distanceStr != "" {
+		distanceKm, err := strconv.Atoi(distanceStr)
+		if err != nil || distanceKm < minUserSearchDistanceKm || distanceKm > maxUserSearchDistanceKm {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid distance"})
+			return
+		}
+		radiusKm = float64(distanceKm)
+	}
+
+	user, err := controllers.GetUserByUserId(jwtUser.Id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, &models.ErrorResponse{ErrorMessage: "user not found"})
+		return
+	}
+	if radiusKm > 0 && !utils.HasLocation(user.Lat, user.Lon) {
+		c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "set your location before searching by distance"})
+		return
+	}
+
+	// 排除自己及屏蔽关系中的用户
+	blockedIds, err := controllers.GetBlockRelatedUserIds(user.Id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to check block status"})
+		return
+	}
+	filter.ExcludeIds = append(blockedIds, user.Id)
+	if radiusKm > 0 {
+		setDistanceFilter(&filter, user.Lat, user.Lon, radiusKm)
+	}
+
+	users, total, err := controllers.SearchUsers(filter, page, pageSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to search users"})
+		return
+	}
+
+	userIds := make([]int64, 0, len(users))
+	for _, u := range users {
+		userIds = append(userIds, u.Id)
+	}
+	interests, err := controllers.GetUserInterestsByUserIds(userIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get user interests"})
+		return
+	}
+	settings, err := controllers.GetUserSettings(userIds)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, &models.ErrorResponse{ErrorMessage: "failed to get privacy setting"})
+		return
+	}
+	hideDistance := make(map[int64]bool, len(settings))
+	for _, setting := range settings {
+		hideDistance[setting.UserId] = setting.HideDistance
+	}
+
+	results := make([]models.UserSearchResult, 0, len(users))
+	for _, u := range users {
+		result := models.UserSearchResult{User: models.NewPublicUser(u), Tags: interests[u.Id]}
+		if result.Tags == nil {
+			result.Tags = []string{}
+		}
+		if utils.HasLocation(user.Lat, user.Lon) && utils.HasLocation(u.Lat, u.Lon) && !hideDistance[u.Id] {
+			distanceKm := approximateDistanceKm(utils.DistanceKm(user.Lat, user.Lon, u.Lat, u.Lon))
+			result.DistanceKm = &distanceKm
+		}
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, &models.PageResponse{Total: total, Page: page, PageSize: pageSize, Items: results})
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchUsersInvalidDistance(t *testing.T) {
+	mock, teardown := setupMockDB(t)
+	defer teardown()
+
+	// 只接受1到100的整数公里，避免通过极小半径定位对方
+	for _, distance := range []string{"0", "0.01", "1.5", "-1", "101", "abc"} {
+		expectJWTUser(mock, 2)
+		c, recorder := newTestContext(t, http.MethodGet, "", 2, nil)
+		c.Request.URL.RawQuery = "distance=" + distance
+		SearchUsers(c)
+		assert.Equal(t, http.StatusBadRequest, recorder.Code, distance)
+	}
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
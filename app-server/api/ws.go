@@ -0,0 +1,206 @@
+package api
+
+import (
+	"encoding/json"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+
+	"hobbyhub-server/controllers"
+	"hobbyhub-server/models"
+	"hobbyhub-server/utils"
+)
+
+const (
+	wsPingInterval  = 30 * time.Second // 服务端发送心跳的间隔
+	wsReadTimeout   = 75 * time.Second // 超过该时间未收到客户端任何消息则断开
+	wsWriteTimeout  = 10 * time.Second
+	wsBackfillLimit = 200 // 重连时最多补发的消息数量
+)
+
+// @Summary 建立实时推送连接
+// @Description 升级为 WebSocket 连接，推送新消息、消息删除、好友申请及站内通知事件。浏览器无法设置请求头时可通过 token 参数传递 JWT。
+// @Description 服务端每30秒发送 {"type":"ping"}，客户端也可发送 {"type":"ping"} 并收到 {"type":"pong"}，75秒内无任何消息将断开连接。
+// @Description 传入 last_id 时会先补发该Id之后的私聊消息，随后发送 backfill_done 事件。
+// @Description 客户端可发送 {"type":"presence","data":{"status":"away"}} 切换为离开（online 恢复），
+// @Description 发送 {"type":"typing","data":{"userId":2}} 或 {"type":"typing","data":{"roomId":3}} 发送正在输入提示。
+// @Tags 聊天相关接口
+// @Param Authorization header string false "JWT Token"
+// @Param token query string false "JWT Token，未设置请求头时使用"
+// @Param last_id query int false "客户端已收到的最后一条私聊消息Id"
+// @Success 101 {object} models.WSEvent
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Router /v1/ws [get]
+func ServeWebSocket(c *gin.Context) {
+	jwtToken := c.GetHeader("Authorization")
+	if jwtToken == "" {
+		jwtToken = c.Query("token")
+	}
+	if jwtToken == "" {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "jwt token is required"})
+		return
+	}
+	jwtUser, err := utils.ParseJWT(jwtToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, &models.ErrorResponse{ErrorMessage: "invalid jwt token"})
+		return
+	}
+
+	// 未传 last_id 时不补发
+	lastId := int64(-1)
+	if lastIdStr := c.Query("last_id"); lastIdStr != "" {
+		lastId, err = utils.StringToInt64(lastIdStr)
+		if err != nil || lastId < 0 {
+			c.JSON(http.StatusBadRequest, &models.ErrorResponse{ErrorMessage: "invalid last_id"})
+			return
+		}
+	}
+
+	server := websocket.Server{Handler: func(conn *websocket.Conn) {
+		serveWebSocketConn(conn, jwtUser.Id, lastId)
+	}}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// wsClientEvent 客户端发送的事件，Data 按事件类型解析
+type wsClientEvent struct {
+	Type string          `json:"type"`
+	Data json.RawMessage `json:"data"`
+}
+
+// serveWebSocketConn 处理单个连接：登记到连接中心、补发消息，并负责心跳与读写
+func serveWebSocketConn(conn *websocket.Conn, userId, lastId int64) {
+	defer conn.Close()
+
+	// 先登记再补发，避免补发期间产生的新消息丢失，客户端按消息Id去重
+	client := utils.ChatHub.Register(userId)
+	touchPresence(userId)
+	if utils.ChatHub.DeviceCount(userId) == 1 {
+		publishPresence(userId)
+	}
+	defer func() {
+		utils.ChatHub.Unregister(client)
+		// 最后一个设备断开时立即记录最近在线时间并通知好友离线
+		if utils.ChatHub.DeviceCount(userId) == 0 {
+			now := utils.GetCurrentTime()
+			utils.UserPresence.Disconnect(userId, now)
+			if err := controllers.UpdateUserLastSeen(userId, now); err != nil {
+				log.Printf("更新用户 %d 最近在线时间失败: %v", userId, err)
+			}
+			publishPresence(userId)
+		}
+	}()
+
+	if lastId >= 0 {
+		if err := backfillChats(conn, userId, lastId); err != nil {
+			log.Printf("用户 %d 补发消息失败: %v", userId, err)
+			return
+		}
+	}
+
+	done := make(chan struct{})
+	pong := make(chan struct{}, 1)
+	go writeWebSocketConn(conn, client, pong, done)
+	defer close(done)
+
+	for {
+		conn.SetReadDeadline(time.Now().Add(wsReadTimeout))
+		var event wsClientEvent
+		if err := websocket.JSON.Receive(conn, &event); err != nil {
+			return
+		}
+		touchPresence(userId)
+		handleClientEvent(userId, event, pong)
+	}
+}
+
+// handleClientEvent 处理客户端发送的心跳、在线状态切换及正在输入事件，格式错误的事件直接忽略
+func handleClientEvent(userId int64, event wsClientEvent, pong chan<- struct{}) {
+	switch event.Type {
+	case models.WSEventPing:
+		select {
+		case pong <- struct{}{}:
+		default:
+		}
+	case models.WSEventPresence:
+		var presence models.PresenceEvent
+		if err := json.Unmarshal(event.Data, &presence); err != nil {
+			return
+		}
+		if presence.Status != models.PresenceOnline && presence.Status != models.PresenceAway {
+			return
+		}
+		if utils.UserPresence.SetAway(userId, presence.Status == models.PresenceAway) {
+			publishPresence(userId)
+		}
+	case models.WSEventTyping:
+		var typing models.TypingEvent
+		if err := json.Unmarshal(event.Data, &typing); err != nil {
+			return
+		}
+		if _, err := forwardTyping(userId, typing); err != nil {
+			log.Printf("转发用户 %d 的正在输入提示失败: %v", userId, err)
+		}
+	}
+}
+
+// writeWebSocketConn 将连接中心推送的事件及心跳写入连接，所有写操作都在此协程中完成
+func writeWebSocketConn(conn *websocket.Conn, client *utils.WSClient, pong <-chan struct{}, done <-chan struct{}) {
+	ticker := time.NewTicker(wsPingInterval)
+	defer ticker.Stop()
+
+	for {
+		var err error
+		select {
+		case data, ok := <-client.Send:
+			if !ok {
+				// 连接被连接中心断开
+				conn.Close()
+				return
+			}
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			err = websocket.Message.Send(conn, string(data))
+		case <-pong:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			err = websocket.JSON.Send(conn, models.WSEvent{Type: models.WSEventPong})
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			err = websocket.JSON.Send(conn, models.WSEvent{Type: models.WSEventPing})
+		case <-done:
+			return
+		}
+		if err != nil {
+			conn.Close()
+			return
+		}
+	}
+}
+
+// backfillChats 补发 lastId 之后的私聊消息
+func backfillChats(conn *websocket.Conn, userId, lastId int64) error {
+	chats, err := controllers.GetChatsAfterId(userId, lastId, wsBackfillLimit+1)
+	if err != nil {
+		return err
+	}
+	hasMore := len(chats) > wsBackfillLimit
+	if hasMore {
+		chats = chats[:wsBackfillLimit]
+	}
+	markChatsDelivered(userId, chats)
+	attachChatPreviews(chats)
+	for _, chat := range chats {
+		conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+		if err := websocket.JSON.Send(conn, models.WSEvent{Type: models.WSEventChatMessage, Data: chat}); err != nil {
+			return err
+		}
+	}
+	conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+	return websocket.JSON.Send(conn, models.WSEvent{
+		Type: models.WSEventBackfillDone,
+		Data: models.BackfillDoneEvent{Count: len(chats), HasMore: hasMore},
+	})
+}
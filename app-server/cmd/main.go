@@ -53,34 +53,82 @@ func main() {
 		return
 	}
 
-	r := gin.Default()
+	api.SetupPush(config.GetConfig().Push)
+	api.StartFriendRequestExpiry()
+
+	// 实时推送连接可能在查询参数中携带JWT，不记录其访问日志以免凭证写入日志文件
+	r := gin.New()
+	r.Use(gin.LoggerWithConfig(gin.LoggerConfig{SkipPaths: []string{"/api/v1/ws"}}), gin.Recovery())
 
 	// 设置路由前缀 /api/v1
-	apiV1 := r.Group("/api/v1")
+	apiV1 := r.Group("/api/v1", api.TrackPresence())
 	{
 		//login
 		apiV1.POST("/login", api.UserLogin)
+		// 实时推送
+		apiV1.GET("/ws", api.ServeWebSocket)
 		// User routes
 		user := apiV1.Group("/user")
 		{
-			user.GET("/", api.GetUserInfo)     // 获取用户信息
-			user.PUT("/", api.UserRegister)    // 注册用户
-			user.POST("/", api.UpdateUserInfo) // 更新用户信息
+			user.GET("/", api.GetUserInfo)                    // 获取用户信息
+			user.PUT("/", api.UserRegister)                   // 注册用户
+			user.POST("/", api.UpdateUserInfo)                // 更新用户信息
+			user.GET("/privacy", api.GetPrivacySetting)       // 获取隐私设置
+			user.POST("/privacy", api.UpdatePrivacySetting)   // 修改隐私设置
+			user.POST("/push-setting", api.UpdatePushSetting) // 修改推送设置
+			user.POST("/device", api.RegisterDevice)          // 登记推送设备
+			user.GET("/device", api.GetDevices)               // 获取推送设备
+			user.DELETE("/device", api.DeleteDevice)          // 删除推送设备
+			user.GET("/interest", api.GetUserInterests)       // 获取兴趣标签
+			user.PUT("/interest", api.UpdateUserInterests)    // 设置兴趣标签
+			user.GET("/search", api.SearchUsers)              // 搜索用户
 		}
 		// Chat routes
 		chat := apiV1.Group("/chat")
 		{
-			chat.GET("/", api.GetChatHistory)   // 获取聊天记录
-			chat.POST("/", api.SendChat)        // 发送聊天消息
-			chat.DELETE("/:id", api.DeleteChat) // 删除聊天记录
+			chat.GET("/", api.GetChatHistory)                                      // 获取聊天记录
+			chat.POST("/", api.SendChat)                                           // 发送聊天消息
+			chat.DELETE("/:id", api.DeleteChat)                                    // 删除聊天记录
+			chat.PUT("/:id", api.EditChat)                                         // 编辑聊天消息
+			chat.POST("/:id/recall", api.RecallChat)                               // 撤回聊天消息
+			chat.GET("/:id/edits", api.GetChatEdits)                               // 获取消息编辑历史
+			chat.POST("/read", api.MarkChatRead)                                   // 标记消息已读
+			chat.GET("/unread", api.GetChatUnreadCount)                            // 获取未读消息数
+			chat.GET("/conversations", api.GetChatConversations)                   // 获取会话列表
+			chat.GET("/search", api.SearchChats)                                   // 搜索聊天记录
+			chat.POST("/typing", api.SendTyping)                                   // 发送正在输入提示
+			chat.POST("/room", api.CreateChatRoom)                                 // 创建群聊
+			chat.GET("/room", api.GetChatRooms)                                    // 获取我的群聊
+			chat.GET("/room/:id", api.GetChatRoom)                                 // 获取群聊详情
+			chat.POST("/room/:id", api.UpdateChatRoom)                             // 修改群聊名称
+			chat.PUT("/room/:id/member", api.AddChatRoomMembers)                   // 添加群成员
+			chat.DELETE("/room/:id/member/:userId", api.RemoveChatRoomMember)      // 移除群成员或退出群聊
+			chat.GET("/room/:id/message", api.GetChatRoomMessages)                 // 获取群聊记录
+			chat.POST("/room/:id/message", api.SendChatRoomMessage)                // 发送群消息
+			chat.DELETE("/room/:id/message/:messageId", api.DeleteChatRoomMessage) // 删除群消息
 		}
 		// Friend routes
 		friend := apiV1.Group("/friend")
 		{
-			friend.GET("/", api.GetFriendList)      // 获取好友列表
-			friend.POST("/", api.SendFriendRequest) // 发送好友申请
-			friend.PUT("/", api.UpdateFriendStatus) // 更新好友申请状态
-			friend.DELETE("/:id", api.DeleteFriend) // 删除好友
+			friend.GET("/", api.GetFriendList)                             // 获取好友列表
+			friend.GET("/presence", api.GetFriendPresences)                // 获取好友在线状态
+			friend.POST("/block", api.BlockUser)                           // 屏蔽用户
+			friend.GET("/block", api.GetBlockedUsers)                      // 获取屏蔽列表
+			friend.DELETE("/block/:userId", api.UnblockUser)               // 取消屏蔽
+			friend.GET("/suggestions", api.GetFriendSuggestions)           // 获取好友推荐
+			friend.POST("/", api.SendFriendRequest)                        // 发送好友申请
+			friend.PUT("/", api.UpdateFriendStatus)                        // 更新好友申请状态
+			friend.GET("/request/incoming", api.GetIncomingFriendRequests) // 获取收到的好友申请
+			friend.GET("/request/outgoing", api.GetOutgoingFriendRequests) // 获取发出的好友申请
+			friend.DELETE("/request/:userId", api.CancelFriendRequest)     // 撤回好友申请
+			friend.GET("/group", api.GetFriendGroups)                      // 获取好友分组
+			friend.POST("/group", api.CreateFriendGroup)                   // 创建好友分组
+			friend.PUT("/group/order", api.ReorderFriendGroups)            // 调整好友分组顺序
+			friend.PUT("/group/:id", api.UpdateFriendGroup)                // 重命名好友分组
+			friend.DELETE("/group/:id", api.DeleteFriendGroup)             // 删除好友分组
+			friend.PUT("/group/:id/member", api.SetFriendGroupMembers)     // 设置好友分组成员
+			friend.PUT("/remark", api.SetFriendRemark)                     // 设置好友备注
+			friend.DELETE("/:id", api.DeleteFriend)                        // 删除好友
 		}
 		// File routes
 		file := apiV1.Group("/file")
@@ -92,18 +140,57 @@ func main() {
 		// Activity routes
 		activity := apiV1.Group("/activity")
 		{
-			activity.GET("/:id", api.GetActivitie)                            // 获取活动列表
-			activity.GET("/member", api.GetUserActivities)                    // 获取用户参加的活动
-			activity.GET("/", api.GetAllActivitie)                            // 获取活动详情
-			activity.PUT("/", api.CreateActivity)                             // 新建活动
-			activity.POST("/:id", api.UpdateActivity)                         // 更新活动信息
-			activity.DELETE("/:id", api.DeleteActivity)                       // 软删除活动
-			activity.GET("/:id/member", api.GetActivityMembers)               // 获取活动成员列表
-			activity.PUT("/:id/member", api.JoinActivity)                     // 添加活动成员
-			activity.DELETE("/:id/member", api.LeaveActivity)                 // 退出活动
-			activity.GET("/:id/comment", api.GetActivityComments)             // 获取活动评论
-			activity.PUT("/:id/comment", api.AddActivityComment)              // 添加活动评论
-			activity.DELETE("/comment/:commentId", api.DeleteActivityComment) // 删除活动评论
+			activity.GET("/:id", api.GetActivitie)                                                        // 获取活动列表
+			activity.GET("/member", api.GetUserActivities)                                                // 获取用户参加的活动
+			activity.GET("/", api.GetAllActivitie)                                                        // 获取活动详情
+			activity.PUT("/", api.CreateActivity)                                                         // 新建活动
+			activity.POST("/:id", api.UpdateActivity)                                                     // 更新活动信息
+			activity.DELETE("/:id", api.DeleteActivity)                                                   // 软删除活动
+			activity.GET("/:id/member", api.GetActivityMembers)                                           // 获取活动成员列表
+			activity.PUT("/:id/member", api.JoinActivity)                                                 // 添加活动成员
+			activity.DELETE("/:id/member", api.LeaveActivity)                                             // 退出活动
+			activity.POST("/:id/member/:userId/role", api.UpdateActivityMemberRole)                       // 修改成员角色
+			activity.POST("/:id/owner", api.TransferActivityOwnership)                                    // 转让活动
+			activity.GET("/:id/comment", api.GetActivityComments)                                         // 获取活动评论
+			activity.PUT("/:id/comment", api.AddActivityComment)                                          // 添加活动评论
+			activity.DELETE("/comment/:commentId", api.DeleteActivityComment)                             // 删除活动评论
+			activity.GET("/:id/photo", api.GetActivityPhotos)                                             // 获取活动相册
+			activity.PUT("/:id/photo", api.AddActivityPhoto)                                              // 添加相册图片
+			activity.PUT("/:id/photo/order", api.SortActivityPhotos)                                      // 调整相册顺序
+			activity.POST("/:id/photo/:photoId", api.UpdateActivityPhoto)                                 // 修改相册图片
+			activity.DELETE("/:id/photo/:photoId", api.DeleteActivityPhoto)                               // 删除相册图片
+			activity.PUT("/:id/cover", api.SetActivityCover)                                              // 设置活动封面
+			activity.GET("/invitation", api.GetActivityInvitations)                                       // 获取收到的活动邀请
+			activity.POST("/invitation/:invitationId", api.RespondActivityInvitation)                     // 处理活动邀请
+			activity.PUT("/:id/invitation", api.InviteFriendsToActivity)                                  // 邀请好友参加活动
+			activity.GET("/:id/invitation/stats", api.GetActivityInvitationStats)                         // 获取活动邀请统计
+			activity.GET("/:id/request", api.GetActivityJoinRequests)                                     // 获取入团申请
+			activity.POST("/:id/request/:requestId", api.ReviewActivityJoinRequest)                       // 审批入团申请
+			activity.GET("/:id/expense", api.GetActivityExpenses)                                         // 获取费用记录
+			activity.PUT("/:id/expense", api.AddActivityExpense)                                          // 记录费用
+			activity.GET("/:id/expense/balance", api.GetActivityExpenseBalances)                          // 获取费用余额与结算建议
+			activity.GET("/:id/settlement", api.GetActivitySettlements)                                   // 获取结算记录
+			activity.PUT("/:id/settlement", api.AddActivitySettlement)                                    // 标记结算已付
+			activity.GET("/:id/poll", api.GetActivityPoll)                                                // 获取时间投票
+			activity.PUT("/:id/poll", api.CreateActivityPoll)                                             // 发起时间投票
+			activity.POST("/:id/poll/vote", api.VoteActivityPoll)                                         // 参与时间投票
+			activity.POST("/:id/poll/finalize", api.FinalizeActivityPoll)                                 // 确定活动时间
+			activity.GET("/:id/history", api.GetActivityHistory)                                          // 获取活动修改历史
+			activity.GET("/:id/announcement", api.GetActivityAnnouncements)                               // 获取活动公告
+			activity.PUT("/:id/announcement", api.AddActivityAnnouncement)                                // 发布活动公告
+			activity.POST("/:id/announcement/:announcementId", api.UpdateActivityAnnouncement)            // 修改活动公告
+			activity.DELETE("/:id/announcement/:announcementId", api.DeleteActivityAnnouncement)          // 删除活动公告
+			activity.GET("/:id/announcement/:announcementId/read", api.GetActivityAnnouncementReadStatus) // 获取公告阅读情况
+			activity.POST("/:id/announcement/:announcementId/read", api.MarkActivityAnnouncementRead)     // 标记公告已读
+			activity.GET("/:id/chat", api.GetActivityChatRoom)                                            // 获取活动群聊
+		}
+		// Notification routes
+		notification := apiV1.Group("/notification")
+		{
+			notification.GET("/", api.GetNotifications)                       // 获取通知
+			notification.GET("/unread-count", api.GetUnreadNotificationCount) // 获取未读通知数量
+			notification.POST("/:id/read", api.MarkNotificationRead)          // 标记通知已读
+			notification.POST("/read-all", api.MarkAllNotificationsRead)      // 全部标记已读
 		}
 		// Admin routes
 		admin := apiV1.Group("/admin")
@@ -0,0 +1,87 @@
+package config
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// 聊天记录的全文检索方式
+const (
+	ChatSearchLike     = "like"     // 未建立全文索引时退化为 LIKE 匹配
+	ChatSearchFTS5     = "fts5"     // SQLite FTS5 外部内容表，trigram 分词
+	ChatSearchFulltext = "fulltext" // MySQL FULLTEXT 索引，ngram 分词
+)
+
+// ChatSearchMode 当前使用的检索方式，由 InitDatabase 根据数据库类型设置
+var ChatSearchMode = ChatSearchLike
+
+// setupChatSearch 为聊天内容建立全文索引，失败时记录日志并退化为 LIKE 匹配
+func setupChatSearch(db *gorm.DB, dbType string) {
+	var err error
+	switch dbType {
+	case "mysql":
+		err = setupMySQLChatFulltext(db)
+		if err == nil {
+			ChatSearchMode = ChatSearchFulltext
+		}
+	case "sqlite":
+		err = setupSQLiteChatFTS(db)
+		if err == nil {
+			ChatSearchMode = ChatSearchFTS5
+		}
+	}
+	if err != nil {
+		log.Printf("建立聊天全文索引失败，聊天搜索将使用 LIKE 匹配: %v", err)
+		ChatSearchMode = ChatSearchLike
+		return
+	}
+	log.Printf("聊天搜索使用 %s 全文索引", ChatSearchMode)
+}
+
+// setupMySQLChatFulltext 为 chat.content 建立使用 ngram 分词的 FULLTEXT 索引，以支持中文
+func setupMySQLChatFulltext(db *gorm.DB) error {
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM information_schema.statistics WHERE table_schema = DATABASE() AND table_name = 'chat' AND index_name = 'ft_chat_content'").
+		Scan(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	return db.Exec("ALTER TABLE chat ADD FULLTEXT INDEX ft_chat_content (content) WITH PARSER ngram").Error
+}
+
+// setupSQLiteChatFTS 创建以 chat 为外部内容的 FTS5 表及同步触发器。
+// FTS5 需要以 -tags sqlite_fts5 编译 go-sqlite3，否则返回错误
+func setupSQLiteChatFTS(db *gorm.DB) error {
+	var count int64
+	if err := db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'chat_fts'").
+		Scan(&count).Error; err != nil {
+		return err
+	}
+	created := count == 0
+
+	statements := []string{
+		"CREATE VIRTUAL TABLE IF NOT EXISTS chat_fts USING fts5(content, content='chat', content_rowid='id', tokenize='trigram')",
+		"CREATE TRIGGER IF NOT EXISTS chat_fts_ai AFTER INSERT ON chat BEGIN " +
+			"INSERT INTO chat_fts(rowid, content) VALUES (new.id, new.content); END",
+		"CREATE TRIGGER IF NOT EXISTS chat_fts_ad AFTER DELETE ON chat BEGIN " +
+			"INSERT INTO chat_fts(chat_fts, rowid, content) VALUES ('delete', old.id, old.content); END",
+		"CREATE TRIGGER IF NOT EXISTS chat_fts_au AFTER UPDATE OF content ON chat BEGIN " +
+			"INSERT INTO chat_fts(chat_fts, rowid, content) VALUES ('delete', old.id, old.content); " +
+			"INSERT INTO chat_fts(rowid, content) VALUES (new.id, new.content); END",
+	}
+	for _, statement := range statements {
+		if err := db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("%s: %v", statement, err)
+		}
+	}
+
+	// 首次创建时为已有消息建立索引
+	if created {
+		return db.Exec("INSERT INTO chat_fts(chat_fts) VALUES ('rebuild')").Error
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hobbyhub-server/models"
+)
+
+// legacyFriendBackupTable 迁移完成后旧版 friend 表重命名为该表，保留原始数据备查
+const legacyFriendBackupTable = "friend_legacy"
+
+// migrateLegacyFriends 将旧版每对好友两条记录的 friend 表合并为 friendship 表中的一条记录。
+// friendship 表已有数据时不再导入，避免重复迁移；完成后重命名旧表，之后启动不会再执行
+func migrateLegacyFriends(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.LegacyFriend{}) {
+		return nil
+	}
+
+	var count int64
+	if err := db.Model(&models.Friendship{}).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		var legacy []models.LegacyFriend
+		if err := db.Order("id ASC").Find(&legacy).Error; err != nil {
+			return err
+		}
+		friendships := models.MergeLegacyFriends(legacy, time.Now())
+		if len(friendships) > 0 {
+			if err := db.Transaction(func(tx *gorm.DB) error {
+				return tx.CreateInBatches(friendships, 200).Error
+			}); err != nil {
+				return err
+			}
+		}
+		log.Printf("已将 %d 条旧版好友记录合并为 %d 条好友关系", len(legacy), len(friendships))
+	}
+
+	return migrator.RenameTable(models.LegacyFriend{}.TableName(), legacyFriendBackupTable)
+}
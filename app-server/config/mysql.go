@@ -94,12 +94,39 @@ func InitDatabase(conf *Config) error {
 	// 自动迁移所有模型
 	err = DB.AutoMigrate(
 		&models.User{},
-		&models.Friend{},
+		&models.UserPresence{},
+		&models.UserSetting{},
+		&models.UserBlock{},
+		&models.DeviceToken{},
+		&models.UserInterest{},
+		&models.Friendship{},
+		&models.FriendGroup{},
+		&models.FriendGroupMember{},
+		&models.FriendRemark{},
 		&models.File{},
 		&models.Chat{},
+		&models.ChatEdit{},
 		&models.Activity{},
 		&models.ActivityMember{},
 		&models.ActivityComment{},
+		&models.ActivityPhoto{},
+		&models.ActivityInvitation{},
+		&models.ActivityJoinRequest{},
+		&models.ActivityExpense{},
+		&models.ActivityExpenseShare{},
+		&models.ActivitySettlement{},
+		&models.ActivityPoll{},
+		&models.ActivityPollOption{},
+		&models.ActivityPollVote{},
+		&models.Notification{},
+		&models.ActivityRevision{},
+		&models.ActivityRevisionField{},
+		&models.ActivityAnnouncement{},
+		&models.ActivityAnnouncementRead{},
+		&models.ChatRoom{},
+		&models.ChatRoomMember{},
+		&models.ChatRoomMessage{},
+		&models.ChatRoomMessageDeletion{},
 		&models.Admin{},
 	)
 
@@ -108,5 +135,18 @@ func InitDatabase(conf *Config) error {
 	}
 	log.Println("所有模型已成功迁移到数据库")
 
+	// 旧版每个活动只能有一个时间投票，删除原来的唯一索引
+	if DB.Migrator().HasIndex(&models.ActivityPoll{}, "idx_activity_poll_activity_id") {
+		if err := DB.Migrator().DropIndex(&models.ActivityPoll{}, "idx_activity_poll_activity_id"); err != nil {
+			return fmt.Errorf("删除时间投票唯一索引失败: %v", err)
+		}
+	}
+
+	if err := migrateLegacyFriends(DB); err != nil {
+		return fmt.Errorf("迁移旧版好友数据失败: %v", err)
+	}
+
+	setupChatSearch(DB, conf.Database.Type)
+
 	return nil
 }
@@ -26,16 +26,37 @@ type AuthenticationConfig struct {
 }
 
 type FileConfig struct {
-	UploadPath   string   `yaml:"upload_path"`   // 文件上传路径
-	MaxSize      int64    `yaml:"max_size"`      // 最大文件大小，单位为字节
-	AllowedTypes []string `yaml:"allowed_types"` // 允许的文件类型
+	UploadPath    string   `yaml:"upload_path"`    // 文件上传路径
+	MaxSize       int64    `yaml:"max_size"`       // 最大文件大小，单位为字节
+	AllowedTypes  []string `yaml:"allowed_types"`  // 允许的文件类型
+	ThumbnailSize int      `yaml:"thumbnail_size"` // 缩略图最长边像素
+}
+
+type ChatConfig struct {
+	RecallWindow int `yaml:"recall_window"` // 消息发出后可撤回和编辑的时长，单位为秒
+}
+
+type FriendConfig struct {
+	RequestExpireDays int `yaml:"request_expire_days"` // 好友申请超过该天数未处理则自动过期，0表示永不过期
+}
+
+type PushConfig struct {
+	Provider      string `yaml:"provider"`       // 推送方式：none（不推送）、log（仅记录日志）、webhook
+	WebhookURL    string `yaml:"webhook_url"`    // webhook 推送地址
+	WebhookSecret string `yaml:"webhook_secret"` // 设置后以 HMAC-SHA256 对请求体签名
+	MaxRetries    int    `yaml:"max_retries"`    // 发送失败后的最大重试次数
+	RetryBackoff  int    `yaml:"retry_backoff"`  // 首次重试前的等待时间，单位为毫秒，之后每次翻倍
+	Workers       int    `yaml:"workers"`        // 并发发送的协程数
 }
 
 type Config struct {
 	Server         ServerConfig         `yaml:"server"`
 	Database       DatabaseConfig       `yaml:"database"`
 	Authentication AuthenticationConfig `yaml:"authentication"`
-	File           FileConfig           `yaml:"file"` // 文件上传配置
+	File           FileConfig           `yaml:"file"`   // 文件上传配置
+	Chat           ChatConfig           `yaml:"chat"`   // 聊天配置
+	Push           PushConfig           `yaml:"push"`   // 离线推送配置
+	Friend         FriendConfig         `yaml:"friend"` // 好友配置
 }
 
 // 默认配置
@@ -58,9 +79,22 @@ func defaultConfig() *Config {
 			JwtSecret: "defaultsecret",
 		},
 		File: FileConfig{
-			UploadPath:   "./uploads",
-			MaxSize:      10, // 10MB
-			AllowedTypes: []string{"png", "jpg", "jpeg", "gif", "pdf", "doc", "docx"},
+			UploadPath:    "./uploads",
+			MaxSize:       10, // 10MB
+			AllowedTypes:  []string{"png", "jpg", "jpeg", "gif", "pdf", "doc", "docx"},
+			ThumbnailSize: 256,
+		},
+		Chat: ChatConfig{
+			RecallWindow: 120,
+		},
+		Push: PushConfig{
+			Provider:     "log",
+			MaxRetries:   3,
+			RetryBackoff: 1000,
+			Workers:      4,
+		},
+		Friend: FriendConfig{
+			RequestExpireDays: 30,
 		},
 	}
 }
@@ -0,0 +1,104 @@
+package controllers
+
+import (
+	"time"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// AddActivityAnnouncement 添加活动公告
+func AddActivityAnnouncement(announcement *models.ActivityAnnouncement) error {
+	return config.DB.Create(announcement).Error
+}
+
+// GetActivityAnnouncementById 获取指定公告
+func GetActivityAnnouncementById(announcementId int64) (*models.ActivityAnnouncement, error) {
+	var announcement models.ActivityAnnouncement
+	if err := config.DB.Where("id = ?", announcementId).First(&announcement).Error; err != nil {
+		return nil, err
+	}
+	return &announcement, nil
+}
+
+// GetActivityAnnouncements 分页获取活动公告，置顶公告在前，其余按时间倒序
+func GetActivityAnnouncements(activityId int64, page, pageSize int) ([]models.ActivityAnnouncement, int64, error) {
+	var announcements []models.ActivityAnnouncement
+	var total int64
+	if err := config.DB.Model(&models.ActivityAnnouncement{}).
+		Where("activity_id = ?", activityId).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := config.DB.Where("activity_id = ?", activityId).
+		Order("pinned DESC, create_time DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&announcements).Error; err != nil {
+		return nil, 0, err
+	}
+	return announcements, total, nil
+}
+
+// UpdateActivityAnnouncement 更新活动公告
+func UpdateActivityAnnouncement(announcement *models.ActivityAnnouncement) error {
+	return config.DB.Save(announcement).Error
+}
+
+// DeleteActivityAnnouncement 在事务中删除公告及其阅读记录
+func DeleteActivityAnnouncement(announcementId int64) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("announcement_id = ?", announcementId).
+		Delete(&models.ActivityAnnouncementRead{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&models.ActivityAnnouncement{}, announcementId).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// MarkActivityAnnouncementRead 记录用户已阅读公告，重复标记不会产生新记录
+func MarkActivityAnnouncementRead(announcementId, userId int64, now time.Time) error {
+	read := models.ActivityAnnouncementRead{AnnouncementId: announcementId, UserId: userId}
+	return config.DB.Where("announcement_id = ? AND user_id = ?", announcementId, userId).
+		Attrs(models.ActivityAnnouncementRead{ReadTime: now}).
+		FirstOrCreate(&read).Error
+}
+
+// GetActivityAnnouncementReads 获取公告的所有阅读记录
+func GetActivityAnnouncementReads(announcementId int64) ([]models.ActivityAnnouncementRead, error) {
+	var reads []models.ActivityAnnouncementRead
+	if err := config.DB.Where("announcement_id = ?", announcementId).
+		Order("read_time ASC").
+		Find(&reads).Error; err != nil {
+		return nil, err
+	}
+	return reads, nil
+}
+
+// GetActivityAnnouncementReadsByIds 批量获取多条公告的阅读记录
+func GetActivityAnnouncementReadsByIds(announcementIds []int64) ([]models.ActivityAnnouncementRead, error) {
+	var reads []models.ActivityAnnouncementRead
+	if len(announcementIds) == 0 {
+		return reads, nil
+	}
+	if err := config.DB.Where("announcement_id IN ?", announcementIds).
+		Find(&reads).Error; err != nil {
+		return nil, err
+	}
+	return reads, nil
+}
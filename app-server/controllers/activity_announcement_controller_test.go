@@ -0,0 +1,118 @@
+package controllers
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetActivityAnnouncements(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 测试置顶公告排在前面
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_announcement` WHERE activity_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_announcement` WHERE activity_id = ? ORDER BY pinned DESC, create_time DESC, id DESC LIMIT ?")).
+		WithArgs(int64(1), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "title", "pinned"}).
+			AddRow(1, 1, "置顶", 1).
+			AddRow(2, 1, "普通", 0))
+
+	announcements, total, err := GetActivityAnnouncements(1, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, announcements, 2)
+	assert.Equal(t, 1, announcements[0].Pinned)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteActivityAnnouncement(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 测试先删除阅读记录再删除公告
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_announcement_read` WHERE announcement_id = ?")).
+		WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_announcement` WHERE `activity_announcement`.`id` = ?")).
+		WithArgs(int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := DeleteActivityAnnouncement(3)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 删除阅读记录失败时回滚
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_announcement_read`")).
+		WillReturnError(errors.New("delete error"))
+	mock2.ExpectRollback()
+
+	err = DeleteActivityAnnouncement(3)
+	assert.EqualError(t, err, "delete error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestMarkActivityAnnouncementRead(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+
+	// 首次阅读时写入记录
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_announcement_read` WHERE announcement_id = ? AND user_id = ?")).
+		WithArgs(int64(3), int64(2), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_announcement_read` (`announcement_id`,`user_id`,`read_time`) VALUES (?,?,?)")).
+		WithArgs(int64(3), int64(2), now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := MarkActivityAnnouncementRead(3, 2, now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 已读过时不重复写入
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_announcement_read` WHERE announcement_id = ? AND user_id = ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "announcement_id", "user_id"}).AddRow(1, 3, 2))
+
+	err = MarkActivityAnnouncementRead(3, 2, now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetActivityAnnouncementReadsByIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 空列表不查询数据库
+	reads, err := GetActivityAnnouncementReadsByIds(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, reads)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_announcement_read` WHERE announcement_id IN (?,?)")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "announcement_id", "user_id"}).
+			AddRow(1, 1, 5).
+			AddRow(2, 2, 5))
+
+	reads, err = GetActivityAnnouncementReadsByIds([]int64{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, reads, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,7 +1,10 @@
 package controllers
 
 import (
+	"time"
+
 	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
 	"hobbyhub-server/models"
 )
 
@@ -81,6 +84,39 @@ func AddActivityMember(activityMember *models.ActivityMember) error {
 	return nil
 }
 
+// JoinActivity 在事务中校验成员是否已存在并添加活动成员
+func JoinActivity(activityMember *models.ActivityMember) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var count int64
+	if err := tx.Model(&models.ActivityMember{}).
+		Where("activity_id = ? AND user_id = ?", activityMember.ActivityId, activityMember.UserId).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count > 0 {
+		tx.Rollback()
+		return custom_errors.ErrAlreadyActivityMember
+	}
+
+	if err := tx.Create(activityMember).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
 func GetAllActivityMembers() ([]models.ActivityMember, error) {
 	var members []models.ActivityMember
 	if err := config.DB.Find(&members).Error; err != nil {
@@ -117,6 +153,105 @@ func UpdateActivityMember(activityMember *models.ActivityMember) error {
 	return nil
 }
 
+// GetActivityMember 获取用户在活动中的成员记录
+func GetActivityMember(activityId, userId int64) (*models.ActivityMember, error) {
+	var member models.ActivityMember
+	if err := config.DB.Where("activity_id = ? AND user_id = ?", activityId, userId).
+		First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// UpdateActivityMemberRole 修改活动成员角色
+func UpdateActivityMemberRole(activityId, userId int64, role int) error {
+	result := config.DB.Model(&models.ActivityMember{}).
+		Where("activity_id = ? AND user_id = ?", activityId, userId).
+		Update("role", role)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return custom_errors.ErrNotActivityMember
+	}
+	return nil
+}
+
+// IsActivityManager 判断用户是否为活动的联合组织者（或持有组织者角色的成员）
+func IsActivityManager(activityId, userId int64) (bool, error) {
+	var count int64
+	if err := config.DB.Model(&models.ActivityMember{}).
+		Where("activity_id = ? AND user_id = ? AND role IN ?", activityId, userId,
+			[]int{models.ActivityRoleCoOrganizer, models.ActivityRoleOrganizer}).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// TransferActivityOwnership 在事务中将活动转让给另一名成员，原组织者保留为联合组织者
+func TransferActivityOwnership(activity *models.Activity, newOwnerId int64, now time.Time) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	// 新组织者必须是活动成员
+	result := tx.Model(&models.ActivityMember{}).
+		Where("activity_id = ? AND user_id = ?", activity.Id, newOwnerId).
+		Update("role", models.ActivityRoleOrganizer)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return custom_errors.ErrNotActivityMember
+	}
+
+	// 原组织者降为联合组织者，没有成员记录时补建一条
+	oldOwnerId := activity.UserId
+	result = tx.Model(&models.ActivityMember{}).
+		Where("activity_id = ? AND user_id = ?", activity.Id, oldOwnerId).
+		Update("role", models.ActivityRoleCoOrganizer)
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		member := &models.ActivityMember{
+			ActivityId: activity.Id,
+			UserId:     oldOwnerId,
+			Role:       models.ActivityRoleCoOrganizer,
+			CreateTime: now,
+		}
+		if err := tx.Create(member).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Model(&models.Activity{}).
+		Where("id = ?", activity.Id).
+		Updates(map[string]interface{}{"user_id": newOwnerId, "update_time": now}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	activity.UserId = newOwnerId
+	activity.UpdateTime = now
+	return nil
+}
+
 // DeleteActivityMember 删除活动成员
 func DeleteActivityMember(activityId, userId int64) error {
 	if err := config.DB.Where("activity_id = ? AND user_id = ?", activityId, userId).
@@ -172,6 +307,15 @@ func GetActivityCommentsByActivityIdAndUserId(activityId, userId int64) ([]model
 	return comments, nil
 }
 
+// GetActivityCommentById 获取指定评论
+func GetActivityCommentById(commentId int64) (*models.ActivityComment, error) {
+	var comment models.ActivityComment
+	if err := config.DB.Where("id = ?", commentId).First(&comment).Error; err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
 // UpdateActivityComment 更新活动评论
 func UpdateActivityComment(activityComment *models.ActivityComment) error {
 	if err := config.DB.Save(activityComment).Error; err != nil {
@@ -187,3 +331,44 @@ func DeleteActivityComment(commentId int64) error {
 	}
 	return nil
 }
+
+// IsActivityMember 判断用户是否为活动成员
+func IsActivityMember(activityId, userId int64) (bool, error) {
+	var count int64
+	if err := config.DB.Model(&models.ActivityMember{}).
+		Where("activity_id = ? AND user_id = ?", activityId, userId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetVisibleActivities 获取用户可见的所有活动，userId 为0时只返回公开活动
+func GetVisibleActivities(userId int64) ([]models.Activity, error) {
+	var activities []models.Activity
+	query := config.DB.Where("if_delete = 0")
+	if userId == 0 {
+		query = query.Where("visibility = ?", models.ActivityVisibilityPublic)
+	} else {
+		memberActivityIds := config.DB.Model(&models.ActivityMember{}).
+			Select("activity_id").
+			Where("user_id = ?", userId)
+		friendIds := config.DB.Table("("+friendEdgeSQL+") AS friend_edge").
+			Select("friend_id").
+			Where("user_id = ? AND status = ?", userId, models.FriendshipAccepted)
+		invitedActivityIds := config.DB.Model(&models.ActivityInvitation{}).
+			Select("activity_id").
+			Where("invitee_id = ? AND status IN ?", userId, []int{models.InvitationPending, models.InvitationAccepted})
+		query = query.Where(
+			config.DB.Where("visibility = ?", models.ActivityVisibilityPublic).
+				Or("user_id = ?", userId).
+				Or("id IN (?)", memberActivityIds).
+				Or("visibility = ? AND user_id IN (?)", models.ActivityVisibilityFriends, friendIds).
+				Or("visibility = ? AND id IN (?)", models.ActivityVisibilityInvite, invitedActivityIds),
+		)
+	}
+	if err := query.Order("create_time DESC").Find(&activities).Error; err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
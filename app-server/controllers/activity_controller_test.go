@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"hobbyhub-server/custom_errors"
 	"hobbyhub-server/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -594,3 +595,194 @@ func TestDeleteActivityComment(t *testing.T) {
 	assert.EqualError(t, err, "delete error")
 	assert.NoError(t, mock2.ExpectationsWereMet())
 }
+
+func TestIsActivityMember(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_member` WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	isMember, err := IsActivityMember(1, 2)
+	assert.NoError(t, err)
+	assert.True(t, isMember)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 非成员
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_member` WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(int64(1), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	isMember, err = IsActivityMember(1, 3)
+	assert.NoError(t, err)
+	assert.False(t, isMember)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestJoinActivity(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	member := &models.ActivityMember{ActivityId: 1, UserId: 2, CreateTime: time.Now()}
+
+	// 测试成功加入
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_member` WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_member`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := JoinActivity(member)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试重复加入
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_member` WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock2.ExpectRollback()
+
+	err = JoinActivity(member)
+	assert.ErrorIs(t, err, custom_errors.ErrAlreadyActivityMember)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetVisibleActivities(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 未登录只查询公开活动
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity` WHERE if_delete = 0 AND visibility = ? ORDER BY create_time DESC")).
+		WithArgs(models.ActivityVisibilityPublic).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "公开活动"))
+
+	activities, err := GetVisibleActivities(0)
+	assert.NoError(t, err)
+	assert.Len(t, activities, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 登录用户同时可见自己创建、参加、好友及受邀的活动
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery("SELECT \\* FROM `activity` WHERE if_delete = 0 AND \\(visibility = \\? OR user_id = \\? OR id IN \\(SELECT `activity_id` FROM `activity_member`.*\\).*ORDER BY create_time DESC").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).AddRow(1, "公开活动").AddRow(2, "好友活动"))
+
+	activities, err = GetVisibleActivities(5)
+	assert.NoError(t, err)
+	assert.Len(t, activities, 2)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestUpdateActivityMemberRole(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 测试成功修改角色
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity_member` SET `role`=? WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(models.ActivityRoleCoOrganizer, int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := UpdateActivityMemberRole(1, 2, models.ActivityRoleCoOrganizer)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试成员不存在
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `activity_member` SET `role`=? WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(models.ActivityRoleMember, int64(1), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock2.ExpectCommit()
+
+	err = UpdateActivityMemberRole(1, 3, models.ActivityRoleMember)
+	assert.ErrorIs(t, err, custom_errors.ErrNotActivityMember)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestIsActivityManager(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_member` WHERE activity_id = ? AND user_id = ? AND role IN (?,?)")).
+		WithArgs(int64(1), int64(2), models.ActivityRoleCoOrganizer, models.ActivityRoleOrganizer).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	isManager, err := IsActivityManager(1, 2)
+	assert.NoError(t, err)
+	assert.True(t, isManager)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestTransferActivityOwnership(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	activity := &models.Activity{Id: 1, UserId: 2}
+
+	// 测试成功转让，原组织者没有成员记录时补建
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity_member` SET `role`=? WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(models.ActivityRoleOrganizer, int64(1), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity_member` SET `role`=? WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(models.ActivityRoleCoOrganizer, int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_member`")).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity` SET `update_time`=?,`user_id`=? WHERE id = ?")).
+		WithArgs(now, int64(3), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := TransferActivityOwnership(activity, 3, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), activity.UserId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试新组织者不是活动成员
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	activity = &models.Activity{Id: 1, UserId: 2}
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `activity_member` SET `role`=? WHERE activity_id = ? AND user_id = ?")).
+		WithArgs(models.ActivityRoleOrganizer, int64(1), int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock2.ExpectRollback()
+
+	err = TransferActivityOwnership(activity, 4, now)
+	assert.ErrorIs(t, err, custom_errors.ErrNotActivityMember)
+	assert.Equal(t, int64(2), activity.UserId)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetActivityCommentById(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_comment` WHERE id = ? ORDER BY `activity_comment`.`id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "user_id", "content"}).
+			AddRow(1, 2, 3, "评论"))
+
+	comment, err := GetActivityCommentById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), comment.ActivityId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
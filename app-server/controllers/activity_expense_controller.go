@@ -0,0 +1,150 @@
+package controllers
+
+import (
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// AddActivityExpense 在事务中添加费用记录及其分摊明细
+func AddActivityExpense(expense *models.ActivityExpense, shares []models.ActivityExpenseShare) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(expense).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range shares {
+		shares[i].ExpenseId = expense.Id
+	}
+	if err := tx.Create(&shares).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetActivityExpenses 分页获取活动的费用记录，按时间倒序
+func GetActivityExpenses(activityId int64, page, pageSize int) ([]models.ActivityExpense, int64, error) {
+	var expenses []models.ActivityExpense
+	var total int64
+	if err := config.DB.Model(&models.ActivityExpense{}).
+		Where("activity_id = ?", activityId).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := config.DB.Where("activity_id = ?", activityId).
+		Order("create_time DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&expenses).Error; err != nil {
+		return nil, 0, err
+	}
+	return expenses, total, nil
+}
+
+// GetActivityExpenseSharesByExpenseIds 批量获取费用的分摊明细
+func GetActivityExpenseSharesByExpenseIds(expenseIds []int64) ([]models.ActivityExpenseShare, error) {
+	var shares []models.ActivityExpenseShare
+	if len(expenseIds) == 0 {
+		return shares, nil
+	}
+	if err := config.DB.Where("expense_id IN ?", expenseIds).
+		Order("id ASC").
+		Find(&shares).Error; err != nil {
+		return nil, err
+	}
+	return shares, nil
+}
+
+// GetActivityExpenseBalances 计算活动内每个用户按币种的净余额，正数为应收，负数为应付
+func GetActivityExpenseBalances(activityId int64) (map[string]map[int64]int64, error) {
+	balances := make(map[string]map[int64]int64)
+	apply := func(rows []models.ExpenseAmount, sign int64) {
+		for _, row := range rows {
+			if balances[row.Currency] == nil {
+				balances[row.Currency] = make(map[int64]int64)
+			}
+			balances[row.Currency][row.UserId] += sign * row.Amount
+		}
+	}
+
+	// 垫付的金额计为应收
+	var paid []models.ExpenseAmount
+	if err := config.DB.Model(&models.ActivityExpense{}).
+		Select("currency, payer_id AS user_id, SUM(amount) AS amount").
+		Where("activity_id = ?", activityId).
+		Group("currency, payer_id").
+		Scan(&paid).Error; err != nil {
+		return nil, err
+	}
+	apply(paid, 1)
+
+	// 分摊的金额计为应付
+	var owed []models.ExpenseAmount
+	if err := config.DB.Table("activity_expense_share AS s").
+		Select("e.currency AS currency, s.user_id AS user_id, SUM(s.amount) AS amount").
+		Joins("JOIN activity_expense AS e ON e.id = s.expense_id").
+		Where("e.activity_id = ?", activityId).
+		Group("e.currency, s.user_id").
+		Scan(&owed).Error; err != nil {
+		return nil, err
+	}
+	apply(owed, -1)
+
+	// 已付的结算款抵消对应的应付与应收
+	var sent []models.ExpenseAmount
+	if err := config.DB.Model(&models.ActivitySettlement{}).
+		Select("currency, from_user_id AS user_id, SUM(amount) AS amount").
+		Where("activity_id = ?", activityId).
+		Group("currency, from_user_id").
+		Scan(&sent).Error; err != nil {
+		return nil, err
+	}
+	apply(sent, 1)
+
+	var received []models.ExpenseAmount
+	if err := config.DB.Model(&models.ActivitySettlement{}).
+		Select("currency, to_user_id AS user_id, SUM(amount) AS amount").
+		Where("activity_id = ?", activityId).
+		Group("currency, to_user_id").
+		Scan(&received).Error; err != nil {
+		return nil, err
+	}
+	apply(received, -1)
+
+	return balances, nil
+}
+
+// AddActivitySettlement 记录一笔已付的结算款
+func AddActivitySettlement(settlement *models.ActivitySettlement) error {
+	return config.DB.Create(settlement).Error
+}
+
+// GetActivitySettlements 分页获取活动的结算记录，按时间倒序
+func GetActivitySettlements(activityId int64, page, pageSize int) ([]models.ActivitySettlement, int64, error) {
+	var settlements []models.ActivitySettlement
+	var total int64
+	if err := config.DB.Model(&models.ActivitySettlement{}).
+		Where("activity_id = ?", activityId).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := config.DB.Where("activity_id = ?", activityId).
+		Order("create_time DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&settlements).Error; err != nil {
+		return nil, 0, err
+	}
+	return settlements, total, nil
+}
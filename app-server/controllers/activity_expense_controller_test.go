@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddActivityExpense(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	expense := &models.ActivityExpense{ActivityId: 1, PayerId: 2, CreatorId: 2, Amount: 1000, Currency: "CNY", CreateTime: time.Now()}
+	shares := []models.ActivityExpenseShare{{UserId: 2, Amount: 500}, {UserId: 3, Amount: 500}}
+
+	// 测试成功添加费用及分摊明细
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_expense`")).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_expense_share` (`expense_id`,`user_id`,`amount`) VALUES (?,?,?),(?,?,?)")).
+		WithArgs(int64(7), int64(2), int64(500), int64(7), int64(3), int64(500)).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	err := AddActivityExpense(expense, shares)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), expense.Id)
+	assert.Equal(t, int64(7), shares[1].ExpenseId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试分摊明细插入失败时回滚
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	expense = &models.ActivityExpense{ActivityId: 1, PayerId: 2, CreatorId: 2, Amount: 1000, Currency: "CNY", CreateTime: time.Now()}
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_expense`")).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	mock2.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_expense_share`")).
+		WillReturnError(errors.New("insert error"))
+	mock2.ExpectRollback()
+
+	err = AddActivityExpense(expense, shares)
+	assert.EqualError(t, err, "insert error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetActivityExpenses(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_expense` WHERE activity_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_expense` WHERE activity_id = ? ORDER BY create_time DESC, id DESC LIMIT ? OFFSET ?")).
+		WithArgs(int64(1), 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "amount", "currency"}).
+			AddRow(1, 1, 1000, "CNY"))
+
+	expenses, total, err := GetActivityExpenses(1, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Len(t, expenses, 1)
+	assert.Equal(t, int64(1000), expenses[0].Amount)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetActivityExpenseSharesByExpenseIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 空列表不查询数据库
+	shares, err := GetActivityExpenseSharesByExpenseIds(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, shares)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_expense_share` WHERE expense_id IN (?,?) ORDER BY id ASC")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "expense_id", "user_id", "amount"}).
+			AddRow(1, 1, 2, 500).
+			AddRow(2, 2, 3, 250))
+
+	shares, err = GetActivityExpenseSharesByExpenseIds([]int64{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, shares, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetActivityExpenseBalances(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 用户2垫付30元由2、3平摊，用户3已还5元
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, payer_id AS user_id, SUM(amount) AS amount FROM `activity_expense` WHERE activity_id = ? GROUP BY currency, payer_id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "user_id", "amount"}).AddRow("CNY", 2, 3000))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT e.currency AS currency, s.user_id AS user_id, SUM(s.amount) AS amount FROM activity_expense_share AS s JOIN activity_expense AS e ON e.id = s.expense_id WHERE e.activity_id = ? GROUP BY e.currency, s.user_id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "user_id", "amount"}).
+			AddRow("CNY", 2, 1500).
+			AddRow("CNY", 3, 1500))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, from_user_id AS user_id, SUM(amount) AS amount FROM `activity_settlement` WHERE activity_id = ? GROUP BY currency, from_user_id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "user_id", "amount"}).AddRow("CNY", 3, 500))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT currency, to_user_id AS user_id, SUM(amount) AS amount FROM `activity_settlement` WHERE activity_id = ? GROUP BY currency, to_user_id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"currency", "user_id", "amount"}).AddRow("CNY", 2, 500))
+
+	balances, err := GetActivityExpenseBalances(1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[int64]int64{"CNY": {2: 1000, 3: -1000}}, balances)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试查询失败
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT currency, payer_id AS user_id, SUM(amount) AS amount FROM `activity_expense`")).
+		WillReturnError(errors.New("query error"))
+
+	balances, err = GetActivityExpenseBalances(1)
+	assert.Nil(t, balances)
+	assert.EqualError(t, err, "query error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestAddActivitySettlement(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	settlement := &models.ActivitySettlement{ActivityId: 1, FromUserId: 3, ToUserId: 2, Amount: 500, Currency: "CNY", CreatorId: 3, CreateTime: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_settlement`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := AddActivitySettlement(settlement)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), settlement.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetActivitySettlements(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_settlement` WHERE activity_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_settlement` WHERE activity_id = ? ORDER BY create_time DESC, id DESC LIMIT ?")).
+		WithArgs(int64(1), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "from_user_id", "to_user_id", "amount"}).
+			AddRow(1, 1, 3, 2, 500))
+
+	settlements, total, err := GetActivitySettlements(1, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, settlements, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
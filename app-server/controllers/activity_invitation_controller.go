@@ -0,0 +1,79 @@
+package controllers
+
+import (
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// AddActivityInvitation 添加活动邀请
+func AddActivityInvitation(invitation *models.ActivityInvitation) error {
+	return config.DB.Create(invitation).Error
+}
+
+// GetActivityInvitationById 获取指定活动邀请
+func GetActivityInvitationById(invitationId int64) (*models.ActivityInvitation, error) {
+	var invitation models.ActivityInvitation
+	if err := config.DB.Where("id = ?", invitationId).First(&invitation).Error; err != nil {
+		return nil, err
+	}
+	return &invitation, nil
+}
+
+// HasPendingActivityInvitation 判断用户是否已有该活动的待处理邀请
+func HasPendingActivityInvitation(activityId, inviteeId int64) (bool, error) {
+	var count int64
+	if err := config.DB.Model(&models.ActivityInvitation{}).
+		Where("activity_id = ? AND invitee_id = ? AND status = ?", activityId, inviteeId, models.InvitationPending).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetPendingActivityInvitationsByInviteeId 获取用户收到的所有待处理邀请
+func GetPendingActivityInvitationsByInviteeId(inviteeId int64) ([]models.ActivityInvitation, error) {
+	var invitations []models.ActivityInvitation
+	if err := config.DB.Where("invitee_id = ? AND status = ?", inviteeId, models.InvitationPending).
+		Order("create_time DESC").
+		Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+// UpdateActivityInvitation 更新活动邀请
+func UpdateActivityInvitation(invitation *models.ActivityInvitation) error {
+	return config.DB.Save(invitation).Error
+}
+
+// CountActivityInvitationsByStatus 按状态统计活动的邀请数量
+func CountActivityInvitationsByStatus(activityId int64) (map[int]int64, error) {
+	var rows []struct {
+		Status int
+		Count  int64
+	}
+	if err := config.DB.Model(&models.ActivityInvitation{}).
+		Select("status, COUNT(*) AS count").
+		Where("activity_id = ?", activityId).
+		Group("status").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int]int64)
+	for _, row := range rows {
+		result[row.Status] = row.Count
+	}
+	return result, nil
+}
+
+// HasActivityInvitation 判断用户是否收到过该活动的有效邀请（待处理或已接受）
+func HasActivityInvitation(activityId, inviteeId int64) (bool, error) {
+	var count int64
+	if err := config.DB.Model(&models.ActivityInvitation{}).
+		Where("activity_id = ? AND invitee_id = ? AND status IN ?", activityId, inviteeId,
+			[]int{models.InvitationPending, models.InvitationAccepted}).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
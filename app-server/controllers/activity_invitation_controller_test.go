@@ -0,0 +1,134 @@
+package controllers
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAddActivityInvitation(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	invitation := &models.ActivityInvitation{ActivityId: 1, InviterId: 2, InviteeId: 3, CreateTime: time.Now(), UpdateTime: time.Now()}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_invitation`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := AddActivityInvitation(invitation)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试添加失败
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_invitation`")).
+		WillReturnError(errors.New("insert error"))
+	mock2.ExpectRollback()
+
+	err = AddActivityInvitation(invitation)
+	assert.EqualError(t, err, "insert error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetActivityInvitationById(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_invitation` WHERE id = ? ORDER BY `activity_invitation`.`id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "inviter_id", "invitee_id", "status"}).
+			AddRow(1, 2, 3, 4, models.InvitationPending))
+
+	invitation, err := GetActivityInvitationById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), invitation.ActivityId)
+	assert.Equal(t, int64(4), invitation.InviteeId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试邀请不存在
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_invitation` WHERE id = ? ORDER BY `activity_invitation`.`id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	invitation, err = GetActivityInvitationById(1)
+	assert.Nil(t, invitation)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestHasPendingActivityInvitation(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_invitation` WHERE activity_id = ? AND invitee_id = ? AND status = ?")).
+		WithArgs(int64(1), int64(2), models.InvitationPending).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	hasPending, err := HasPendingActivityInvitation(1, 2)
+	assert.NoError(t, err)
+	assert.True(t, hasPending)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetPendingActivityInvitationsByInviteeId(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_invitation` WHERE invitee_id = ? AND status = ? ORDER BY create_time DESC")).
+		WithArgs(int64(2), models.InvitationPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "invitee_id"}).
+			AddRow(1, 10, 2).
+			AddRow(2, 11, 2))
+
+	invitations, err := GetPendingActivityInvitationsByInviteeId(2)
+	assert.NoError(t, err)
+	assert.Len(t, invitations, 2)
+	assert.Equal(t, int64(11), invitations[1].ActivityId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountActivityInvitationsByStatus(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT status, COUNT(*) AS count FROM `activity_invitation` WHERE activity_id = ? GROUP BY `status`")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"status", "count"}).
+			AddRow(models.InvitationPending, 3).
+			AddRow(models.InvitationAccepted, 2))
+
+	counts, err := CountActivityInvitationsByStatus(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), counts[models.InvitationPending])
+	assert.Equal(t, int64(2), counts[models.InvitationAccepted])
+	assert.Equal(t, int64(0), counts[models.InvitationDeclined])
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试查询失败
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT status, COUNT(*) AS count FROM `activity_invitation` WHERE activity_id = ? GROUP BY `status`")).
+		WithArgs(int64(1)).
+		WillReturnError(errors.New("query error"))
+
+	counts, err = CountActivityInvitationsByStatus(1)
+	assert.Nil(t, counts)
+	assert.EqualError(t, err, "query error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
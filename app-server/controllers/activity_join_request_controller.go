@@ -0,0 +1,65 @@
+package controllers
+
+import (
+	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+)
+
+// AddActivityJoinRequest 添加入团申请，已有待审批申请时返回错误
+func AddActivityJoinRequest(request *models.ActivityJoinRequest) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var count int64
+	if err := tx.Model(&models.ActivityJoinRequest{}).
+		Where("activity_id = ? AND user_id = ? AND status = ?", request.ActivityId, request.UserId, models.JoinRequestPending).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count > 0 {
+		tx.Rollback()
+		return custom_errors.ErrJoinRequestPending
+	}
+
+	if err := tx.Create(request).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetActivityJoinRequestById 获取指定入团申请
+func GetActivityJoinRequestById(requestId int64) (*models.ActivityJoinRequest, error) {
+	var request models.ActivityJoinRequest
+	if err := config.DB.Where("id = ?", requestId).First(&request).Error; err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// GetPendingActivityJoinRequests 获取活动所有待审批的入团申请
+func GetPendingActivityJoinRequests(activityId int64) ([]models.ActivityJoinRequest, error) {
+	var requests []models.ActivityJoinRequest
+	if err := config.DB.Where("activity_id = ? AND status = ?", activityId, models.JoinRequestPending).
+		Order("create_time ASC").
+		Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+// UpdateActivityJoinRequest 更新入团申请
+func UpdateActivityJoinRequest(request *models.ActivityJoinRequest) error {
+	return config.DB.Save(request).Error
+}
@@ -0,0 +1,107 @@
+package controllers
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAddActivityJoinRequest(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	request := &models.ActivityJoinRequest{ActivityId: 1, UserId: 2, CreateTime: time.Now(), UpdateTime: time.Now()}
+
+	// 测试成功提交申请
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_join_request` WHERE activity_id = ? AND user_id = ? AND status = ?")).
+		WithArgs(int64(1), int64(2), models.JoinRequestPending).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_join_request`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := AddActivityJoinRequest(request)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试重复提交
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_join_request` WHERE activity_id = ? AND user_id = ? AND status = ?")).
+		WithArgs(int64(1), int64(2), models.JoinRequestPending).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock2.ExpectRollback()
+
+	err = AddActivityJoinRequest(request)
+	assert.ErrorIs(t, err, custom_errors.ErrJoinRequestPending)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetActivityJoinRequestById(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_join_request` WHERE id = ? ORDER BY `activity_join_request`.`id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "user_id", "status"}).
+			AddRow(1, 2, 3, models.JoinRequestPending))
+
+	request, err := GetActivityJoinRequestById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), request.ActivityId)
+	assert.Equal(t, int64(3), request.UserId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试申请不存在
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_join_request` WHERE id = ? ORDER BY `activity_join_request`.`id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	request, err = GetActivityJoinRequestById(1)
+	assert.Nil(t, request)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetPendingActivityJoinRequests(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_join_request` WHERE activity_id = ? AND status = ? ORDER BY create_time ASC")).
+		WithArgs(int64(1), models.JoinRequestPending).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "user_id"}).
+			AddRow(1, 1, 2).
+			AddRow(2, 1, 3))
+
+	requests, err := GetPendingActivityJoinRequests(1)
+	assert.NoError(t, err)
+	assert.Len(t, requests, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试查询失败
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_join_request` WHERE activity_id = ? AND status = ? ORDER BY create_time ASC")).
+		WithArgs(int64(1), models.JoinRequestPending).
+		WillReturnError(errors.New("query error"))
+
+	requests, err = GetPendingActivityJoinRequests(1)
+	assert.Nil(t, requests)
+	assert.EqualError(t, err, "query error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+)
+
+// AddActivityPhoto 添加活动相册图片，排序序号追加到末尾。
+// originalFileId 为图片实际存储的原始文件Id，关联到同一原始文件的图片在相册中只能出现一次
+func AddActivityPhoto(photo *models.ActivityPhoto, originalFileId int64) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var count int64
+	if err := tx.Model(&models.ActivityPhoto{}).
+		Where("activity_id = ? AND file_id IN (SELECT id FROM file WHERE id = ? OR link_file_id = ?)",
+			photo.ActivityId, originalFileId, originalFileId).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count > 0 {
+		tx.Rollback()
+		return custom_errors.ErrPhotoAlreadyAttached
+	}
+
+	var maxSortOrder int
+	if err := tx.Model(&models.ActivityPhoto{}).
+		Where("activity_id = ?", photo.ActivityId).
+		Select("COALESCE(MAX(sort_order), 0)").
+		Scan(&maxSortOrder).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	photo.SortOrder = maxSortOrder + 1
+
+	if err := tx.Create(photo).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetActivityPhotoById 获取指定相册图片
+func GetActivityPhotoById(photoId int64) (*models.ActivityPhoto, error) {
+	var photo models.ActivityPhoto
+	if err := config.DB.Where("id = ?", photoId).First(&photo).Error; err != nil {
+		return nil, err
+	}
+	return &photo, nil
+}
+
+// GetActivityPhotosByActivityId 分页获取活动相册，按排序序号升序
+func GetActivityPhotosByActivityId(activityId int64, page, pageSize int) ([]models.ActivityPhoto, int64, error) {
+	var photos []models.ActivityPhoto
+	var total int64
+	if err := config.DB.Model(&models.ActivityPhoto{}).
+		Where("activity_id = ?", activityId).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := config.DB.Where("activity_id = ?", activityId).
+		Order("sort_order ASC, id ASC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&photos).Error; err != nil {
+		return nil, 0, err
+	}
+	return photos, total, nil
+}
+
+// UpdateActivityPhoto 更新相册图片信息
+func UpdateActivityPhoto(photo *models.ActivityPhoto) error {
+	return config.DB.Save(photo).Error
+}
+
+// SortActivityPhotos 按给定顺序重排活动相册，未列出的图片保持原有序号
+func SortActivityPhotos(activityId int64, photoIds []int64) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for i, photoId := range photoIds {
+		if err := tx.Model(&models.ActivityPhoto{}).
+			Where("id = ? AND activity_id = ?", photoId, activityId).
+			Update("sort_order", i+1).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// DeleteActivityPhoto 删除相册图片
+func DeleteActivityPhoto(photoId int64) error {
+	return config.DB.Delete(&models.ActivityPhoto{}, photoId).Error
+}
+
+// DeleteActivityPhotosByFileId 删除引用指定文件的所有相册图片，
+// 这些活动的封面为该图片（cover 为原始文件的磁盘文件名）时一并清除
+func DeleteActivityPhotosByFileId(fileId int64, cover string) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var activityIds []int64
+	if err := tx.Model(&models.ActivityPhoto{}).
+		Where("file_id = ?", fileId).
+		Distinct().
+		Pluck("activity_id", &activityIds).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("file_id = ?", fileId).Delete(&models.ActivityPhoto{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(activityIds) > 0 {
+		if err := tx.Model(&models.Activity{}).
+			Where("id IN ? AND head_img = ?", activityIds, cover).
+			Update("head_img", "").Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// ClearActivityCovers 清除使用指定磁盘文件作为封面的所有活动封面
+func ClearActivityCovers(cover string) error {
+	return config.DB.Model(&models.Activity{}).
+		Where("head_img = ?", cover).
+		Update("head_img", "").Error
+}
@@ -0,0 +1,203 @@
+package controllers
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAddActivityPhoto(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	photo := &models.ActivityPhoto{ActivityId: 1, FileId: 2, UserId: 3, Caption: "合影", CreateTime: time.Now()}
+
+	// 测试成功添加，排序序号追加到末尾
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_photo` WHERE activity_id = ? AND file_id IN (SELECT id FROM file WHERE id = ? OR link_file_id = ?)")).
+		WithArgs(int64(1), int64(2), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(sort_order), 0) FROM `activity_photo` WHERE activity_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(4))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_photo`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := AddActivityPhoto(photo, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 5, photo.SortOrder)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试重复添加关联到同一原始文件的文件
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	photo = &models.ActivityPhoto{ActivityId: 1, FileId: 6, UserId: 3, CreateTime: time.Now()}
+	mock2.ExpectBegin()
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_photo` WHERE activity_id = ? AND file_id IN (SELECT id FROM file WHERE id = ? OR link_file_id = ?)")).
+		WithArgs(int64(1), int64(2), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock2.ExpectRollback()
+
+	err = AddActivityPhoto(photo, 2)
+	assert.ErrorIs(t, err, custom_errors.ErrPhotoAlreadyAttached)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetActivityPhotoById(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	rows := sqlmock.NewRows([]string{"id", "activity_id", "file_id", "caption"}).
+		AddRow(1, 2, 3, "合影")
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_photo` WHERE id = ? ORDER BY `activity_photo`.`id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnRows(rows)
+
+	photo, err := GetActivityPhotoById(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), photo.ActivityId)
+	assert.Equal(t, int64(3), photo.FileId)
+	assert.Equal(t, "合影", photo.Caption)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试图片不存在
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_photo` WHERE id = ? ORDER BY `activity_photo`.`id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnError(gorm.ErrRecordNotFound)
+
+	photo, err = GetActivityPhotoById(1)
+	assert.Nil(t, photo)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetActivityPhotosByActivityId(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_photo` WHERE activity_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(12))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_photo` WHERE activity_id = ? ORDER BY sort_order ASC, id ASC LIMIT ? OFFSET ?")).
+		WithArgs(int64(1), 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "file_id", "sort_order"}).
+			AddRow(11, 1, 21, 11).
+			AddRow(12, 1, 22, 12))
+
+	photos, total, err := GetActivityPhotosByActivityId(1, 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(12), total)
+	assert.Len(t, photos, 2)
+	assert.Equal(t, int64(21), photos[0].FileId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试查询失败
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_photo` WHERE activity_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnError(errors.New("query error"))
+
+	photos, _, err = GetActivityPhotosByActivityId(1, 1, 10)
+	assert.Nil(t, photos)
+	assert.EqualError(t, err, "query error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestSortActivityPhotos(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 测试成功重排
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity_photo` SET `sort_order`=? WHERE id = ? AND activity_id = ?")).
+		WithArgs(1, int64(3), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity_photo` SET `sort_order`=? WHERE id = ? AND activity_id = ?")).
+		WithArgs(2, int64(2), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := SortActivityPhotos(1, []int64{3, 2})
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试更新失败时回滚
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `activity_photo` SET `sort_order`=? WHERE id = ? AND activity_id = ?")).
+		WithArgs(1, int64(3), int64(1)).
+		WillReturnError(errors.New("update error"))
+	mock2.ExpectRollback()
+
+	err = SortActivityPhotos(1, []int64{3, 2})
+	assert.EqualError(t, err, "update error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestDeleteActivityPhoto(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_photo` WHERE `activity_photo`.`id` = ?")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := DeleteActivityPhoto(1)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 按文件删除，封面为该图片的活动一并清除封面
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT `activity_id` FROM `activity_photo` WHERE file_id = ?")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"activity_id"}).AddRow(4).AddRow(5))
+	mock2.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_photo` WHERE file_id = ?")).
+		WithArgs(int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `activity` SET `head_img`=? WHERE id IN (?,?) AND head_img = ?")).
+		WithArgs("", int64(4), int64(5), "1.jpg").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock2.ExpectCommit()
+
+	err = DeleteActivityPhotosByFileId(2, "1.jpg")
+	assert.NoError(t, err)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestClearActivityCovers(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity` SET `head_img`=? WHERE head_img = ?")).
+		WithArgs("", "1.jpg").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := ClearActivityCovers("1.jpg")
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,183 @@
+package controllers
+
+import (
+	"time"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+)
+
+// AddActivityPoll 在事务中创建活动时间投票及候选时间，每个活动只能有一个投票
+func AddActivityPoll(poll *models.ActivityPoll, options []models.ActivityPollOption) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var count int64
+	if err := tx.Model(&models.ActivityPoll{}).
+		Where("activity_id = ? AND status = ?", poll.ActivityId, models.PollOpen).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count > 0 {
+		tx.Rollback()
+		return custom_errors.ErrPollAlreadyExists
+	}
+
+	if err := tx.Create(poll).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range options {
+		options[i].PollId = poll.Id
+	}
+	if err := tx.Create(&options).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetActivityPollByActivityId 获取活动最近一次发起的时间投票
+func GetActivityPollByActivityId(activityId int64) (*models.ActivityPoll, error) {
+	var poll models.ActivityPoll
+	if err := config.DB.Where("activity_id = ?", activityId).Order("id DESC").First(&poll).Error; err != nil {
+		return nil, err
+	}
+	return &poll, nil
+}
+
+// GetActivityPollOptions 获取投票的所有候选时间，按时间升序
+func GetActivityPollOptions(pollId int64) ([]models.ActivityPollOption, error) {
+	var options []models.ActivityPollOption
+	if err := config.DB.Where("poll_id = ?", pollId).
+		Order("start_time ASC, id ASC").
+		Find(&options).Error; err != nil {
+		return nil, err
+	}
+	return options, nil
+}
+
+// SaveActivityPollVotes 在事务中保存用户对若干候选时间的投票，已投过的选项会被覆盖
+func SaveActivityPollVotes(pollId, userId int64, votes []models.ActivityPollVote) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var optionIds []int64
+	for i := range votes {
+		votes[i].PollId = pollId
+		votes[i].UserId = userId
+		optionIds = append(optionIds, votes[i].OptionId)
+	}
+	if err := tx.Where("option_id IN ? AND user_id = ?", optionIds, userId).
+		Delete(&models.ActivityPollVote{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Create(&votes).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// CountActivityPollVotes 统计投票中每个候选时间各选择的票数
+func CountActivityPollVotes(pollId int64) ([]models.PollVoteCount, error) {
+	var counts []models.PollVoteCount
+	if err := config.DB.Model(&models.ActivityPollVote{}).
+		Select("option_id, choice, COUNT(*) AS count").
+		Where("poll_id = ?", pollId).
+		Group("option_id, choice").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// GetActivityPollVotesByUserId 获取用户在投票中的所有选择
+func GetActivityPollVotesByUserId(pollId, userId int64) ([]models.ActivityPollVote, error) {
+	var votes []models.ActivityPollVote
+	if err := config.DB.Where("poll_id = ? AND user_id = ?", pollId, userId).
+		Find(&votes).Error; err != nil {
+		return nil, err
+	}
+	return votes, nil
+}
+
+// GetActivityPollVoterIds 获取参与投票的所有用户Id
+func GetActivityPollVoterIds(pollId int64) ([]int64, error) {
+	var userIds []int64
+	if err := config.DB.Model(&models.ActivityPollVote{}).
+		Where("poll_id = ?", pollId).
+		Distinct().
+		Pluck("user_id", &userIds).Error; err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
+
+// FinalizeActivityPoll 在事务中确定投票结果，将活动开始时间设为所选时间、状态改为未开始，并记录活动修订
+func FinalizeActivityPoll(poll *models.ActivityPoll, option *models.ActivityPollOption, editorId int64, changes []models.ActivityRevisionField, now time.Time) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Model(&models.ActivityPoll{}).
+		Where("id = ?", poll.Id).
+		Updates(map[string]interface{}{
+			"status":          models.PollFinalized,
+			"final_option_id": option.Id,
+			"update_time":     now,
+		}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&models.Activity{}).
+		Where("id = ?", poll.ActivityId).
+		Updates(map[string]interface{}{
+			"start_time":  option.StartTime,
+			"state":       models.ActivityStateNotStarted,
+			"update_time": now,
+		}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := addActivityRevision(tx, poll.ActivityId, editorId, changes, now); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+	poll.Status = models.PollFinalized
+	poll.FinalOptionId = option.Id
+	poll.UpdateTime = now
+	return nil
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddActivityPoll(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	poll := &models.ActivityPoll{ActivityId: 1, CreatorId: 2, CreateTime: now, UpdateTime: now}
+	options := []models.ActivityPollOption{{StartTime: now.Add(time.Hour)}, {StartTime: now.Add(2 * time.Hour)}}
+
+	// 测试成功发起投票
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_poll` WHERE activity_id = ? AND status = ?")).
+		WithArgs(int64(1), models.PollOpen).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_poll`")).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_poll_option` (`poll_id`,`start_time`) VALUES (?,?),(?,?)")).
+		WithArgs(int64(5), options[0].StartTime, int64(5), options[1].StartTime).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	err := AddActivityPoll(poll, options)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), options[0].PollId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试活动已有进行中的投票
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_poll` WHERE activity_id = ? AND status = ?")).
+		WithArgs(int64(1), models.PollOpen).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock2.ExpectRollback()
+
+	err = AddActivityPoll(&models.ActivityPoll{ActivityId: 1}, options)
+	assert.ErrorIs(t, err, custom_errors.ErrPollAlreadyExists)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestSaveActivityPollVotes(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	votes := []models.ActivityPollVote{
+		{OptionId: 1, Choice: models.PollChoiceYes, UpdateTime: now},
+		{OptionId: 2, Choice: models.PollChoiceNo, UpdateTime: now},
+	}
+
+	// 先删除旧的选择再写入新的选择
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_poll_vote` WHERE option_id IN (?,?) AND user_id = ?")).
+		WithArgs(int64(1), int64(2), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_poll_vote` (`poll_id`,`option_id`,`user_id`,`choice`,`update_time`) VALUES (?,?,?,?,?),(?,?,?,?,?)")).
+		WithArgs(int64(9), int64(1), int64(3), models.PollChoiceYes, now, int64(9), int64(2), int64(3), models.PollChoiceNo, now).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	err := SaveActivityPollVotes(9, 3, votes)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountActivityPollVotes(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT option_id, choice, COUNT(*) AS count FROM `activity_poll_vote` WHERE poll_id = ? GROUP BY option_id, choice")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"option_id", "choice", "count"}).
+			AddRow(1, models.PollChoiceYes, 3).
+			AddRow(1, models.PollChoiceNo, 1))
+
+	counts, err := CountActivityPollVotes(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.PollVoteCount{
+		{OptionId: 1, Choice: models.PollChoiceYes, Count: 3},
+		{OptionId: 1, Choice: models.PollChoiceNo, Count: 1},
+	}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetActivityPollVoterIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT DISTINCT `user_id` FROM `activity_poll_vote` WHERE poll_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(2).AddRow(3))
+
+	userIds, err := GetActivityPollVoterIds(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, userIds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFinalizeActivityPoll(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	poll := &models.ActivityPoll{Id: 1, ActivityId: 2, Status: models.PollOpen}
+	option := &models.ActivityPollOption{Id: 3, PollId: 1, StartTime: now.Add(24 * time.Hour)}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity_poll` SET `final_option_id`=?,`status`=?,`update_time`=? WHERE id = ?")).
+		WithArgs(int64(3), models.PollFinalized, now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity` SET `start_time`=?,`state`=?,`update_time`=? WHERE id = ?")).
+		WithArgs(option.StartTime, models.ActivityStateNotStarted, now, int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM `activity_revision` WHERE activity_id = ?")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_revision`")).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_revision_field`")).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	changes := []models.ActivityRevisionField{
+		{Field: "startTime", NewValue: option.StartTime.Format("2006-01-02 15:04:05")},
+		{Field: "state", OldValue: "4", NewValue: "0"},
+	}
+	err := FinalizeActivityPoll(poll, option, 5, changes, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.PollFinalized, poll.Status)
+	assert.Equal(t, int64(3), poll.FinalOptionId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetActivityPollByActivityId(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 活动可以有多次投票，返回最近发起的一次
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_poll` WHERE activity_id = ? ORDER BY id DESC,`activity_poll`.`id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "status"}).AddRow(6, 1, models.PollOpen))
+
+	poll, err := GetActivityPollByActivityId(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(6), poll.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
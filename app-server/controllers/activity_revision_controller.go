@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// addActivityRevision 在给定事务中追加一条活动修订记录，版本号在该活动内递增
+func addActivityRevision(tx *gorm.DB, activityId, editorId int64, changes []models.ActivityRevisionField, now time.Time) error {
+	if len(changes) == 0 {
+		return nil
+	}
+
+	var maxVersion int
+	if err := tx.Model(&models.ActivityRevision{}).
+		Where("activity_id = ?", activityId).
+		Select("COALESCE(MAX(version), 0)").
+		Scan(&maxVersion).Error; err != nil {
+		return err
+	}
+
+	revision := &models.ActivityRevision{
+		ActivityId: activityId,
+		Version:    maxVersion + 1,
+		EditorId:   editorId,
+		CreateTime: now,
+	}
+	if err := tx.Create(revision).Error; err != nil {
+		return err
+	}
+	for i := range changes {
+		changes[i].RevisionId = revision.Id
+	}
+	return tx.Create(&changes).Error
+}
+
+// UpdateActivityWithRevision 在事务中保存活动并记录本次修改的字段
+func UpdateActivityWithRevision(activity *models.Activity, editorId int64, changes []models.ActivityRevisionField) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Save(activity).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := addActivityRevision(tx, activity.Id, editorId, changes, activity.UpdateTime); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetActivityRevisions 分页获取活动的修订记录，按版本号倒序
+func GetActivityRevisions(activityId int64, page, pageSize int) ([]models.ActivityRevision, int64, error) {
+	var revisions []models.ActivityRevision
+	var total int64
+	if err := config.DB.Model(&models.ActivityRevision{}).
+		Where("activity_id = ?", activityId).
+		Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := config.DB.Where("activity_id = ?", activityId).
+		Order("version DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&revisions).Error; err != nil {
+		return nil, 0, err
+	}
+	return revisions, total, nil
+}
+
+// GetActivityRevisionFieldsByRevisionIds 批量获取修订记录的字段变更
+func GetActivityRevisionFieldsByRevisionIds(revisionIds []int64) ([]models.ActivityRevisionField, error) {
+	var fields []models.ActivityRevisionField
+	if len(revisionIds) == 0 {
+		return fields, nil
+	}
+	if err := config.DB.Where("revision_id IN ?", revisionIds).
+		Order("id ASC").
+		Find(&fields).Error; err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
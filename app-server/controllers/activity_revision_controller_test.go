@@ -0,0 +1,110 @@
+package controllers
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateActivityWithRevision(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	activity := &models.Activity{Id: 1, Name: "活动", Addr: "新地点", UserId: 2, UpdateTime: now}
+	changes := []models.ActivityRevisionField{{Field: "addr", OldValue: "旧地点", NewValue: "新地点"}}
+
+	// 测试保存活动并追加版本号递增的修订
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity` SET")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM `activity_revision` WHERE activity_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_revision` (`activity_id`,`version`,`editor_id`,`create_time`) VALUES (?,?,?,?)")).
+		WithArgs(int64(1), 4, int64(2), now).
+		WillReturnResult(sqlmock.NewResult(9, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `activity_revision_field` (`revision_id`,`field`,`old_value`,`new_value`) VALUES (?,?,?,?)")).
+		WithArgs(int64(9), "addr", "旧地点", "新地点").
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := UpdateActivityWithRevision(activity, 2, changes)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 没有字段变化时只保存活动
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `activity` SET")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock2.ExpectCommit()
+
+	err = UpdateActivityWithRevision(activity, 2, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+
+	// 写入修订失败时回滚
+	mock3, teardown3 := SetupMockDB(t)
+	defer teardown3()
+
+	mock3.ExpectBegin()
+	mock3.ExpectExec(regexp.QuoteMeta("UPDATE `activity` SET")).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock3.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(version), 0) FROM `activity_revision`")).
+		WillReturnError(errors.New("query error"))
+	mock3.ExpectRollback()
+
+	err = UpdateActivityWithRevision(activity, 2, changes)
+	assert.EqualError(t, err, "query error")
+	assert.NoError(t, mock3.ExpectationsWereMet())
+}
+
+func TestGetActivityRevisions(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `activity_revision` WHERE activity_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_revision` WHERE activity_id = ? ORDER BY version DESC LIMIT ?")).
+		WithArgs(int64(1), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "activity_id", "version", "editor_id"}).
+			AddRow(2, 1, 2, 3).
+			AddRow(1, 1, 1, 3))
+
+	revisions, total, err := GetActivityRevisions(1, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Equal(t, 2, revisions[0].Version)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetActivityRevisionFieldsByRevisionIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 空列表不查询数据库
+	fields, err := GetActivityRevisionFieldsByRevisionIds(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, fields)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `activity_revision_field` WHERE revision_id IN (?,?) ORDER BY id ASC")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "revision_id", "field", "old_value", "new_value"}).
+			AddRow(1, 1, "addr", "a", "b").
+			AddRow(2, 2, "name", "c", "d"))
+
+	fields, err = GetActivityRevisionFieldsByRevisionIds([]int64{1, 2})
+	assert.NoError(t, err)
+	assert.Len(t, fields, 2)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
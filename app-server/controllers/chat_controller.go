@@ -2,7 +2,14 @@ package controllers
 
 import (
 	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+
 	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
 	"hobbyhub-server/models"
 )
 
@@ -62,3 +69,333 @@ func DeleteChatById(chatId int64, userId int64) error {
 
 	return config.DB.Save(&chat).Error
 }
+
+// GetChatsAfterId 获取用户在指定消息之后收发的、未被自己删除的消息，按Id升序，用于断线重连后补发
+func GetChatsAfterId(userId, lastId int64, limit int) ([]models.Chat, error) {
+	var chats []models.Chat
+	if err := config.DB.Where("id > ?", lastId).
+		Where("(user_id_to = ? AND status_to <> 0) OR (user_id_from = ? AND status_from <> 0)", userId, userId).
+		Order("id ASC").
+		Limit(limit).
+		Find(&chats).Error; err != nil {
+		return nil, err
+	}
+	return chats, nil
+}
+
+// MarkChatsDelivered 将 fromUserId 发给 userId、Id 不大于 upToId 且尚未送达的消息标记为已送达
+func MarkChatsDelivered(userId, fromUserId, upToId int64, now time.Time) (int64, error) {
+	result := config.DB.Model(&models.Chat{}).
+		Where("user_id_to = ? AND user_id_from = ? AND id <= ? AND deliver_time IS NULL", userId, fromUserId, upToId).
+		Update("deliver_time", now)
+	return result.RowsAffected, result.Error
+}
+
+// MarkChatsRead 将 fromUserId 发给 userId、Id 不大于 upToId 的未读消息标记为已读，未送达的同时视为已送达
+func MarkChatsRead(userId, fromUserId, upToId int64, now time.Time) (int64, error) {
+	result := config.DB.Model(&models.Chat{}).
+		Where("user_id_to = ? AND user_id_from = ? AND id <= ? AND read_time IS NULL", userId, fromUserId, upToId).
+		Updates(map[string]interface{}{
+			"read_time":    now,
+			"deliver_time": gorm.Expr("COALESCE(deliver_time, ?)", now),
+		})
+	return result.RowsAffected, result.Error
+}
+
+// CountUnreadChats 按会话统计用户未读且未删除的消息数
+func CountUnreadChats(userId int64) ([]models.ChatUnreadCount, error) {
+	var counts []models.ChatUnreadCount
+	if err := config.DB.Model(&models.Chat{}).
+		Select("user_id_from AS user_id, COUNT(*) AS count").
+		Where("user_id_to = ? AND status_to <> 0 AND read_time IS NULL", userId).
+		Group("user_id_from").
+		Order("user_id_from ASC").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// chatConversationRow 会话列表查询的扫描结果
+type chatConversationRow struct {
+	models.Chat
+	CounterpartId int64
+	Username      string
+	Name          string
+	HeadImg       string
+	UnreadCount   int64
+}
+
+// GetChatConversations 分页获取用户的会话列表，每个聊天对象一行，按最近一条可见消息的时间倒序
+func GetChatConversations(userId int64, page, pageSize int) ([]models.ChatConversation, int64, error) {
+	// 每个聊天对象最近一条自己未删除的消息
+	latest := config.DB.Model(&models.Chat{}).
+		Select("CASE WHEN user_id_from = ? THEN user_id_to ELSE user_id_from END AS counterpart_id, MAX(id) AS last_id", userId).
+		Where("(user_id_from = ? AND status_from <> 0) OR (user_id_to = ? AND status_to <> 0)", userId, userId).
+		Group("counterpart_id")
+
+	var total int64
+	if err := config.DB.Table("(?) AS l", latest).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	unread := config.DB.Model(&models.Chat{}).
+		Select("user_id_from, COUNT(*) AS unread_count").
+		Where("user_id_to = ? AND status_to <> 0 AND read_time IS NULL", userId).
+		Group("user_id_from")
+
+	var rows []chatConversationRow
+	if err := config.DB.Table("(?) AS l", latest).
+		Select("c.*, l.counterpart_id, COALESCE(u.unread_count, 0) AS unread_count, p.username, p.name, p.head_img").
+		Joins("JOIN `chat` c ON c.id = l.last_id").
+		Joins("LEFT JOIN (?) AS u ON u.user_id_from = l.counterpart_id", unread).
+		Joins("LEFT JOIN `user` p ON p.id = l.counterpart_id").
+		Order("c.create_time DESC, c.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	conversations := make([]models.ChatConversation, 0, len(rows))
+	for _, row := range rows {
+		conversations = append(conversations, models.ChatConversation{
+			UserId:      row.CounterpartId,
+			Username:    row.Username,
+			Name:        row.Name,
+			HeadImg:     row.HeadImg,
+			UnreadCount: row.UnreadCount,
+			LastMessage: row.Chat,
+		})
+	}
+	return conversations, total, nil
+}
+
+// GetChatHistory 按 Id 倒序获取两人之间自己可见的消息，beforeId 为0表示从最新开始，
+// startTime、endTime 为零值表示不限制
+func GetChatHistory(userId, counterpartId, beforeId int64, startTime, endTime time.Time, limit int) ([]models.Chat, error) {
+	var chats []models.Chat
+	query := config.DB.Where("(user_id_from = ? AND user_id_to = ? AND status_from <> 0) OR (user_id_from = ? AND user_id_to = ? AND status_to <> 0)",
+		userId, counterpartId, counterpartId, userId)
+	if beforeId > 0 {
+		query = query.Where("id < ?", beforeId)
+	}
+	if !startTime.IsZero() {
+		query = query.Where("create_time >= ?", startTime)
+	}
+	if !endTime.IsZero() {
+		query = query.Where("create_time <= ?", endTime)
+	}
+	if err := query.Order("id DESC").
+		Limit(limit).
+		Find(&chats).Error; err != nil {
+		return nil, err
+	}
+	return chats, nil
+}
+
+// GetChatFileAccess 判断文件（含以其为缩略图或与其关联的文件）是否被聊天消息引用为附件，
+// 以及用户是否为引用该附件的会话参与者
+func GetChatFileAccess(fileId, userId int64) (bool, bool, error) {
+	originals := config.DB.Model(&models.File{}).Select("id").Where("id = ? OR thumbnail_id = ?", fileId, fileId)
+	related := config.DB.Model(&models.File{}).Select("id").Where("id IN (?) OR link_file_id IN (?)", originals, originals)
+
+	var attachedCount int64
+	if err := config.DB.Model(&models.Chat{}).
+		Where("file_id IN (?)", related).
+		Count(&attachedCount).Error; err != nil {
+		return false, false, err
+	}
+	if attachedCount == 0 {
+		return false, false, nil
+	}
+
+	var allowedCount int64
+	if err := config.DB.Model(&models.Chat{}).
+		Where("file_id IN (?)", related).
+		Where("user_id_from = ? OR user_id_to = ?", userId, userId).
+		Count(&allowedCount).Error; err != nil {
+		return false, false, err
+	}
+	return true, allowedCount > 0, nil
+}
+
+// RecallChat 在事务中撤回消息：清除双方可见的内容、附件及编辑历史，仅保留撤回占位
+func RecallChat(chat *models.Chat, now time.Time) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result := tx.Model(&models.Chat{}).
+		Where("id = ? AND recall_time IS NULL", chat.Id).
+		Updates(map[string]interface{}{
+			"msg_type":    models.ChatMsgRecalled,
+			"content":     "",
+			"file_id":     0,
+			"activity_id": 0,
+			"lat":         0,
+			"lon":         0,
+			"recall_time": now,
+		})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return custom_errors.ErrChatRecalled
+	}
+	if err := tx.Where("chat_id = ?", chat.Id).Delete(&models.ChatEdit{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	chat.MsgType = models.ChatMsgRecalled
+	chat.Content = ""
+	chat.FileId = 0
+	chat.ActivityId = 0
+	chat.Lat = 0
+	chat.Lon = 0
+	chat.RecallTime = &now
+	return nil
+}
+
+// EditChat 在事务中编辑消息内容，编辑前的内容保存到编辑历史
+func EditChat(chat *models.Chat, content string, now time.Time) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	result := tx.Model(&models.Chat{}).
+		Where("id = ? AND recall_time IS NULL", chat.Id).
+		Updates(map[string]interface{}{"content": content, "edit_time": now})
+	if result.Error != nil {
+		tx.Rollback()
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		tx.Rollback()
+		return custom_errors.ErrChatRecalled
+	}
+	edit := models.ChatEdit{ChatId: chat.Id, Content: chat.Content, EditTime: now}
+	if err := tx.Create(&edit).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	chat.Content = content
+	chat.EditTime = &now
+	return nil
+}
+
+// GetChatEdits 获取消息的编辑历史，按编辑时间升序
+func GetChatEdits(chatId int64) ([]models.ChatEdit, error) {
+	var edits []models.ChatEdit
+	if err := config.DB.Where("chat_id = ?", chatId).
+		Order("id ASC").
+		Find(&edits).Error; err != nil {
+		return nil, err
+	}
+	return edits, nil
+}
+
+// chatSearchCondition 根据全文检索方式构造关键词匹配条件，关键词过短无法使用分词索引时退化为 LIKE
+func chatSearchCondition(keyword string) (string, []interface{}) {
+	runeCount := utf8.RuneCountInString(keyword)
+	switch {
+	case config.ChatSearchMode == config.ChatSearchFTS5 && runeCount >= 3:
+		// 作为短语匹配，避免用户输入被解析为 FTS5 查询语法
+		phrase := `"` + strings.ReplaceAll(keyword, `"`, `""`) + `"`
+		return "id IN (SELECT rowid FROM chat_fts WHERE chat_fts MATCH ?)", []interface{}{phrase}
+	case config.ChatSearchMode == config.ChatSearchFulltext && runeCount >= 2:
+		phrase := `"` + strings.ReplaceAll(keyword, `"`, " ") + `"`
+		return "MATCH(content) AGAINST (? IN BOOLEAN MODE)", []interface{}{phrase}
+	default:
+		escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(keyword)
+		return "content LIKE ? ESCAPE '!'", []interface{}{"%" + escaped + "%"}
+	}
+}
+
+// SearchChats 在用户自己可见且未撤回的私聊消息中检索关键词，按Id倒序分页。
+// counterpartId 为0表示不限制会话对象，startTime、endTime 为零值表示不限制
+func SearchChats(userId, counterpartId int64, keyword string, startTime, endTime time.Time, page, pageSize int) ([]models.Chat, int64, error) {
+	var chats []models.Chat
+	var total int64
+	query := config.DB.Model(&models.Chat{})
+	if counterpartId != 0 {
+		query = query.Where("(user_id_from = ? AND user_id_to = ? AND status_from <> 0) OR (user_id_from = ? AND user_id_to = ? AND status_to <> 0)",
+			userId, counterpartId, counterpartId, userId)
+	} else {
+		query = query.Where("(user_id_from = ? AND status_from <> 0) OR (user_id_to = ? AND status_to <> 0)", userId, userId)
+	}
+	condition, args := chatSearchCondition(keyword)
+	query = query.Where("recall_time IS NULL").Where(condition, args...)
+	if !startTime.IsZero() {
+		query = query.Where("create_time >= ?", startTime)
+	}
+	if !endTime.IsZero() {
+		query = query.Where("create_time <= ?", endTime)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&chats).Error; err != nil {
+		return nil, 0, err
+	}
+	return chats, total, nil
+}
+
+// GetChatContext 获取同一会话中指定消息前后各 size 条用户自己可见的消息，均按Id升序
+func GetChatContext(userId int64, chat *models.Chat, size int) ([]models.Chat, []models.Chat, error) {
+	counterpartId := chat.UserIdTo
+	if chat.UserIdTo == userId {
+		counterpartId = chat.UserIdFrom
+	}
+	visible := func() *gorm.DB {
+		return config.DB.Where("(user_id_from = ? AND user_id_to = ? AND status_from <> 0) OR (user_id_from = ? AND user_id_to = ? AND status_to <> 0)",
+			userId, counterpartId, counterpartId, userId)
+	}
+
+	var before []models.Chat
+	if err := visible().Where("id < ?", chat.Id).
+		Order("id DESC").
+		Limit(size).
+		Find(&before).Error; err != nil {
+		return nil, nil, err
+	}
+	for i, j := 0, len(before)-1; i < j; i, j = i+1, j-1 {
+		before[i], before[j] = before[j], before[i]
+	}
+
+	var after []models.Chat
+	if err := visible().Where("id > ?", chat.Id).
+		Order("id ASC").
+		Limit(size).
+		Find(&after).Error; err != nil {
+		return nil, nil, err
+	}
+	return before, after, nil
+}
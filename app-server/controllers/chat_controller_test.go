@@ -4,7 +4,10 @@ import (
 	"errors"
 	"regexp"
 	"testing"
+	"time"
 
+	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
 	"hobbyhub-server/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -140,7 +143,7 @@ func TestDeleteChatById(t *testing.T) {
 
 	mock.ExpectBegin()
 	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat` SET")).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, chatId).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, nil, nil, "", 0, 0, 0.0, 0.0, nil, nil, chatId).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock.ExpectCommit()
 
@@ -164,7 +167,7 @@ func TestDeleteChatById(t *testing.T) {
 
 	mock2.ExpectBegin()
 	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `chat` SET")).
-		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, sqlmock.AnyArg(), chatId).
+		WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), sqlmock.AnyArg(), 0, sqlmock.AnyArg(), nil, nil, "", 0, 0, 0.0, 0.0, nil, nil, chatId).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 	mock2.ExpectCommit()
 
@@ -185,3 +188,318 @@ func TestDeleteChatById(t *testing.T) {
 	assert.EqualError(t, err, "query error")
 	assert.NoError(t, mock3.ExpectationsWereMet())
 }
+
+func TestGetChatsAfterId(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat` WHERE id > ? AND ((user_id_to = ? AND status_to <> 0) OR (user_id_from = ? AND status_from <> 0)) ORDER BY id ASC LIMIT ?")).
+		WithArgs(int64(10), int64(1), int64(1), 201).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id_from", "user_id_to", "content"}).
+			AddRow(11, 2, 1, "你好").
+			AddRow(12, 1, 2, "在吗"))
+
+	chats, err := GetChatsAfterId(1, 10, 201)
+	assert.NoError(t, err)
+	assert.Len(t, chats, 2)
+	assert.Equal(t, int64(11), chats[0].Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkChatsRead(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+
+	// 测试标记已读时补齐送达时间
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat` SET `deliver_time`=COALESCE(deliver_time, ?),`read_time`=? WHERE user_id_to = ? AND user_id_from = ? AND id <= ? AND read_time IS NULL")).
+		WithArgs(now, now, int64(2), int64(1), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectCommit()
+
+	updated, err := MarkChatsRead(2, 1, 10, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkChatsDelivered(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat` SET `deliver_time`=? WHERE user_id_to = ? AND user_id_from = ? AND id <= ? AND deliver_time IS NULL")).
+		WithArgs(now, int64(2), int64(1), int64(10)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	updated, err := MarkChatsDelivered(2, 1, 10, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountUnreadChats(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user_id_from AS user_id, COUNT(*) AS count FROM `chat` WHERE user_id_to = ? AND status_to <> 0 AND read_time IS NULL GROUP BY `user_id_from` ORDER BY user_id_from ASC")).
+		WithArgs(int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "count"}).AddRow(1, 3).AddRow(4, 1))
+
+	counts, err := CountUnreadChats(2)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ChatUnreadCount{{UserId: 1, Count: 3}, {UserId: 4, Count: 1}}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChatConversations(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	latest := "SELECT CASE WHEN user_id_from = ? THEN user_id_to ELSE user_id_from END AS counterpart_id, MAX(id) AS last_id FROM `chat` WHERE (user_id_from = ? AND status_from <> 0) OR (user_id_to = ? AND status_to <> 0) GROUP BY `counterpart_id`"
+
+	// 测试统计聊天对象数并按最近消息分页
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM ("+latest+") AS l")).
+		WithArgs(int64(1), int64(1), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT c.*, l.counterpart_id, COALESCE(u.unread_count, 0) AS unread_count, p.username, p.name, p.head_img FROM ("+latest+") AS l JOIN `chat` c ON c.id = l.last_id LEFT JOIN (SELECT user_id_from, COUNT(*) AS unread_count FROM `chat` WHERE user_id_to = ? AND status_to <> 0 AND read_time IS NULL GROUP BY `user_id_from`) AS u ON u.user_id_from = l.counterpart_id LEFT JOIN `user` p ON p.id = l.counterpart_id ORDER BY c.create_time DESC, c.id DESC LIMIT ?")).
+		WithArgs(int64(1), int64(1), int64(1), int64(1), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id_from", "user_id_to", "content", "counterpart_id", "unread_count", "username", "name", "head_img"}).
+			AddRow(9, 3, 1, "最新", 3, 2, "user3", "用户3", "3.jpg").
+			AddRow(5, 1, 2, "较早", 2, 0, "user2", "用户2", ""))
+
+	conversations, total, err := GetChatConversations(1, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, conversations, 2)
+	assert.Equal(t, int64(3), conversations[0].UserId)
+	assert.Equal(t, int64(2), conversations[0].UnreadCount)
+	assert.Equal(t, "用户3", conversations[0].Name)
+	assert.Equal(t, "最新", conversations[0].LastMessage.Content)
+	assert.Equal(t, int64(2), conversations[1].UserId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChatHistory(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	visible := "(user_id_from = ? AND user_id_to = ? AND status_from <> 0) OR (user_id_from = ? AND user_id_to = ? AND status_to <> 0)"
+
+	// 测试不带游标和时间过滤
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat` WHERE "+visible+" ORDER BY id DESC LIMIT ?")).
+		WithArgs(int64(1), int64(2), int64(2), int64(1), 20).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id_from", "user_id_to", "content"}).
+			AddRow(3, 2, 1, "c").
+			AddRow(2, 1, 2, "b"))
+
+	chats, err := GetChatHistory(1, 2, 0, time.Time{}, time.Time{}, 20)
+	assert.NoError(t, err)
+	assert.Len(t, chats, 2)
+	assert.Equal(t, int64(3), chats[0].Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试游标及时间范围都下推到查询中
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat` WHERE ("+visible+") AND id < ? AND create_time >= ? AND create_time <= ? ORDER BY id DESC LIMIT ?")).
+		WithArgs(int64(1), int64(2), int64(2), int64(1), int64(3), start, end, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id_from", "user_id_to", "content"}).AddRow(2, 1, 2, "b"))
+
+	chats, err = GetChatHistory(1, 2, 3, start, end, 10)
+	assert.NoError(t, err)
+	assert.Len(t, chats, 1)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetChatFileAccess(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	relatedQuery := "file_id IN (SELECT `id` FROM `file` WHERE id IN (SELECT `id` FROM `file` WHERE id = ? OR thumbnail_id = ?) OR link_file_id IN (SELECT `id` FROM `file` WHERE id = ? OR thumbnail_id = ?))"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `chat` WHERE "+relatedQuery)).
+		WithArgs(int64(5), int64(5), int64(5), int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `chat` WHERE "+relatedQuery+" AND (user_id_from = ? OR user_id_to = ?)")).
+		WithArgs(int64(5), int64(5), int64(5), int64(5), int64(3), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	attached, allowed, err := GetChatFileAccess(5, 3)
+	assert.NoError(t, err)
+	assert.True(t, attached)
+	assert.False(t, allowed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 未被任何消息引用的文件不做额外限制
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `chat` WHERE "+relatedQuery)).
+		WithArgs(int64(6), int64(6), int64(6), int64(6)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+
+	attached, allowed, err = GetChatFileAccess(6, 3)
+	assert.NoError(t, err)
+	assert.False(t, attached)
+	assert.False(t, allowed)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRecallChat(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	chat := &models.Chat{Id: 1, UserIdFrom: 1, UserIdTo: 2, Content: "发错了", MsgType: models.ChatMsgText}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat` SET `activity_id`=?,`content`=?,`file_id`=?,`lat`=?,`lon`=?,`msg_type`=?,`recall_time`=? WHERE id = ? AND recall_time IS NULL")).
+		WithArgs(0, "", 0, 0, 0, models.ChatMsgRecalled, now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `chat_edit` WHERE chat_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err := RecallChat(chat, now)
+	assert.NoError(t, err)
+	assert.Equal(t, models.ChatMsgRecalled, chat.MsgType)
+	assert.Empty(t, chat.Content)
+	assert.Equal(t, &now, chat.RecallTime)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 已撤回的消息不能重复撤回
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat` SET")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = RecallChat(&models.Chat{Id: 1}, now)
+	assert.ErrorIs(t, err, custom_errors.ErrChatRecalled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEditChat(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	chat := &models.Chat{Id: 1, UserIdFrom: 1, UserIdTo: 2, Content: "明天见", MsgType: models.ChatMsgText}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat` SET `content`=?,`edit_time`=? WHERE id = ? AND recall_time IS NULL")).
+		WithArgs("后天见", now, int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `chat_edit` (`chat_id`,`content`,`edit_time`) VALUES (?,?,?)")).
+		WithArgs(int64(1), "明天见", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := EditChat(chat, "后天见", now)
+	assert.NoError(t, err)
+	assert.Equal(t, "后天见", chat.Content)
+	assert.Equal(t, &now, chat.EditTime)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 已撤回的消息不能编辑
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat` SET `content`=?,`edit_time`=? WHERE id = ? AND recall_time IS NULL")).
+		WithArgs("再改", now, int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+
+	err = EditChat(&models.Chat{Id: 2, Content: "原文"}, "再改", now)
+	assert.ErrorIs(t, err, custom_errors.ErrChatRecalled)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetChatEdits(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_edit` WHERE chat_id = ? ORDER BY id ASC")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "chat_id", "content"}).
+			AddRow(1, 1, "明天见").
+			AddRow(2, 1, "后天见"))
+
+	edits, err := GetChatEdits(1)
+	assert.NoError(t, err)
+	assert.Len(t, edits, 2)
+	assert.Equal(t, "明天见", edits[0].Content)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchChats(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 未建立全文索引时使用 LIKE，并转义通配符
+	where := "WHERE ((user_id_from = ? AND status_from <> 0) OR (user_id_to = ? AND status_to <> 0)) AND recall_time IS NULL AND content LIKE ? ESCAPE '!'"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `chat` "+where)).
+		WithArgs(int64(1), int64(1), "%100!%%").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat` "+where+" ORDER BY id DESC LIMIT ?")).
+		WithArgs(int64(1), int64(1), "%100!%%", 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id_from", "user_id_to", "content"}).
+			AddRow(5, 2, 1, "折扣100%"))
+
+	chats, total, err := SearchChats(1, 0, "100%", time.Time{}, time.Time{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, chats, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSearchChatsFTS5(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	config.ChatSearchMode = config.ChatSearchFTS5
+	defer func() { config.ChatSearchMode = config.ChatSearchLike }()
+
+	startTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.Local)
+	where := "WHERE ((user_id_from = ? AND user_id_to = ? AND status_from <> 0) OR (user_id_from = ? AND user_id_to = ? AND status_to <> 0)) AND recall_time IS NULL AND id IN (SELECT rowid FROM chat_fts WHERE chat_fts MATCH ?) AND create_time >= ?"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `chat` "+where)).
+		WithArgs(int64(1), int64(2), int64(2), int64(1), `"人民路"`, startTime).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat` "+where+" ORDER BY id DESC LIMIT ?")).
+		WithArgs(int64(1), int64(2), int64(2), int64(1), `"人民路"`, startTime, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	_, total, err := SearchChats(1, 2, "人民路", startTime, time.Time{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 少于3个字符时 trigram 无法匹配，退化为 LIKE
+	condition, args := chatSearchCondition("地址")
+	assert.Equal(t, "content LIKE ? ESCAPE '!'", condition)
+	assert.Equal(t, []interface{}{"%地址%"}, args)
+}
+
+func TestGetChatContext(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	chat := &models.Chat{Id: 10, UserIdFrom: 2, UserIdTo: 1}
+	visible := "WHERE ((user_id_from = ? AND user_id_to = ? AND status_from <> 0) OR (user_id_from = ? AND user_id_to = ? AND status_to <> 0))"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat` "+visible+" AND id < ? ORDER BY id DESC LIMIT ?")).
+		WithArgs(int64(1), int64(2), int64(2), int64(1), int64(10), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(9).AddRow(7))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat` "+visible+" AND id > ? ORDER BY id ASC LIMIT ?")).
+		WithArgs(int64(1), int64(2), int64(2), int64(1), int64(10), 2).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+
+	before, after, err := GetChatContext(1, chat, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), before[0].Id)
+	assert.Equal(t, int64(9), before[1].Id)
+	assert.Len(t, after, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,316 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+)
+
+// CreateChatRoom 在事务中创建群聊并添加初始成员，群主自动成为成员
+func CreateChatRoom(room *models.ChatRoom, memberIds []int64) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(room).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := addChatRoomMembers(tx, room, append([]int64{room.OwnerId}, memberIds...), room.CreateTime); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// addChatRoomMembers 批量添加群成员，已在群中的用户会被跳过，群主以群主角色加入
+func addChatRoomMembers(tx *gorm.DB, room *models.ChatRoom, userIds []int64, now time.Time) error {
+	var existingIds []int64
+	if err := tx.Model(&models.ChatRoomMember{}).
+		Where("room_id = ?", room.Id).
+		Pluck("user_id", &existingIds).Error; err != nil {
+		return err
+	}
+	seen := make(map[int64]bool)
+	for _, userId := range existingIds {
+		seen[userId] = true
+	}
+
+	var members []models.ChatRoomMember
+	for _, userId := range userIds {
+		if seen[userId] {
+			continue
+		}
+		seen[userId] = true
+		role := models.ChatRoomRoleMember
+		if userId == room.OwnerId {
+			role = models.ChatRoomRoleOwner
+		}
+		members = append(members, models.ChatRoomMember{RoomId: room.Id, UserId: userId, Role: role, JoinTime: now})
+	}
+	if len(members) == 0 {
+		return nil
+	}
+	return tx.Create(&members).Error
+}
+
+// AddChatRoomMembers 向群聊添加成员
+func AddChatRoomMembers(room *models.ChatRoom, userIds []int64, now time.Time) error {
+	return addChatRoomMembers(config.DB, room, userIds, now)
+}
+
+// GetChatRoomById 获取指定群聊
+func GetChatRoomById(roomId int64) (*models.ChatRoom, error) {
+	var room models.ChatRoom
+	if err := config.DB.Where("id = ?", roomId).First(&room).Error; err != nil {
+		return nil, err
+	}
+	return &room, nil
+}
+
+// UpdateChatRoom 更新群聊信息
+func UpdateChatRoom(room *models.ChatRoom) error {
+	return config.DB.Save(room).Error
+}
+
+// EnsureActivityChatRoom 获取活动群聊，不存在时以活动参与者为成员创建
+func EnsureActivityChatRoom(activity *models.Activity, participantIds []int64, now time.Time) (*models.ChatRoom, error) {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var room models.ChatRoom
+	err := tx.Where("type = ? AND activity_id = ?", models.ChatRoomTypeActivity, activity.Id).First(&room).Error
+	if err == nil {
+		tx.Rollback()
+		return &room, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return nil, err
+	}
+
+	room = models.ChatRoom{
+		Type:       models.ChatRoomTypeActivity,
+		ActivityId: activity.Id,
+		Name:       activity.Name,
+		OwnerId:    activity.UserId,
+		CreateTime: now,
+		UpdateTime: now,
+	}
+	if err := tx.Create(&room).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := addChatRoomMembers(tx, &room, append([]int64{room.OwnerId}, participantIds...), now); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	return &room, tx.Commit().Error
+}
+
+// AddActivityChatRoomMember 将新加入活动的用户同步到活动群聊，活动尚无群聊时不做处理
+func AddActivityChatRoomMember(activityId, userId int64, now time.Time) error {
+	var room models.ChatRoom
+	err := config.DB.Where("type = ? AND activity_id = ?", models.ChatRoomTypeActivity, activityId).First(&room).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return addChatRoomMembers(config.DB, &room, []int64{userId}, now)
+}
+
+// RemoveActivityChatRoomMember 将退出活动的用户移出活动群聊
+func RemoveActivityChatRoomMember(activityId, userId int64) error {
+	return config.DB.Where("user_id = ? AND room_id IN (?)", userId,
+		config.DB.Model(&models.ChatRoom{}).
+			Select("id").
+			Where("type = ? AND activity_id = ?", models.ChatRoomTypeActivity, activityId)).
+		Delete(&models.ChatRoomMember{}).Error
+}
+
+// GetChatRoomsByUserId 分页获取用户加入的群聊，按最后活跃时间倒序
+func GetChatRoomsByUserId(userId int64, page, pageSize int) ([]models.ChatRoom, int64, error) {
+	var rooms []models.ChatRoom
+	var total int64
+	query := config.DB.Model(&models.ChatRoom{}).
+		Where("id IN (?)", config.DB.Model(&models.ChatRoomMember{}).Select("room_id").Where("user_id = ?", userId))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("update_time DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&rooms).Error; err != nil {
+		return nil, 0, err
+	}
+	return rooms, total, nil
+}
+
+// GetChatRoomMember 获取用户在群聊中的成员记录
+func GetChatRoomMember(roomId, userId int64) (*models.ChatRoomMember, error) {
+	var member models.ChatRoomMember
+	if err := config.DB.Where("room_id = ? AND user_id = ?", roomId, userId).First(&member).Error; err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// IsChatRoomMember 判断用户是否为群聊成员
+func IsChatRoomMember(roomId, userId int64) (bool, error) {
+	var count int64
+	if err := config.DB.Model(&models.ChatRoomMember{}).
+		Where("room_id = ? AND user_id = ?", roomId, userId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetChatRoomMembers 获取群聊的所有成员
+func GetChatRoomMembers(roomId int64) ([]models.ChatRoomMember, error) {
+	var members []models.ChatRoomMember
+	if err := config.DB.Where("room_id = ?", roomId).
+		Order("join_time ASC, id ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// RemoveChatRoomMember 在事务中将用户移出群聊，群主离开时由最早加入的成员接任
+func RemoveChatRoomMember(room *models.ChatRoom, userId int64) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := removeChatRoomMember(tx, room, userId); err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// removeChatRoomMember 在给定事务中将用户移出群聊，群主离开时由最早加入的成员接任
+func removeChatRoomMember(tx *gorm.DB, room *models.ChatRoom, userId int64) error {
+	result := tx.Where("room_id = ? AND user_id = ?", room.Id, userId).Delete(&models.ChatRoomMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return custom_errors.ErrNotChatRoomMember
+	}
+
+	if userId == room.OwnerId {
+		var next models.ChatRoomMember
+		err := tx.Where("room_id = ?", room.Id).Order("join_time ASC, id ASC").First(&next).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&next).Update("role", models.ChatRoomRoleOwner).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(room).Update("owner_id", next.UserId).Error; err != nil {
+			return err
+		}
+		room.OwnerId = next.UserId
+	}
+	return nil
+}
+
+// AddChatRoomMessage 在事务中保存群消息并刷新群聊的最后活跃时间
+func AddChatRoomMessage(message *models.ChatRoomMessage) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Create(message).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&models.ChatRoom{}).
+		Where("id = ?", message.RoomId).
+		Update("update_time", message.CreateTime).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetChatRoomMessageById 获取指定群消息
+func GetChatRoomMessageById(messageId int64) (*models.ChatRoomMessage, error) {
+	var message models.ChatRoomMessage
+	if err := config.DB.Where("id = ?", messageId).First(&message).Error; err != nil {
+		return nil, err
+	}
+	return &message, nil
+}
+
+// GetChatRoomMessages 分页获取群聊消息，按时间倒序，排除该用户已删除的消息
+func GetChatRoomMessages(roomId, userId int64, page, pageSize int) ([]models.ChatRoomMessage, int64, error) {
+	var messages []models.ChatRoomMessage
+	var total int64
+	query := config.DB.Model(&models.ChatRoomMessage{}).
+		Where("room_id = ?", roomId).
+		Where("id NOT IN (?)", config.DB.Model(&models.ChatRoomMessageDeletion{}).Select("message_id").Where("user_id = ?", userId))
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("create_time DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&messages).Error; err != nil {
+		return nil, 0, err
+	}
+	return messages, total, nil
+}
+
+// DeleteChatRoomMessageForUser 为用户删除一条群消息，其他成员仍可见
+func DeleteChatRoomMessageForUser(messageId, userId int64, now time.Time) error {
+	deletion := models.ChatRoomMessageDeletion{MessageId: messageId, UserId: userId}
+	return config.DB.Where("message_id = ? AND user_id = ?", messageId, userId).
+		Attrs(models.ChatRoomMessageDeletion{CreateTime: now}).
+		FirstOrCreate(&deletion).Error
+}
@@ -0,0 +1,220 @@
+package controllers
+
+import (
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateChatRoom(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	room := &models.ChatRoom{Name: "群聊", OwnerId: 1, CreateTime: now, UpdateTime: now}
+
+	// 测试创建群聊时群主以群主角色加入，重复的成员只添加一次
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `chat_room`")).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `user_id` FROM `chat_room_member` WHERE room_id = ?")).
+		WithArgs(int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `chat_room_member` (`room_id`,`user_id`,`role`,`join_time`) VALUES (?,?,?,?),(?,?,?,?)")).
+		WithArgs(int64(5), int64(1), models.ChatRoomRoleOwner, now, int64(5), int64(2), models.ChatRoomRoleMember, now).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	err := CreateChatRoom(room, []int64{2, 1, 2})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), room.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestEnsureActivityChatRoom(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	activity := &models.Activity{Id: 3, Name: "活动", UserId: 1}
+
+	// 已有群聊时直接返回
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_room` WHERE type = ? AND activity_id = ?")).
+		WithArgs(models.ChatRoomTypeActivity, int64(3), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "type", "activity_id"}).AddRow(7, models.ChatRoomTypeActivity, 3))
+	mock.ExpectRollback()
+
+	room, err := EnsureActivityChatRoom(activity, []int64{1, 2}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), room.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 不存在时以参与者为成员创建
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_room` WHERE type = ? AND activity_id = ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+	mock2.ExpectExec(regexp.QuoteMeta("INSERT INTO `chat_room`")).
+		WillReturnResult(sqlmock.NewResult(8, 1))
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT `user_id` FROM `chat_room_member` WHERE room_id = ?")).
+		WithArgs(int64(8)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+	mock2.ExpectExec(regexp.QuoteMeta("INSERT INTO `chat_room_member`")).
+		WithArgs(int64(8), int64(1), models.ChatRoomRoleOwner, now, int64(8), int64(2), models.ChatRoomRoleMember, now).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock2.ExpectCommit()
+
+	room, err = EnsureActivityChatRoom(activity, []int64{1, 2}, now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(8), room.Id)
+	assert.Equal(t, "活动", room.Name)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestAddActivityChatRoomMember(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+
+	// 活动尚无群聊时不做处理
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_room` WHERE type = ? AND activity_id = ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	err := AddActivityChatRoomMember(3, 2, now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 已在群中的用户不重复添加
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_room` WHERE type = ? AND activity_id = ?")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id"}).AddRow(7, 1))
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT `user_id` FROM `chat_room_member` WHERE room_id = ?")).
+		WithArgs(int64(7)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(1).AddRow(2))
+
+	err = AddActivityChatRoomMember(3, 2, now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestRemoveActivityChatRoomMember(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `chat_room_member` WHERE user_id = ? AND room_id IN (SELECT `id` FROM `chat_room` WHERE type = ? AND activity_id = ?)")).
+		WithArgs(int64(2), models.ChatRoomTypeActivity, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := RemoveActivityChatRoomMember(3, 2)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestRemoveChatRoomMember(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	room := &models.ChatRoom{Id: 5, OwnerId: 1}
+
+	// 群主退出时由最早加入的成员接任
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `chat_room_member` WHERE room_id = ? AND user_id = ?")).
+		WithArgs(int64(5), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_room_member` WHERE room_id = ? ORDER BY join_time ASC, id ASC")).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "user_id"}).AddRow(9, 5, 3))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat_room_member` SET `role`=? WHERE `id` = ?")).
+		WithArgs(models.ChatRoomRoleOwner, int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat_room` SET `owner_id`=? WHERE `id` = ?")).
+		WithArgs(int64(3), int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := RemoveChatRoomMember(room, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), room.OwnerId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 非成员返回错误
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("DELETE FROM `chat_room_member`")).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock2.ExpectRollback()
+
+	err = RemoveChatRoomMember(room, 4)
+	assert.ErrorIs(t, err, custom_errors.ErrNotChatRoomMember)
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestAddChatRoomMessage(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	message := &models.ChatRoomMessage{RoomId: 5, UserIdFrom: 1, Content: "你好", CreateTime: now}
+
+	// 测试发送消息并刷新群聊活跃时间
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `chat_room_message` (`room_id`,`user_id_from`,`content`,`create_time`) VALUES (?,?,?,?)")).
+		WithArgs(int64(5), int64(1), "你好", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat_room` SET `update_time`=? WHERE id = ?")).
+		WithArgs(now, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := AddChatRoomMessage(message)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 插入失败时回滚
+	mock2, teardown2 := SetupMockDB(t)
+	defer teardown2()
+
+	mock2.ExpectBegin()
+	mock2.ExpectExec(regexp.QuoteMeta("INSERT INTO `chat_room_message`")).
+		WillReturnError(errors.New("insert error"))
+	mock2.ExpectRollback()
+
+	err = AddChatRoomMessage(&models.ChatRoomMessage{RoomId: 5, UserIdFrom: 1, Content: "你好", CreateTime: now})
+	assert.EqualError(t, err, "insert error")
+	assert.NoError(t, mock2.ExpectationsWereMet())
+}
+
+func TestGetChatRoomMessages(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 测试排除用户已删除的消息
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `chat_room_message` WHERE room_id = ? AND id NOT IN (SELECT `message_id` FROM `chat_room_message_deletion` WHERE user_id = ?)")).
+		WithArgs(int64(5), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_room_message` WHERE room_id = ? AND id NOT IN (SELECT `message_id` FROM `chat_room_message_deletion` WHERE user_id = ?) ORDER BY create_time DESC, id DESC LIMIT ?")).
+		WithArgs(int64(5), int64(2), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "user_id_from", "content"}).AddRow(1, 5, 1, "你好"))
+
+	messages, total, err := GetChatRoomMessages(5, 2, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Len(t, messages, 1)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
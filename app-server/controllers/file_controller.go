@@ -45,3 +45,26 @@ func DeleteFileById(fileId int64) error {
 	}
 	return nil
 }
+
+// GetFilesByIds 批量获取文件信息
+func GetFilesByIds(fileIds []int64) ([]models.File, error) {
+	var files []models.File
+	if len(fileIds) == 0 {
+		return files, nil
+	}
+	if err := config.DB.Where("id IN ?", fileIds).Find(&files).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// CountFilesByThumbnailId 统计使用指定缩略图的文件数量
+func CountFilesByThumbnailId(thumbnailId int64) (int64, error) {
+	var count int64
+	if err := config.DB.Model(&models.File{}).
+		Where("thumbnail_id = ?", thumbnailId).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
@@ -191,3 +191,17 @@ func TestDeleteFileById(t *testing.T) {
 	assert.EqualError(t, err, "delete error")
 	assert.NoError(t, mock2.ExpectationsWereMet())
 }
+
+func TestCountFilesByThumbnailId(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `file` WHERE thumbnail_id = ?")).
+		WithArgs(int64(9)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	count, err := CountFilesByThumbnailId(9)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -1,113 +1,257 @@
 package controllers
 
 import (
+	"time"
+
+	"gorm.io/gorm"
+
 	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
 	"hobbyhub-server/models"
 )
 
-func GetAllFriends() ([]models.Friend, error) {
-	var friends []models.Friend
-	if err := config.DB.Find(&friends).Error; err != nil {
-		return nil, err
+// friendEdgeSQL 将每条好友关系展开为双方各一条的 (user_id, friend_id, status) 记录，便于按单个用户查询
+const friendEdgeSQL = "SELECT user_low_id AS user_id, user_high_id AS friend_id, status FROM friendship " +
+	"UNION ALL SELECT user_high_id AS user_id, user_low_id AS friend_id, status FROM friendship"
+
+// friendshipRemoved 操作后好友关系记录应被删除
+const friendshipRemoved = -1
+
+// nextFriendshipStatus 校验 actorId 对好友关系执行操作是否合法并返回操作后的状态，friendship 为 nil 表示两人之间没有关系
+func nextFriendshipStatus(friendship *models.Friendship, actorId int64, action string) (int, error) {
+	status := friendshipRemoved
+	if friendship != nil {
+		status = friendship.Status
 	}
-	return friends, nil
+	pending := status == models.FriendshipPending
+	requestedByActor := pending && friendship.RequesterId == actorId
+
+	switch action {
+	case models.FriendActionRequest:
+		switch {
+		case status == models.FriendshipAccepted:
+			return 0, custom_errors.ErrFriendAlreadyExists
+		case requestedByActor:
+			return 0, custom_errors.ErrFriendRequestAlreadySent
+		case pending:
+			// 对方已向自己发出申请，直接成为好友
+			return models.FriendshipAccepted, nil
+		}
+		return models.FriendshipPending, nil
+	case models.FriendActionAccept, models.FriendActionReject:
+		if !pending || requestedByActor {
+			return 0, custom_errors.ErrFriendRequestNotFound
+		}
+		if action == models.FriendActionAccept {
+			return models.FriendshipAccepted, nil
+		}
+		return models.FriendshipRejected, nil
+	case models.FriendActionCancel:
+		if !requestedByActor {
+			return 0, custom_errors.ErrFriendRequestNotFound
+		}
+		return friendshipRemoved, nil
+	case models.FriendActionUnfriend:
+		if status != models.FriendshipAccepted {
+			return 0, custom_errors.ErrNotFriends
+		}
+		return friendshipRemoved, nil
+	case models.FriendActionBlock:
+		return friendshipRemoved, nil
+	}
+	return 0, custom_errors.ErrInvalidFriendAction
 }
 
-// AddFriend 添加好友关系
-func AddFriend(friend *models.Friend) error {
-	if err := config.DB.Create(friend).Error; err != nil {
-		return err
+// ApplyFriendAction 在事务中由 actorId 对与 userId 的好友关系执行操作，返回操作后的关系，关系被删除时返回 nil。
+// 申请时间早于 expireBefore 的待处理申请视为已拒绝，expireBefore 为零值时申请不会过期
+func ApplyFriendAction(actorId, userId int64, action, message string, now, expireBefore time.Time) (*models.Friendship, error) {
+	if actorId == userId {
+		return nil, custom_errors.ErrFriendSelf
 	}
-	_friend := models.Friend{
-		UserId:     friend.FriendId,
-		FriendId:   friend.UserId,
-		Status:     2,
-		CreateTime: friend.CreateTime,
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
 	}
-	if err := config.DB.Create(&_friend).Error; err != nil {
-		return err
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	friendship, err := applyFriendAction(tx, actorId, userId, action, message, now, expireBefore)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
 	}
-	return nil
+	return friendship, nil
 }
 
-// GetFriendById 获取好友关系详情
-func GetFriendById(friendId int64) (*models.Friend, error) {
-	var friend models.Friend
-	if err := config.DB.Where("id = ?", friendId).First(&friend).Error; err != nil {
+// applyFriendAction 在给定事务中执行好友关系操作，供需要与其他数据一同提交的操作（如屏蔽）复用
+func applyFriendAction(tx *gorm.DB, actorId, userId int64, action, message string, now, expireBefore time.Time) (*models.Friendship, error) {
+	low, high := models.FriendshipPair(actorId, userId)
+	var friendships []models.Friendship
+	if err := tx.Where("user_low_id = ? AND user_high_id = ?", low, high).Limit(1).Find(&friendships).Error; err != nil {
 		return nil, err
 	}
-	return &friend, nil
-}
+	var friendship *models.Friendship
+	if len(friendships) > 0 {
+		friendship = &friendships[0]
+	}
 
-// GetAllFriendsByUserId 获取用户的所有好友关系
-func GetAllFriendsByUserId(userId int64) ([]models.Friend, error) {
-	var friends []models.Friend
+	if friendship != nil && friendship.Status == models.FriendshipPending &&
+		!expireBefore.IsZero() && friendship.RequestTime.Before(expireBefore) {
+		switch action {
+		case models.FriendActionAccept, models.FriendActionReject, models.FriendActionCancel:
+			return nil, custom_errors.ErrFriendRequestExpired
+		}
+		// 过期的申请视为已拒绝，可以重新发起申请
+		friendship.Status = models.FriendshipRejected
+	}
 
-	if err := config.DB.Where("user_id = ?", userId).
-		Find(&friends).Error; err != nil {
+	next, err := nextFriendshipStatus(friendship, actorId, action)
+	if err != nil {
 		return nil, err
 	}
 
-	return friends, nil
-}
+	if next == friendshipRemoved {
+		if friendship == nil {
+			return nil, nil
+		}
+		if err := tx.Delete(&models.Friendship{}, friendship.Id).Error; err != nil {
+			return nil, err
+		}
+		// 关系解除后双方的分组及备注不再保留
+		if friendship.Status == models.FriendshipAccepted {
+			if err := deleteFriendOrganization(tx, actorId, userId); err != nil {
+				return nil, err
+			}
+		}
+		return nil, nil
+	}
 
-// UpdateFriend 更新好友关系
-func UpdateFriend(friend *models.Friend) error {
-	if err := config.DB.Save(friend).Error; err != nil {
-		return err
+	if friendship == nil {
+		friendship = &models.Friendship{UserLowId: low, UserHighId: high, CreateTime: now}
+	}
+	if next == models.FriendshipPending {
+		friendship.RequesterId = actorId
+		friendship.Message = message
+		friendship.RequestTime = now
 	}
-	return nil
+	friendship.Status = next
+	friendship.UpdateTime = now
+	if err := tx.Save(friendship).Error; err != nil {
+		return nil, err
+	}
+	return friendship, nil
 }
 
-// UpdateFriendSynchronize 更新两个好友关系，确保两边的状态一致
-func UpdateFriendSynchronize(friend1 *models.Friend, friend2 *models.Friend) error {
-	// 开启事务
-	tx := config.DB.Begin()
-
-	// 事务操作出错时进行回滚的函数
-	defer func() {
-		if r := recover(); r != nil {
-			tx.Rollback()
-		}
-	}()
-
-	// 在事务中更新第一个好友关系
-	if err := tx.Save(friend1).Error; err != nil {
-		tx.Rollback() // 回滚事务
-		return err
+// GetFriendshipById 获取好友关系详情
+func GetFriendshipById(friendshipId int64) (*models.Friendship, error) {
+	var friendship models.Friendship
+	if err := config.DB.Where("id = ?", friendshipId).First(&friendship).Error; err != nil {
+		return nil, err
 	}
+	return &friendship, nil
+}
 
-	// 在事务中更新第二个好友关系
-	if err := tx.Save(friend2).Error; err != nil {
-		tx.Rollback() // 回滚事务
-		return err
+// GetFriendshipsByUserId 获取用户参与的所有好友关系
+func GetFriendshipsByUserId(userId int64) ([]models.Friendship, error) {
+	var friendships []models.Friendship
+	if err := config.DB.Where("user_low_id = ? OR user_high_id = ?", userId, userId).
+		Order("id ASC").
+		Find(&friendships).Error; err != nil {
+		return nil, err
 	}
+	return friendships, nil
+}
 
-	// 提交事务
-	return tx.Commit().Error
+// AreFriends 判断两个用户是否已互为好友
+func AreFriends(userId, friendId int64) (bool, error) {
+	low, high := models.FriendshipPair(userId, friendId)
+	var count int64
+	if err := config.DB.Model(&models.Friendship{}).
+		Where("user_low_id = ? AND user_high_id = ? AND status = ?", low, high, models.FriendshipAccepted).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
 }
 
-// DeleteFriendById 删除好友关系
-func DeleteFriendById(friendId int64) error {
-	if err := config.DB.Delete(&models.Friend{}, friendId).Error; err != nil {
-		return err
+// GetAcceptedFriendIds 获取已互为好友的用户Id
+func GetAcceptedFriendIds(userId int64) ([]int64, error) {
+	var friendIds []int64
+	if err := config.DB.Table("("+friendEdgeSQL+") AS friend_edge").
+		Where("user_id = ? AND status = ?", userId, models.FriendshipAccepted).
+		Pluck("friend_id", &friendIds).Error; err != nil {
+		return nil, err
 	}
-	return nil
+	return friendIds, nil
 }
 
-// GetFriendByUserIdAndFriendId 获取两个用户之间的好友关系
-func GetFriendByUserIdAndFriendId(userId, friendId int64) (*models.Friend, *models.Friend, error) {
-	var friends1 *models.Friend
-	var friends2 *models.Friend
+// GetFriendRelatedUserIds 获取已是好友或有待处理好友申请（任一方向）的用户Id
+func GetFriendRelatedUserIds(userId int64) ([]int64, error) {
+	var userIds []int64
+	if err := config.DB.Table("("+friendEdgeSQL+") AS friend_edge").
+		Where("user_id = ? AND status IN ?", userId, []int{models.FriendshipAccepted, models.FriendshipPending}).
+		Pluck("friend_id", &userIds).Error; err != nil {
+		return nil, err
+	}
+	return userIds, nil
+}
 
-	// First query
-	if err := config.DB.Where("user_id = ? AND friend_id = ?", userId, friendId).Find(&friends1).Error; err != nil {
-		return nil, nil, err
+// GetFriendRequests 分页获取用户收到（incoming 为 true）或发出的、申请时间不早于 since 的待处理好友申请，按申请时间倒序。
+// since 为零值时不限制申请时间
+func GetFriendRequests(userId int64, incoming bool, since time.Time, page, pageSize int) ([]models.FriendRequestInfo, int64, error) {
+	requesterCondition := "friendship.requester_id = ?"
+	if incoming {
+		requesterCondition = "friendship.requester_id <> ?"
+	}
+	query := config.DB.Table("friendship").
+		Joins("JOIN user ON user.id = CASE WHEN friendship.user_low_id = ? THEN friendship.user_high_id ELSE friendship.user_low_id END", userId).
+		Where("(friendship.user_low_id = ? OR friendship.user_high_id = ?) AND friendship.status = ?", userId, userId, models.FriendshipPending).
+		Where(requesterCondition, userId)
+	if !since.IsZero() {
+		query = query.Where("friendship.request_time >= ?", since)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
 	}
 
-	// Second query
-	if err := config.DB.Where("user_id = ? AND friend_id = ?", friendId, userId).Find(&friends2).Error; err != nil {
-		return nil, nil, err
+	var rows []struct {
+		UserId      int64
+		Name        string
+		Gender      string
+		HeadImg     string
+		Message     string
+		RequestTime time.Time
+	}
+	if err := query.Select("user.id AS user_id, user.name, user.gender, user.head_img, friendship.message, friendship.request_time").
+		Order("friendship.request_time DESC, friendship.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&rows).Error; err != nil {
+		return nil, 0, err
 	}
-	return friends1, friends2, nil
+	requests := make([]models.FriendRequestInfo, 0, len(rows))
+	for _, row := range rows {
+		requests = append(requests, models.FriendRequestInfo{
+			User:        models.PublicUser{Id: row.UserId, Name: row.Name, Gender: row.Gender, HeadImg: row.HeadImg},
+			Message:     row.Message,
+			RequestTime: row.RequestTime,
+		})
+	}
+	return requests, total, nil
+}
+
+// ExpireFriendRequests 将申请时间早于 before 的待处理好友申请置为拒绝状态，返回更新的记录数
+func ExpireFriendRequests(before, now time.Time) (int64, error) {
+	result := config.DB.Model(&models.Friendship{}).
+		Where("status = ? AND request_time < ?", models.FriendshipPending, before).
+		Updates(map[string]interface{}{"status": models.FriendshipRejected, "update_time": now})
+	return result.RowsAffected, result.Error
 }
@@ -6,6 +6,7 @@ import (
 	"testing"
 	"time"
 
+	"hobbyhub-server/custom_errors"
 	"hobbyhub-server/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -13,319 +14,295 @@ import (
 	"gorm.io/gorm"
 )
 
-func TestAddFriend(t *testing.T) {
+var friendshipColumns = []string{"id", "user_low_id", "user_high_id", "requester_id", "status", "message", "request_time", "create_time", "update_time"}
+
+func TestNextFriendshipStatus(t *testing.T) {
+	pending := &models.Friendship{UserLowId: 1, UserHighId: 2, RequesterId: 1, Status: models.FriendshipPending}
+	accepted := &models.Friendship{UserLowId: 1, UserHighId: 2, RequesterId: 1, Status: models.FriendshipAccepted}
+	rejected := &models.Friendship{UserLowId: 1, UserHighId: 2, RequesterId: 1, Status: models.FriendshipRejected}
+
+	cases := []struct {
+		name       string
+		friendship *models.Friendship
+		actorId    int64
+		action     string
+		want       int
+		err        error
+	}{
+		{"首次申请", nil, 1, models.FriendActionRequest, models.FriendshipPending, nil},
+		{"被拒绝后重新申请", rejected, 2, models.FriendActionRequest, models.FriendshipPending, nil},
+		{"重复申请", pending, 1, models.FriendActionRequest, 0, custom_errors.ErrFriendRequestAlreadySent},
+		{"对方已申请时直接成为好友", pending, 2, models.FriendActionRequest, models.FriendshipAccepted, nil},
+		{"已是好友时申请", accepted, 2, models.FriendActionRequest, 0, custom_errors.ErrFriendAlreadyExists},
+		{"接受对方的申请", pending, 2, models.FriendActionAccept, models.FriendshipAccepted, nil},
+		{"申请人不能接受自己的申请", pending, 1, models.FriendActionAccept, 0, custom_errors.ErrFriendRequestNotFound},
+		{"拒绝对方的申请", pending, 2, models.FriendActionReject, models.FriendshipRejected, nil},
+		{"没有待处理申请时拒绝", accepted, 2, models.FriendActionReject, 0, custom_errors.ErrFriendRequestNotFound},
+		{"撤回自己的申请", pending, 1, models.FriendActionCancel, friendshipRemoved, nil},
+		{"不能撤回对方的申请", pending, 2, models.FriendActionCancel, 0, custom_errors.ErrFriendRequestNotFound},
+		{"解除好友", accepted, 2, models.FriendActionUnfriend, friendshipRemoved, nil},
+		{"不是好友时解除", pending, 2, models.FriendActionUnfriend, 0, custom_errors.ErrNotFriends},
+		{"没有关系时解除", nil, 1, models.FriendActionUnfriend, 0, custom_errors.ErrNotFriends},
+		{"屏蔽", pending, 2, models.FriendActionBlock, friendshipRemoved, nil},
+		{"没有关系时屏蔽", nil, 1, models.FriendActionBlock, friendshipRemoved, nil},
+		{"未知操作", accepted, 1, "follow", 0, custom_errors.ErrInvalidFriendAction},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, err := nextFriendshipStatus(tc.friendship, tc.actorId, tc.action)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tc.want, status)
+		})
+	}
+}
+
+func TestApplyFriendAction(t *testing.T) {
+	now := time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+	requestTime := time.Date(2024, 1, 9, 8, 0, 0, 0, time.UTC)
+	createTime := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	selectSQL := regexp.QuoteMeta("SELECT * FROM `friendship` WHERE user_low_id = ? AND user_high_id = ? LIMIT ?")
+
+	// 测试场景1：首次申请，用户Id较大的一方发出申请时按较小的Id在前保存
 	mock, teardown := SetupMockDB(t)
 	defer teardown()
 
-	friend := &models.Friend{UserId: 1, FriendId: 2, CreateTime: time.Now()}
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectSQL).
+		WithArgs(int64(2), int64(5), 1).
+		WillReturnRows(sqlmock.NewRows(friendshipColumns))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `friendship` (`user_low_id`,`user_high_id`,`requester_id`,`status`,`message`,`request_time`,`create_time`,`update_time`) VALUES (?,?,?,?,?,?,?,?)")).
+		WithArgs(int64(2), int64(5), int64(5), models.FriendshipPending, "一起爬山吧", now, now, now).
+		WillReturnResult(sqlmock.NewResult(7, 1))
+	mock.ExpectCommit()
+
+	friendship, err := ApplyFriendAction(5, 2, models.FriendActionRequest, "一起爬山吧", now, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(7), friendship.Id)
+	assert.Equal(t, models.FriendStatusOutgoing, friendship.StatusFor(5))
+	assert.Equal(t, models.FriendStatusIncoming, friendship.StatusFor(2))
+	assert.NoError(t, mock.ExpectationsWereMet())
 
-	// 测试成功场景：两个INSERT都成功
-	// 第一次插入原始朋友关系
+	// 测试场景2：拒绝申请时状态0也会被保存
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `friend`")).
-		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(selectSQL).
+		WithArgs(int64(2), int64(5), 1).
+		WillReturnRows(sqlmock.NewRows(friendshipColumns).
+			AddRow(7, 2, 5, 5, models.FriendshipPending, "", requestTime, createTime, requestTime))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friendship` SET `user_low_id`=?,`user_high_id`=?,`requester_id`=?,`status`=?,`message`=?,`request_time`=?,`create_time`=?,`update_time`=? WHERE `id` = ?")).
+		WithArgs(int64(2), int64(5), int64(5), models.FriendshipRejected, "", requestTime, createTime, now, int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 	mock.ExpectCommit()
 
-	// 第二次插入反向朋友关系
+	friendship, err = ApplyFriendAction(2, 5, models.FriendActionReject, "", now, time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, models.FriendshipRejected, friendship.Status)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试场景3：申请人不能接受自己发出的申请
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectSQL).
+		WithArgs(int64(2), int64(5), 1).
+		WillReturnRows(sqlmock.NewRows(friendshipColumns).
+			AddRow(7, 2, 5, 5, models.FriendshipPending, "", requestTime, createTime, requestTime))
+	mock.ExpectRollback()
+
+	_, err = ApplyFriendAction(5, 2, models.FriendActionAccept, "", now, time.Time{})
+	assert.ErrorIs(t, err, custom_errors.ErrFriendRequestNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试场景4：过期的申请不能再接受
+	mock.ExpectBegin()
+	mock.ExpectQuery(selectSQL).
+		WithArgs(int64(2), int64(5), 1).
+		WillReturnRows(sqlmock.NewRows(friendshipColumns).
+			AddRow(7, 2, 5, 5, models.FriendshipPending, "", requestTime, createTime, requestTime))
+	mock.ExpectRollback()
+
+	_, err = ApplyFriendAction(2, 5, models.FriendActionAccept, "", now, now.Add(-time.Hour))
+	assert.ErrorIs(t, err, custom_errors.ErrFriendRequestExpired)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试场景5：解除好友时删除关系
 	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `friend`")).
-		WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectQuery(selectSQL).
+		WithArgs(int64(2), int64(5), 1).
+		WillReturnRows(sqlmock.NewRows(friendshipColumns).
+			AddRow(7, 2, 5, 5, models.FriendshipAccepted, "", requestTime, createTime, requestTime))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friendship` WHERE `friendship`.`id` = ?")).
+		WithArgs(int64(7)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_group_member` WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)")).
+		WithArgs(int64(2), int64(5), int64(5), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_remark` WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)")).
+		WithArgs(int64(2), int64(5), int64(5), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
 	mock.ExpectCommit()
 
-	err := AddFriend(friend)
+	friendship, err = ApplyFriendAction(2, 5, models.FriendActionUnfriend, "", now, time.Time{})
 	assert.NoError(t, err)
+	assert.Nil(t, friendship)
 	assert.NoError(t, mock.ExpectationsWereMet())
 
-	// 测试第一次插入失败场景
-	mock2, teardown2 := SetupMockDB(t)
-	defer teardown2()
-
-	mock2.ExpectBegin()
-	mock2.ExpectExec(regexp.QuoteMeta("INSERT INTO `friend`")).
-		WillReturnError(errors.New("insert error"))
-	mock2.ExpectRollback()
-
-	err = AddFriend(friend)
-	assert.EqualError(t, err, "insert error")
-	assert.NoError(t, mock2.ExpectationsWereMet())
-
-	// 测试第二次插入失败场景
-	mock3, teardown3 := SetupMockDB(t)
-	defer teardown3()
-
-	// 第一次插入成功
-	mock3.ExpectBegin()
-	mock3.ExpectExec(regexp.QuoteMeta("INSERT INTO `friend`")).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock3.ExpectCommit()
-
-	// 第二次插入失败
-	mock3.ExpectBegin()
-	mock3.ExpectExec(regexp.QuoteMeta("INSERT INTO `friend`")).
-		WillReturnError(errors.New("second insert error"))
-	mock3.ExpectRollback()
-
-	err = AddFriend(friend)
-	assert.EqualError(t, err, "second insert error")
-	assert.NoError(t, mock3.ExpectationsWereMet())
+	// 测试场景6：不能与自己建立关系
+	_, err = ApplyFriendAction(2, 2, models.FriendActionRequest, "", now, time.Time{})
+	assert.ErrorIs(t, err, custom_errors.ErrFriendSelf)
 }
 
-func TestGetFriendById(t *testing.T) {
+func TestGetFriendshipById(t *testing.T) {
 	mock, teardown := SetupMockDB(t)
 	defer teardown()
 
-	friendId := int64(1)
-	expectedFriend := &models.Friend{
-		Id:       friendId,
-		UserId:   1,
-		FriendId: 2,
-	}
-
-	rows := sqlmock.NewRows([]string{"id", "user_id", "friend_id"}).
-		AddRow(expectedFriend.Id, expectedFriend.UserId, expectedFriend.FriendId)
+	createTime := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friendship` WHERE id = ? ORDER BY `friendship`.`id` LIMIT ?")).
+		WithArgs(int64(7), 1).
+		WillReturnRows(sqlmock.NewRows(friendshipColumns).
+			AddRow(7, 2, 5, 5, models.FriendshipAccepted, "", createTime, createTime, createTime))
 
-	// Test successful friend retrieval
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friend` WHERE id = ? ORDER BY `friend`.`id` LIMIT ?")).
-		WithArgs(friendId, 1).
-		WillReturnRows(rows)
-
-	friend, err := GetFriendById(friendId)
+	friendship, err := GetFriendshipById(7)
 	assert.NoError(t, err)
-	assert.Equal(t, expectedFriend.Id, friend.Id)
-	assert.Equal(t, expectedFriend.UserId, friend.UserId)
-	assert.Equal(t, expectedFriend.FriendId, friend.FriendId)
-	assert.NoError(t, mock.ExpectationsWereMet())
+	assert.Equal(t, int64(5), friendship.OtherUserId(2))
+	assert.Equal(t, int64(2), friendship.OtherUserId(5))
 
-	// Test friend not found
-	mock2, teardown2 := SetupMockDB(t)
-	defer teardown2()
-
-	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friend` WHERE id = ? ORDER BY `friend`.`id` LIMIT ?")).
-		WithArgs(friendId, 1).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friendship` WHERE id = ? ORDER BY `friendship`.`id` LIMIT ?")).
+		WithArgs(int64(8), 1).
 		WillReturnError(gorm.ErrRecordNotFound)
 
-	friend, err = GetFriendById(friendId)
-	assert.Nil(t, friend)
-	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
-	assert.NoError(t, mock2.ExpectationsWereMet())
+	_, err = GetFriendshipById(8)
+	assert.True(t, errors.Is(err, gorm.ErrRecordNotFound))
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdateFriend(t *testing.T) {
+func TestGetFriendshipsByUserId(t *testing.T) {
 	mock, teardown := SetupMockDB(t)
 	defer teardown()
 
-	friend := models.Friend{Id: 1, UserId: 1, FriendId: 3}
-
-	// Test successful friend update
-	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friend` SET")).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectCommit()
+	createTime := time.Date(2024, 1, 1, 8, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friendship` WHERE user_low_id = ? OR user_high_id = ? ORDER BY id ASC")).
+		WithArgs(int64(2), int64(2)).
+		WillReturnRows(sqlmock.NewRows(friendshipColumns).
+			AddRow(7, 2, 5, 5, models.FriendshipPending, "", createTime, createTime, createTime).
+			AddRow(9, 1, 2, 1, models.FriendshipAccepted, "", createTime, createTime, createTime))
 
-	err := UpdateFriend(&friend)
+	friendships, err := GetFriendshipsByUserId(2)
 	assert.NoError(t, err)
+	assert.Len(t, friendships, 2)
+	assert.Equal(t, models.FriendStatusIncoming, friendships[0].StatusFor(2))
+	assert.Equal(t, models.FriendStatusAccepted, friendships[1].StatusFor(2))
 	assert.NoError(t, mock.ExpectationsWereMet())
-
-	// Test friend update error
-	mock2, teardown2 := SetupMockDB(t)
-	defer teardown2()
-
-	mock2.ExpectBegin()
-	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `friend` SET")).
-		WillReturnError(errors.New("update error"))
-	mock2.ExpectRollback()
-
-	err = UpdateFriend(&friend)
-	assert.EqualError(t, err, "update error")
-	assert.NoError(t, mock2.ExpectationsWereMet())
 }
 
-func TestDeleteFriendById(t *testing.T) {
+func TestAreFriends(t *testing.T) {
 	mock, teardown := SetupMockDB(t)
 	defer teardown()
 
-	friendId := int64(1)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `friendship` WHERE user_low_id = ? AND user_high_id = ? AND status = ?")).
+		WithArgs(int64(1), int64(2), models.FriendshipAccepted).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
 
-	// Test successful friend deletion
-	mock.ExpectBegin()
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend` WHERE `friend`.`id` = ?")).
-		WithArgs(friendId).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-	mock.ExpectCommit()
-
-	err := DeleteFriendById(friendId)
+	isFriend, err := AreFriends(2, 1)
 	assert.NoError(t, err)
-	assert.NoError(t, mock.ExpectationsWereMet())
-
-	// Test friend deletion error
-	mock2, teardown2 := SetupMockDB(t)
-	defer teardown2()
+	assert.True(t, isFriend)
 
-	mock2.ExpectBegin()
-	mock2.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend` WHERE `friend`.`id` = ?")).
-		WithArgs(friendId).
-		WillReturnError(errors.New("delete error"))
-	mock2.ExpectRollback()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `friendship` WHERE user_low_id = ? AND user_high_id = ? AND status = ?")).
+		WithArgs(int64(1), int64(3), models.FriendshipAccepted).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
 
-	err = DeleteFriendById(friendId)
-	assert.EqualError(t, err, "delete error")
-	assert.NoError(t, mock2.ExpectationsWereMet())
+	isFriend, err = AreFriends(1, 3)
+	assert.NoError(t, err)
+	assert.False(t, isFriend)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestGetFriendByUserIdAndFriendId(t *testing.T) {
+func TestGetAcceptedFriendIds(t *testing.T) {
 	mock, teardown := SetupMockDB(t)
 	defer teardown()
 
-	userId := int64(1)
-	friendId := int64(2)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `friend_id` FROM ("+friendEdgeSQL+") AS friend_edge WHERE user_id = ? AND status = ?")).
+		WithArgs(int64(1), models.FriendshipAccepted).
+		WillReturnRows(sqlmock.NewRows([]string{"friend_id"}).AddRow(2).AddRow(3))
 
-	// 创建第一个方向的好友关系（userId -> friendId）
-	friend1 := &models.Friend{
-		Id:       1,
-		UserId:   userId,
-		FriendId: friendId,
-	}
-
-	// 创建第二个方向的好友关系（friendId -> userId）
-	friend2 := &models.Friend{
-		Id:       2,
-		UserId:   friendId,
-		FriendId: userId,
-	}
-
-	// 第一个查询的结果行
-	rows1 := sqlmock.NewRows([]string{"id", "user_id", "friend_id"}).
-		AddRow(friend1.Id, friend1.UserId, friend1.FriendId)
-
-	// 第二个查询的结果行
-	rows2 := sqlmock.NewRows([]string{"id", "user_id", "friend_id"}).
-		AddRow(friend2.Id, friend2.UserId, friend2.FriendId)
+	friendIds, err := GetAcceptedFriendIds(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, friendIds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	// 测试成功获取两个方向的好友关系
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friend` WHERE user_id = ? AND friend_id = ?")).
-		WithArgs(userId, friendId).
-		WillReturnRows(rows1)
+func TestGetFriendRelatedUserIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
 
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friend` WHERE user_id = ? AND friend_id = ?")).
-		WithArgs(friendId, userId).
-		WillReturnRows(rows2)
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `friend_id` FROM ("+friendEdgeSQL+") AS friend_edge WHERE user_id = ? AND status IN (?,?)")).
+		WithArgs(int64(1), models.FriendshipAccepted, models.FriendshipPending).
+		WillReturnRows(sqlmock.NewRows([]string{"friend_id"}).AddRow(2).AddRow(5))
 
-	friendA, friendB, err := GetFriendByUserIdAndFriendId(userId, friendId)
+	userIds, err := GetFriendRelatedUserIds(1)
 	assert.NoError(t, err)
-	assert.NotNil(t, friendA) // 应该返回第一个好友关系
-	assert.NotNil(t, friendB) // 应该返回第二个好友关系
-
-	// 验证第一个好友关系
-	assert.Equal(t, friend1.Id, friendA.Id)
-	assert.Equal(t, friend1.UserId, friendA.UserId)
-	assert.Equal(t, friend1.FriendId, friendA.FriendId)
+	assert.Equal(t, []int64{2, 5}, userIds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
 
-	// 验证第二个好友关系
-	assert.Equal(t, friend2.Id, friendB.Id)
-	assert.Equal(t, friend2.UserId, friendB.UserId)
-	assert.Equal(t, friend2.FriendId, friendB.FriendId)
+func TestGetFriendRequests(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
 
+	since := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	requestTime := time.Date(2024, 1, 10, 8, 0, 0, 0, time.UTC)
+	fromSQL := "FROM `friendship` JOIN user ON user.id = CASE WHEN friendship.user_low_id = ? THEN friendship.user_high_id ELSE friendship.user_low_id END " +
+		"WHERE ((friendship.user_low_id = ? OR friendship.user_high_id = ?) AND friendship.status = ?) AND friendship.requester_id <> ?"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) "+fromSQL+" AND friendship.request_time >= ?")).
+		WithArgs(int64(1), int64(1), int64(1), models.FriendshipPending, int64(1), since).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user.id AS user_id, user.name, user.gender, user.head_img, friendship.message, friendship.request_time "+
+		fromSQL+" AND friendship.request_time >= ? ORDER BY friendship.request_time DESC, friendship.id DESC LIMIT ? OFFSET ?")).
+		WithArgs(int64(1), int64(1), int64(1), models.FriendshipPending, int64(1), since, 2, 2).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "name", "gender", "head_img", "message", "request_time"}).
+			AddRow(5, "小红", "female", "head.png", "一起爬山吧", requestTime))
+
+	requests, total, err := GetFriendRequests(1, true, since, 2, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), total)
+	assert.Equal(t, []models.FriendRequestInfo{{
+		User:        models.PublicUser{Id: 5, Name: "小红", Gender: "female", HeadImg: "head.png"},
+		Message:     "一起爬山吧",
+		RequestTime: requestTime,
+	}}, requests)
 	assert.NoError(t, mock.ExpectationsWereMet())
 
-	// 测试查询失败的情况
-	mock2, teardown2 := SetupMockDB(t)
-	defer teardown2()
+	// 发出的申请，since 为零值时不限制申请时间
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `friendship` JOIN user ON user.id = CASE WHEN friendship.user_low_id = ? THEN friendship.user_high_id ELSE friendship.user_low_id END "+
+		"WHERE ((friendship.user_low_id = ? OR friendship.user_high_id = ?) AND friendship.status = ?) AND friendship.requester_id = ?")).
+		WithArgs(int64(1), int64(1), int64(1), models.FriendshipPending, int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT user.id AS user_id")).
+		WithArgs(int64(1), int64(1), int64(1), models.FriendshipPending, int64(1), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "name", "gender", "head_img", "message", "request_time"}))
 
-	mock2.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friend` WHERE user_id = ? AND friend_id = ?")).
-		WithArgs(userId, friendId).
-		WillReturnError(errors.New("query error"))
-
-	friendA, friendB, err = GetFriendByUserIdAndFriendId(userId, friendId)
-	assert.Nil(t, friendA)
-	assert.Nil(t, friendB)
-	assert.EqualError(t, err, "query error")
-	assert.NoError(t, mock2.ExpectationsWereMet())
+	requests, total, err = GetFriendRequests(1, false, time.Time{}, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.Empty(t, requests)
+	assert.NoError(t, mock.ExpectationsWereMet())
 }
 
-func TestUpdateFriendSynchronize(t *testing.T) {
-	// 测试场景1：成功同步更新两个好友关系
+func TestExpireFriendRequests(t *testing.T) {
 	mock, teardown := SetupMockDB(t)
 	defer teardown()
 
-	friend1 := &models.Friend{
-		Id:       1,
-		UserId:   1,
-		FriendId: 2,
-		Status:   1, // 假设要更新的状态
-	}
-
-	friend2 := &models.Friend{
-		Id:       2,
-		UserId:   2,
-		FriendId: 1,
-		Status:   1, // 假设要更新的状态
-	}
-
-	// 期望开启事务
+	before := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	now := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
 	mock.ExpectBegin()
-
-	// 期望更新第一个好友关系成功
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friend` SET")).
-		WithArgs(
-			friend1.UserId,
-			friend1.FriendId,
-			friend1.Status,
-			sqlmock.AnyArg(), // create_time
-			1,                // id
-		).WillReturnResult(sqlmock.NewResult(1, 1))
-
-	// 期望更新第二个好友关系成功
-	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friend` SET")).
-		WithArgs(
-			friend2.UserId,
-			friend2.FriendId,
-			friend2.Status,
-			sqlmock.AnyArg(), // create_time
-			2,                // id
-		).WillReturnResult(sqlmock.NewResult(2, 1))
-
-	// 期望提交事务
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friendship` SET `status`=?,`update_time`=? WHERE status = ? AND request_time < ?")).
+		WithArgs(models.FriendshipRejected, now, models.FriendshipPending, before).
+		WillReturnResult(sqlmock.NewResult(0, 2))
 	mock.ExpectCommit()
 
-	// 执行测试
-	err := UpdateFriendSynchronize(friend1, friend2)
+	expired, err := ExpireFriendRequests(before, now)
 	assert.NoError(t, err)
+	assert.Equal(t, int64(2), expired)
 	assert.NoError(t, mock.ExpectationsWereMet())
-
-	// 测试场景2：第二次更新失败应回滚
-	mock2, teardown2 := SetupMockDB(t)
-	defer teardown2()
-
-	// 期望开启事务
-	mock2.ExpectBegin()
-
-	// 期望更新第一个好友关系成功
-	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `friend` SET")).
-		WillReturnResult(sqlmock.NewResult(1, 1))
-
-	// 期望更新第二个好友关系失败
-	mock2.ExpectExec(regexp.QuoteMeta("UPDATE `friend` SET")).
-		WillReturnError(errors.New("update error"))
-
-	// 期望回滚事务
-	mock2.ExpectRollback()
-
-	// 执行测试
-	err = UpdateFriendSynchronize(friend1, friend2)
-	assert.Error(t, err)
-	assert.EqualError(t, err, "update error")
-	assert.NoError(t, mock2.ExpectationsWereMet())
-
-	// 测试场景3：事务开始失败
-	mock3, teardown3 := SetupMockDB(t)
-	defer teardown3()
-
-	// 期望开启事务失败
-	mock3.ExpectBegin().WillReturnError(errors.New("transaction error"))
-
-	// 执行测试
-	err = UpdateFriendSynchronize(friend1, friend2)
-	assert.Error(t, err)
-	assert.EqualError(t, err, "transaction error")
-	assert.NoError(t, mock3.ExpectationsWereMet())
 }
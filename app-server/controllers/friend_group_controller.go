@@ -0,0 +1,208 @@
+package controllers
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// CreateFriendGroup 创建好友分组，排在用户已有分组的最后
+func CreateFriendGroup(group *models.FriendGroup) error {
+	tx := config.DB.Begin()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var maxSortOrder int
+	if err := tx.Model(&models.FriendGroup{}).
+		Select("COALESCE(MAX(sort_order), 0)").
+		Where("user_id = ?", group.UserId).
+		Scan(&maxSortOrder).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	group.SortOrder = maxSortOrder + 1
+	if err := tx.Create(group).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetFriendGroupById 获取好友分组详情
+func GetFriendGroupById(groupId int64) (*models.FriendGroup, error) {
+	var group models.FriendGroup
+	if err := config.DB.Where("id = ?", groupId).First(&group).Error; err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// GetFriendGroupsByUserId 获取用户的所有好友分组，按排序及创建顺序排列
+func GetFriendGroupsByUserId(userId int64) ([]models.FriendGroup, error) {
+	var groups []models.FriendGroup
+	if err := config.DB.Where("user_id = ?", userId).
+		Order("sort_order ASC, id ASC").
+		Find(&groups).Error; err != nil {
+		return nil, err
+	}
+	return groups, nil
+}
+
+// FriendGroupNameExists 判断用户是否已有同名分组，excludeId 为重命名时排除的分组Id
+func FriendGroupNameExists(userId int64, name string, excludeId int64) (bool, error) {
+	var count int64
+	if err := config.DB.Model(&models.FriendGroup{}).
+		Where("user_id = ? AND name = ? AND id <> ?", userId, name, excludeId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// UpdateFriendGroup 更新好友分组
+func UpdateFriendGroup(group *models.FriendGroup) error {
+	return config.DB.Save(group).Error
+}
+
+// DeleteFriendGroup 删除好友分组及其中的成员记录，好友关系本身不受影响
+func DeleteFriendGroup(groupId int64) error {
+	tx := config.DB.Begin()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("group_id = ?", groupId).Delete(&models.FriendGroupMember{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(&models.FriendGroup{}, groupId).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// ReorderFriendGroups 按 groupIds 的顺序重新设置用户分组的排序，groupIds 需包含用户的全部分组
+func ReorderFriendGroups(userId int64, groupIds []int64, now time.Time) error {
+	tx := config.DB.Begin()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	for i, groupId := range groupIds {
+		if err := tx.Model(&models.FriendGroup{}).
+			Where("id = ? AND user_id = ?", groupId, userId).
+			Updates(map[string]interface{}{"sort_order": i + 1, "update_time": now}).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// SetFriendGroupMembers 用新的好友列表替换分组原有的全部成员
+func SetFriendGroupMembers(group *models.FriendGroup, friendIds []int64, now time.Time) error {
+	tx := config.DB.Begin()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("group_id = ?", group.Id).Delete(&models.FriendGroupMember{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(friendIds) > 0 {
+		members := make([]models.FriendGroupMember, 0, len(friendIds))
+		for _, friendId := range friendIds {
+			members = append(members, models.FriendGroupMember{GroupId: group.Id, UserId: group.UserId, FriendId: friendId, CreateTime: now})
+		}
+		if err := tx.Create(&members).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := tx.Model(group).Update("update_time", now).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// GetFriendGroupMembersByUserId 获取用户所有分组中的成员记录，按加入顺序排列
+func GetFriendGroupMembersByUserId(userId int64) ([]models.FriendGroupMember, error) {
+	var members []models.FriendGroupMember
+	if err := config.DB.Where("user_id = ?", userId).
+		Order("id ASC").
+		Find(&members).Error; err != nil {
+		return nil, err
+	}
+	return members, nil
+}
+
+// GetFriendGroupMemberIds 获取用户指定分组中的好友Id，多个分组中的同一好友只返回一次
+func GetFriendGroupMemberIds(userId int64, groupIds []int64) ([]int64, error) {
+	var friendIds []int64
+	if err := config.DB.Model(&models.FriendGroupMember{}).
+		Where("user_id = ? AND group_id IN ?", userId, groupIds).
+		Group("friend_id").
+		Order("MIN(id)").
+		Pluck("friend_id", &friendIds).Error; err != nil {
+		return nil, err
+	}
+	return friendIds, nil
+}
+
+// SetFriendRemark 设置好友备注名，remark 为空时删除备注
+func SetFriendRemark(userId, friendId int64, remark string, now time.Time) error {
+	if remark == "" {
+		return config.DB.Where("user_id = ? AND friend_id = ?", userId, friendId).Delete(&models.FriendRemark{}).Error
+	}
+	friendRemark := models.FriendRemark{UserId: userId, FriendId: friendId, Remark: remark, UpdateTime: now}
+	return config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "friend_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"remark", "update_time"}),
+	}).Create(&friendRemark).Error
+}
+
+// GetFriendRemarks 获取用户设置的所有好友备注，键为好友Id
+func GetFriendRemarks(userId int64) (map[int64]string, error) {
+	var remarks []models.FriendRemark
+	if err := config.DB.Where("user_id = ?", userId).Find(&remarks).Error; err != nil {
+		return nil, err
+	}
+	result := make(map[int64]string, len(remarks))
+	for _, remark := range remarks {
+		result[remark.FriendId] = remark.Remark
+	}
+	return result, nil
+}
+
+// deleteFriendOrganization 删除两个用户互相设置的分组成员及备注，在好友关系解除时调用
+func deleteFriendOrganization(tx *gorm.DB, userId, otherId int64) error {
+	pair := "(user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)"
+	if err := tx.Where(pair, userId, otherId, otherId, userId).Delete(&models.FriendGroupMember{}).Error; err != nil {
+		return err
+	}
+	return tx.Where(pair, userId, otherId, otherId, userId).Delete(&models.FriendRemark{}).Error
+}
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateFriendGroup(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	group := &models.FriendGroup{UserId: 1, Name: "羽毛球", CreateTime: now, UpdateTime: now}
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT COALESCE(MAX(sort_order), 0) FROM `friend_group` WHERE user_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"max"}).AddRow(2))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `friend_group` (`user_id`,`name`,`sort_order`,`create_time`,`update_time`) VALUES (?,?,?,?,?)")).
+		WithArgs(int64(1), "羽毛球", 3, now, now).
+		WillReturnResult(sqlmock.NewResult(5, 1))
+	mock.ExpectCommit()
+
+	err := CreateFriendGroup(group)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), group.Id)
+	assert.Equal(t, 3, group.SortOrder)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFriendGroupsByUserId(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friend_group` WHERE user_id = ? ORDER BY sort_order ASC, id ASC")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "name", "sort_order", "create_time", "update_time"}).
+			AddRow(6, 1, "徒步", 1, now, now).
+			AddRow(5, 1, "羽毛球", 2, now, now))
+
+	groups, err := GetFriendGroupsByUserId(1)
+	assert.NoError(t, err)
+	assert.Len(t, groups, 2)
+	assert.Equal(t, "徒步", groups[0].Name)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestFriendGroupNameExists(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `friend_group` WHERE user_id = ? AND name = ? AND id <> ?")).
+		WithArgs(int64(1), "徒步", int64(5)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	exists, err := FriendGroupNameExists(1, "徒步", 5)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteFriendGroup(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_group_member` WHERE group_id = ?")).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_group` WHERE `friend_group`.`id` = ?")).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, DeleteFriendGroup(5))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestReorderFriendGroups(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friend_group` SET `sort_order`=?,`update_time`=? WHERE id = ? AND user_id = ?")).
+		WithArgs(1, now, int64(6), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friend_group` SET `sort_order`=?,`update_time`=? WHERE id = ? AND user_id = ?")).
+		WithArgs(2, now, int64(5), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, ReorderFriendGroups(1, []int64{6, 5}, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetFriendGroupMembers(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	group := &models.FriendGroup{Id: 5, UserId: 1, Name: "羽毛球", SortOrder: 1, CreateTime: now, UpdateTime: now}
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_group_member` WHERE group_id = ?")).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `friend_group_member` (`group_id`,`user_id`,`friend_id`,`create_time`) VALUES (?,?,?,?),(?,?,?,?)")).
+		WithArgs(int64(5), int64(1), int64(2), now, int64(5), int64(1), int64(3), now).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friend_group` SET `update_time`=? WHERE `id` = ?")).
+		WithArgs(now, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, SetFriendGroupMembers(group, []int64{2, 3}, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 清空分组
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_group_member` WHERE group_id = ?")).
+		WithArgs(int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `friend_group` SET `update_time`=? WHERE `id` = ?")).
+		WithArgs(now, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, SetFriendGroupMembers(group, nil, now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFriendGroupMemberIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `friend_id` FROM `friend_group_member` WHERE user_id = ? AND group_id IN (?,?) GROUP BY `friend_id` ORDER BY MIN(id)")).
+		WithArgs(int64(1), int64(5), int64(6)).
+		WillReturnRows(sqlmock.NewRows([]string{"friend_id"}).AddRow(2).AddRow(3))
+
+	friendIds, err := GetFriendGroupMemberIds(1, []int64{5, 6})
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, friendIds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSetFriendRemark(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `friend_remark` (`user_id`,`friend_id`,`remark`,`update_time`) VALUES (?,?,?,?) ON DUPLICATE KEY UPDATE `remark`=VALUES(`remark`),`update_time`=VALUES(`update_time`)")).
+		WithArgs(int64(1), int64(2), "球搭子", now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, SetFriendRemark(1, 2, "球搭子", now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 备注为空时删除
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_remark` WHERE user_id = ? AND friend_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, SetFriendRemark(1, 2, "", now))
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetFriendRemarks(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friend_remark` WHERE user_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "friend_id", "remark", "update_time"}).
+			AddRow(1, 1, 2, "球搭子", now).
+			AddRow(2, 1, 3, "老王", now))
+
+	remarks, err := GetFriendRemarks(1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]string{2: "球搭子", 3: "老王"}, remarks)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
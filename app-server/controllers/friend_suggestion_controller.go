@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// maxNearbyCandidates 按距离推荐时最多取出的候选用户数量
+const maxNearbyCandidates = 200
+
+// activityParticipationSQL 活动参与记录：活动成员及活动创建者
+const activityParticipationSQL = "SELECT activity_id, user_id FROM activity_member UNION SELECT id AS activity_id, user_id FROM activity"
+
+// userCount 按用户统计的数量
+type userCount struct {
+	UserId int64
+	Count  int
+}
+
+func userCountsToMap(rows []userCount) map[int64]int {
+	counts := make(map[int64]int, len(rows))
+	for _, row := range rows {
+		counts[row.UserId] = row.Count
+	}
+	return counts
+}
+
+// GetMutualFriendCounts 统计好友的好友（不含自己）与用户的共同好友数量
+func GetMutualFriendCounts(userId int64) (map[int64]int, error) {
+	var rows []userCount
+	if err := config.DB.Table("("+friendEdgeSQL+") AS mine").
+		Select("theirs.friend_id AS user_id, COUNT(*) AS count").
+		Joins("JOIN ("+friendEdgeSQL+") AS theirs ON theirs.user_id = mine.friend_id AND theirs.status = ?", models.FriendshipAccepted).
+		Where("mine.user_id = ? AND mine.status = ? AND theirs.friend_id <> ?", userId, models.FriendshipAccepted, userId).
+		Group("theirs.friend_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return userCountsToMap(rows), nil
+}
+
+// GetSharedActivityCounts 统计与用户共同参加过未删除公开活动的其他用户及共同活动数量，
+// 好友可见及仅受邀可见的活动不计入，避免通过推荐理由暴露他人参加的非公开活动
+func GetSharedActivityCounts(userId int64) (map[int64]int, error) {
+	var rows []userCount
+	if err := config.DB.Raw("SELECT theirs.user_id AS user_id, COUNT(DISTINCT theirs.activity_id) AS count "+
+		"FROM ("+activityParticipationSQL+") AS mine "+
+		"JOIN ("+activityParticipationSQL+") AS theirs ON theirs.activity_id = mine.activity_id AND theirs.user_id <> mine.user_id "+
+		"JOIN activity ON activity.id = mine.activity_id AND activity.if_delete = ? AND activity.visibility = ? "+
+		"WHERE mine.user_id = ? GROUP BY theirs.user_id", 0, models.ActivityVisibilityPublic, userId).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return userCountsToMap(rows), nil
+}
+
+// GetNearbyUsers 获取距离范围内的用户，排除指定用户、不出现在搜索结果中及隐藏距离的用户，
+// 在数据库中按距离从近到远排列后取最近的一批
+func GetNearbyUsers(filter models.UserSearchFilter) ([]models.User, error) {
+	query := config.DB.Where("id NOT IN (SELECT user_id FROM user_setting WHERE hide_from_search = ?)", true)
+	if len(filter.ExcludeIds) > 0 {
+		query = query.Where("id NOT IN ?", filter.ExcludeIds)
+	}
+	var users []models.User
+	if err := whereWithinDistance(query, filter).
+		Order(orderByDistance(filter)).
+		Limit(maxNearbyCandidates).
+		Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMutualFriendCounts(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT theirs.friend_id AS user_id, COUNT(*) AS count FROM ("+friendEdgeSQL+") AS mine "+
+		"JOIN ("+friendEdgeSQL+") AS theirs ON theirs.user_id = mine.friend_id AND theirs.status = ? "+
+		"WHERE mine.user_id = ? AND mine.status = ? AND theirs.friend_id <> ? GROUP BY `theirs`.`friend_id`")).
+		WithArgs(1, int64(1), 1, int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "count"}).AddRow(4, 2).AddRow(5, 1))
+
+	counts, err := GetMutualFriendCounts(1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int{4: 2, 5: 1}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSharedActivityCounts(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT theirs.user_id AS user_id, COUNT(DISTINCT theirs.activity_id) AS count "+
+		"FROM ("+activityParticipationSQL+") AS mine "+
+		"JOIN ("+activityParticipationSQL+") AS theirs ON theirs.activity_id = mine.activity_id AND theirs.user_id <> mine.user_id "+
+		"JOIN activity ON activity.id = mine.activity_id AND activity.if_delete = ? AND activity.visibility = ? "+
+		"WHERE mine.user_id = ? GROUP BY theirs.user_id")).
+		WithArgs(0, models.ActivityVisibilityPublic, int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "count"}).AddRow(3, 2))
+
+	counts, err := GetSharedActivityCounts(1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64]int{3: 2}, counts)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNearbyUsers(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 在数据库中筛选半径内的用户并按距离从近到远取最近的一批，排除不出现在搜索结果中及隐藏距离的用户
+	filter := models.UserSearchFilter{
+		ExcludeIds:     []int64{1, 3},
+		WithinDistance: true, Lat: 40, Lon: 116, RadiusDeg: 0.2, LonScale: 0.8,
+		MinLat: 39.8, MaxLat: 40.2, MinLon: 115.75, MaxLon: 116.25,
+	}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user` WHERE id NOT IN (SELECT user_id FROM user_setting WHERE hide_from_search = ?) "+
+		"AND id NOT IN (?,?) "+
+		"AND (lat BETWEEN ? AND ? AND lon BETWEEN ? AND ? AND (lat <> 0 OR lon <> 0)) "+
+		"AND (lat - ?) * (lat - ?) + (lon - ?) * (lon - ?) * ? <= ? "+
+		"AND id NOT IN (SELECT user_id FROM user_setting WHERE hide_distance = ?) "+
+		"ORDER BY (lat - ?) * (lat - ?) + (lon - ?) * (lon - ?) * ?, id ASC LIMIT ?")).
+		WithArgs(true, int64(1), int64(3), 39.8, 40.2, 115.75, 116.25, 40.0, 40.0, 116.0, 116.0, sqlmock.AnyArg(), sqlmock.AnyArg(), true,
+			40.0, 40.0, 116.0, 116.0, sqlmock.AnyArg(), maxNearbyCandidates).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "lat", "lon"}).AddRow(2, 40.1, 116.1))
+
+	users, err := GetNearbyUsers(filter)
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, 40.1, users[0].Lat)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
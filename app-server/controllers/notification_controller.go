@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// AddNotifications 批量添加通知
+func AddNotifications(notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return config.DB.Create(&notifications).Error
+}
+
+// GetNotificationsByUserId 分页获取用户的通知，按时间倒序，unreadOnly 为 true 时只返回未读通知
+func GetNotificationsByUserId(userId int64, unreadOnly bool, page, pageSize int) ([]models.Notification, int64, error) {
+	var notifications []models.Notification
+	var total int64
+	query := config.DB.Model(&models.Notification{}).Where("user_id = ?", userId)
+	if unreadOnly {
+		query = query.Where("if_read = ?", 0)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("create_time DESC, id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+	return notifications, total, nil
+}
+
+// CountUnreadNotifications 获取用户未读通知数量
+func CountUnreadNotifications(userId int64) (int64, error) {
+	var count int64
+	err := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND if_read = ?", userId, 0).
+		Count(&count).Error
+	return count, err
+}
+
+// MarkNotificationRead 将用户自己的一条通知标记为已读，通知不存在时返回 gorm.ErrRecordNotFound
+func MarkNotificationRead(userId, notificationId int64) (*models.Notification, error) {
+	var notification models.Notification
+	if err := config.DB.Where("id = ? AND user_id = ?", notificationId, userId).First(&notification).Error; err != nil {
+		return nil, err
+	}
+	if notification.IfRead == 1 {
+		return &notification, nil
+	}
+	if err := config.DB.Model(&notification).Update("if_read", 1).Error; err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// MarkAllNotificationsRead 将用户所有未读通知标记为已读，返回标记的数量
+func MarkAllNotificationsRead(userId int64) (int64, error) {
+	result := config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND if_read = ?", userId, 0).
+		Update("if_read", 1)
+	return result.RowsAffected, result.Error
+}
+
+// MarkNotificationsReadByRelatedId 将用户某类型、关联同一对象的通知标记为已读
+func MarkNotificationsReadByRelatedId(userId int64, notificationType string, relatedId int64) error {
+	return config.DB.Model(&models.Notification{}).
+		Where("user_id = ? AND type = ? AND related_id = ?", userId, notificationType, relatedId).
+		Update("if_read", 1).Error
+}
@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+)
+
+func TestAddNotifications(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 空列表不写入数据库
+	assert.NoError(t, AddNotifications(nil))
+
+	now := time.Now()
+	notifications := []models.Notification{
+		{UserId: 1, Type: models.NotificationActivityTimeFinalized, Content: "a", ActivityId: 3, CreateTime: now},
+		{UserId: 2, Type: models.NotificationActivityTimeFinalized, Content: "a", ActivityId: 3, CreateTime: now},
+	}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `notification`")).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	err := AddNotifications(notifications)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetNotificationsByUserId(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `notification` WHERE user_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notification` WHERE user_id = ? ORDER BY create_time DESC, id DESC LIMIT ?")).
+		WithArgs(int64(1), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "content"}).
+			AddRow(2, 1, models.NotificationActivityTimeFinalized, "b").
+			AddRow(1, 1, models.NotificationActivityTimeFinalized, "a"))
+
+	notifications, total, err := GetNotificationsByUserId(1, false, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, notifications, 2)
+
+	// 只获取未读通知
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `notification` WHERE user_id = ? AND if_read = ?")).
+		WithArgs(int64(1), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notification` WHERE user_id = ? AND if_read = ? ORDER BY create_time DESC, id DESC LIMIT ? OFFSET ?")).
+		WithArgs(int64(1), 0, 10, 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "type", "content"}))
+
+	notifications, total, err = GetNotificationsByUserId(1, true, 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Empty(t, notifications)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestCountUnreadNotifications(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `notification` WHERE user_id = ? AND if_read = ?")).
+		WithArgs(int64(1), 0).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+
+	count, err := CountUnreadNotifications(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkNotificationRead(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notification` WHERE id = ? AND user_id = ? ORDER BY `notification`.`id` LIMIT ?")).
+		WithArgs(int64(5), int64(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "if_read"}).AddRow(5, 1, 0))
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `notification` SET `if_read`=? WHERE `id` = ?")).
+		WithArgs(1, int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	notification, err := MarkNotificationRead(1, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, notification.IfRead)
+
+	// 已读的通知不再更新
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notification` WHERE id = ? AND user_id = ? ORDER BY `notification`.`id` LIMIT ?")).
+		WithArgs(int64(6), int64(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "if_read"}).AddRow(6, 1, 1))
+
+	_, err = MarkNotificationRead(1, 6)
+	assert.NoError(t, err)
+
+	// 他人的通知视为不存在
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `notification` WHERE id = ? AND user_id = ? ORDER BY `notification`.`id` LIMIT ?")).
+		WithArgs(int64(7), int64(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "if_read"}))
+
+	_, err = MarkNotificationRead(1, 7)
+	assert.ErrorIs(t, err, gorm.ErrRecordNotFound)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkAllNotificationsRead(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `notification` SET `if_read`=? WHERE user_id = ? AND if_read = ?")).
+		WithArgs(1, int64(1), 0).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+	mock.ExpectCommit()
+
+	updated, err := MarkAllNotificationsRead(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), updated)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestMarkNotificationsReadByRelatedId(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `notification` SET `if_read`=? WHERE user_id = ? AND type = ? AND related_id = ?")).
+		WithArgs(1, int64(2), models.NotificationActivityAnnouncement, int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := MarkNotificationsReadByRelatedId(2, models.NotificationActivityAnnouncement, 3)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// UpdateUserLastSeen 写入用户最近在线时间，不存在时新建
+func UpdateUserLastSeen(userId int64, now time.Time) error {
+	presence := models.UserPresence{UserId: userId, LastSeenTime: now}
+	return config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_seen_time"}),
+	}).Create(&presence).Error
+}
+
+// GetUserPresences 批量获取用户最近在线时间，从未上线的用户没有记录
+func GetUserPresences(userIds []int64) ([]models.UserPresence, error) {
+	var presences []models.UserPresence
+	if len(userIds) == 0 {
+		return presences, nil
+	}
+	if err := config.DB.Where("user_id IN ?", userIds).Find(&presences).Error; err != nil {
+		return nil, err
+	}
+	return presences, nil
+}
+
+// GetUserSetting 获取用户隐私及推送设置，未设置时返回默认设置
+func GetUserSetting(userId int64) (*models.UserSetting, error) {
+	var setting models.UserSetting
+	err := config.DB.Where("user_id = ?", userId).First(&setting).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.UserSetting{UserId: userId}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &setting, nil
+}
+
+// GetUserSettings 批量获取用户隐私及推送设置，未设置的用户没有记录
+func GetUserSettings(userIds []int64) ([]models.UserSetting, error) {
+	var settings []models.UserSetting
+	if len(userIds) == 0 {
+		return settings, nil
+	}
+	if err := config.DB.Where("user_id IN ?", userIds).Find(&settings).Error; err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// SaveUserSetting 保存用户隐私及推送设置，不存在时新建
+func SaveUserSetting(setting *models.UserSetting) error {
+	return config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		UpdateAll: true,
+	}).Create(setting).Error
+}
@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpdateUserLastSeen(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `user_presence` (`user_id`,`last_seen_time`) VALUES (?,?) ON DUPLICATE KEY UPDATE `last_seen_time`=VALUES(`last_seen_time`)")).
+		WithArgs(int64(1), now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := UpdateUserLastSeen(1, now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserPresences(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 没有用户时不查询数据库
+	presences, err := GetUserPresences(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, presences)
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user_presence` WHERE user_id IN (?,?)")).
+		WithArgs(int64(2), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "last_seen_time"}).AddRow(2, now))
+
+	presences, err = GetUserPresences([]int64{2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, presences, 1)
+	assert.Equal(t, int64(2), presences[0].UserId)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserSetting(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 未设置时返回默认设置
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user_setting` WHERE user_id = ? ORDER BY `user_setting`.`user_id` LIMIT ?")).
+		WithArgs(int64(1), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "hide_presence"}))
+
+	setting, err := GetUserSetting(1)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), setting.UserId)
+	assert.False(t, setting.HidePresence)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user_setting` WHERE user_id = ? ORDER BY `user_setting`.`user_id` LIMIT ?")).
+		WithArgs(int64(2), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "hide_presence"}).AddRow(2, true))
+
+	setting, err = GetUserSetting(2)
+	assert.NoError(t, err)
+	assert.True(t, setting.HidePresence)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestSaveUserSetting(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	setting := &models.UserSetting{UserId: 1, HideLastSeen: true, UpdateTime: now}
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `user_setting` (`user_id`,`hide_presence`,`hide_last_seen`,`hide_typing`,`hide_from_search`,`hide_distance`,`mute_chat_push`,`mute_friend_push`,`mute_invitation_push`,`mute_activity_push`,`update_time`) VALUES (?,?,?,?,?,?,?,?,?,?,?) ON DUPLICATE KEY UPDATE")).
+		WithArgs(int64(1), false, true, false, false, false, false, false, false, false, now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	err := SaveUserSetting(setting)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
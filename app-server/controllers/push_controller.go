@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// RegisterDeviceToken 登记设备推送令牌。令牌已存在时转移给当前用户，避免换号登录后推送给上一个用户
+func RegisterDeviceToken(userId int64, platform, token string, now time.Time) (*models.DeviceToken, error) {
+	device := models.DeviceToken{UserId: userId, Platform: platform, Token: token, CreateTime: now, UpdateTime: now}
+	if err := config.DB.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.AssignmentColumns([]string{"user_id", "platform", "update_time"}),
+	}).Create(&device).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+// DeleteDeviceToken 删除用户自己的设备推送令牌，返回是否删除了记录
+func DeleteDeviceToken(userId int64, token string) (bool, error) {
+	result := config.DB.Where("user_id = ? AND token = ?", userId, token).Delete(&models.DeviceToken{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteDeviceTokenById 删除推送服务报告失效的令牌
+func DeleteDeviceTokenById(id int64) error {
+	return config.DB.Delete(&models.DeviceToken{}, id).Error
+}
+
+// GetDeviceTokens 获取用户登记的所有设备，最近登记的在前
+func GetDeviceTokens(userId int64) ([]models.DeviceToken, error) {
+	var devices []models.DeviceToken
+	if err := config.DB.Where("user_id = ?", userId).Order("update_time DESC").Find(&devices).Error; err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
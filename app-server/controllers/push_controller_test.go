@@ -0,0 +1,72 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterDeviceToken(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `device_token` (`user_id`,`platform`,`token`,`create_time`,`update_time`) VALUES (?,?,?,?,?) ON DUPLICATE KEY UPDATE `user_id`=VALUES(`user_id`),`platform`=VALUES(`platform`),`update_time`=VALUES(`update_time`)")).
+		WithArgs(int64(1), "android", "token-1", now, now).
+		WillReturnResult(sqlmock.NewResult(3, 1))
+	mock.ExpectCommit()
+
+	device, err := RegisterDeviceToken(1, "android", "token-1", now)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(3), device.Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestDeleteDeviceToken(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `device_token` WHERE user_id = ? AND token = ?")).
+		WithArgs(int64(1), "token-1").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	deleted, err := DeleteDeviceToken(1, "token-1")
+	assert.NoError(t, err)
+	assert.True(t, deleted)
+
+	// 不属于自己的令牌不会被删除
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `device_token` WHERE user_id = ? AND token = ?")).
+		WithArgs(int64(2), "token-1").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	deleted, err = DeleteDeviceToken(2, "token-1")
+	assert.NoError(t, err)
+	assert.False(t, deleted)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetDeviceTokens(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `device_token` WHERE user_id = ? ORDER BY update_time DESC")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "platform", "token", "create_time", "update_time"}).
+			AddRow(2, 1, "ios", "token-2", now, now).
+			AddRow(1, 1, "android", "token-1", now, now))
+
+	devices, err := GetDeviceTokens(1)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 2)
+	assert.Equal(t, "token-2", devices[0].Token)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
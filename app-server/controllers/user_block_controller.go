@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"time"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+)
+
+// BlockUser 在事务中屏蔽用户：解除双方的好友关系，并拒绝双方之间待处理的活动邀请
+func BlockUser(userId, blockedUserId int64, now time.Time) error {
+	tx := config.DB.Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	var count int64
+	if err := tx.Model(&models.UserBlock{}).
+		Where("user_id = ? AND blocked_user_id = ?", userId, blockedUserId).
+		Count(&count).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if count > 0 {
+		tx.Rollback()
+		return custom_errors.ErrUserAlreadyBlocked
+	}
+
+	block := models.UserBlock{UserId: userId, BlockedUserId: blockedUserId, CreateTime: now}
+	if err := tx.Create(&block).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if _, err := applyFriendAction(tx, userId, blockedUserId, models.FriendActionBlock, "", now, time.Time{}); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Model(&models.ActivityInvitation{}).
+		Where("status = ?", models.InvitationPending).
+		Where("(inviter_id = ? AND invitee_id = ?) OR (inviter_id = ? AND invitee_id = ?)", userId, blockedUserId, blockedUserId, userId).
+		Updates(map[string]interface{}{"status": models.InvitationDeclined, "update_time": now}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	return tx.Commit().Error
+}
+
+// UnblockUser 取消屏蔽，好友关系不会自动恢复
+func UnblockUser(userId, blockedUserId int64) error {
+	result := config.DB.Where("user_id = ? AND blocked_user_id = ?", userId, blockedUserId).Delete(&models.UserBlock{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return custom_errors.ErrUserNotBlocked
+	}
+	return nil
+}
+
+// GetBlockedUsers 分页获取用户屏蔽的人，按屏蔽时间倒序
+func GetBlockedUsers(userId int64, page, pageSize int) ([]models.BlockedUser, int64, error) {
+	blockedUsers := make([]models.BlockedUser, 0)
+	var total int64
+	query := config.DB.Table("user_block AS b").
+		Joins("JOIN `user` u ON u.id = b.blocked_user_id").
+		Where("b.user_id = ?", userId)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Select("b.blocked_user_id AS user_id, u.username, u.name, u.head_img, b.create_time").
+		Order("b.create_time DESC, b.id DESC").
+		Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Scan(&blockedUsers).Error; err != nil {
+		return nil, 0, err
+	}
+	return blockedUsers, total, nil
+}
+
+// IsBlockedBetween 判断两个用户之间是否有任意一方屏蔽了另一方
+func IsBlockedBetween(userId, otherUserId int64) (bool, error) {
+	var count int64
+	if err := config.DB.Model(&models.UserBlock{}).
+		Where("(user_id = ? AND blocked_user_id = ?) OR (user_id = ? AND blocked_user_id = ?)", userId, otherUserId, otherUserId, userId).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// GetBlockRelatedUserIds 获取用户屏蔽的人及屏蔽了该用户的人
+func GetBlockRelatedUserIds(userId int64) ([]int64, error) {
+	var blockedIds, blockerIds []int64
+	if err := config.DB.Model(&models.UserBlock{}).
+		Where("user_id = ?", userId).
+		Pluck("blocked_user_id", &blockedIds).Error; err != nil {
+		return nil, err
+	}
+	if err := config.DB.Model(&models.UserBlock{}).
+		Where("blocked_user_id = ?", userId).
+		Pluck("user_id", &blockerIds).Error; err != nil {
+		return nil, err
+	}
+	return append(blockedIds, blockerIds...), nil
+}
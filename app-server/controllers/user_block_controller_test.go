@@ -0,0 +1,148 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/custom_errors"
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockUser(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `user_block` WHERE user_id = ? AND blocked_user_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `user_block` (`user_id`,`blocked_user_id`,`create_time`) VALUES (?,?,?)")).
+		WithArgs(int64(1), int64(2), now).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `friendship` WHERE user_low_id = ? AND user_high_id = ? LIMIT ?")).
+		WithArgs(int64(1), int64(2), 1).
+		WillReturnRows(sqlmock.NewRows(friendshipColumns).
+			AddRow(4, 1, 2, 2, models.FriendshipAccepted, "", now, now, now))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friendship` WHERE `friendship`.`id` = ?")).
+		WithArgs(int64(4)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_group_member` WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)")).
+		WithArgs(int64(1), int64(2), int64(2), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_remark` WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)")).
+		WithArgs(int64(1), int64(2), int64(2), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `activity_invitation` SET `status`=?,`update_time`=? WHERE status = ? AND ((inviter_id = ? AND invitee_id = ?) OR (inviter_id = ? AND invitee_id = ?))")).
+		WithArgs(models.InvitationDeclined, now, models.InvitationPending, int64(1), int64(2), int64(2), int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	err := BlockUser(1, 2, now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 重复屏蔽
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `user_block` WHERE user_id = ? AND blocked_user_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectRollback()
+
+	err = BlockUser(1, 2, now)
+	assert.ErrorIs(t, err, custom_errors.ErrUserAlreadyBlocked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestUnblockUser(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_block` WHERE user_id = ? AND blocked_user_id = ?")).
+		WithArgs(int64(1), int64(2)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectCommit()
+
+	assert.NoError(t, UnblockUser(1, 2))
+
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_block` WHERE user_id = ? AND blocked_user_id = ?")).
+		WithArgs(int64(1), int64(3)).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectCommit()
+
+	assert.ErrorIs(t, UnblockUser(1, 3), custom_errors.ErrUserNotBlocked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlockedUsers(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM user_block AS b JOIN `user` u ON u.id = b.blocked_user_id WHERE b.user_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT b.blocked_user_id AS user_id, u.username, u.name, u.head_img, b.create_time FROM user_block AS b JOIN `user` u ON u.id = b.blocked_user_id WHERE b.user_id = ? ORDER BY b.create_time DESC, b.id DESC LIMIT ?")).
+		WithArgs(int64(1), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "username", "name", "head_img", "create_time"}).
+			AddRow(2, "bob", "Bob", "", now))
+
+	blockedUsers, total, err := GetBlockedUsers(1, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), total)
+	assert.Equal(t, int64(2), blockedUsers[0].UserId)
+	assert.Equal(t, "bob", blockedUsers[0].Username)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 没有屏蔽任何人时返回空列表而不是nil
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM user_block AS b JOIN `user` u ON u.id = b.blocked_user_id WHERE b.user_id = ?")).
+		WithArgs(int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT b.blocked_user_id AS user_id, u.username, u.name, u.head_img, b.create_time FROM user_block AS b JOIN `user` u ON u.id = b.blocked_user_id WHERE b.user_id = ? ORDER BY b.create_time DESC, b.id DESC LIMIT ?")).
+		WithArgs(int64(3), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "username", "name", "head_img", "create_time"}))
+
+	blockedUsers, total, err = GetBlockedUsers(3, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(0), total)
+	assert.NotNil(t, blockedUsers)
+	assert.Empty(t, blockedUsers)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestIsBlockedBetween(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `user_block` WHERE (user_id = ? AND blocked_user_id = ?) OR (user_id = ? AND blocked_user_id = ?)")).
+		WithArgs(int64(1), int64(2), int64(2), int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+
+	blocked, err := IsBlockedBetween(1, 2)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetBlockRelatedUserIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `blocked_user_id` FROM `user_block` WHERE user_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"blocked_user_id"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `user_id` FROM `user_block` WHERE blocked_user_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(3))
+
+	userIds, err := GetBlockRelatedUserIds(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{2, 3}, userIds)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
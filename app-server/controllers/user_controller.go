@@ -27,6 +27,18 @@ func GetUserByUserId(userId int64) (*models.User, error) {
 	return &user, nil
 }
 
+// GetUsersByIds 批量获取用户详情，不存在的用户不返回
+func GetUsersByIds(userIds []int64) ([]models.User, error) {
+	var users []models.User
+	if len(userIds) == 0 {
+		return users, nil
+	}
+	if err := config.DB.Where("id IN ?", userIds).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 // GetUserByUserName 通过用户名获取用户详情
 func GetUserByUserName(userName string) (*models.User, error) {
 	var user models.User
@@ -56,8 +68,38 @@ func DeleteUserByUserId(userId int64) error {
 	}()
 
 	// 删除用户的好友关系
+	if err := tx.Where("user_low_id = ? OR user_high_id = ?", userId, userId).
+		Delete(&models.Friendship{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除用户的好友分组及备注，以及别人分组、备注中的该用户
+	if err := tx.Where("user_id = ?", userId).Delete(&models.FriendGroup{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("user_id = ? OR friend_id = ?", userId, userId).
+		Delete(&models.FriendGroupMember{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
 	if err := tx.Where("user_id = ? OR friend_id = ?", userId, userId).
-		Delete(&models.Friend{}).Error; err != nil {
+		Delete(&models.FriendRemark{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除双方向的屏蔽关系
+	if err := tx.Where("user_id = ? OR blocked_user_id = ?", userId, userId).
+		Delete(&models.UserBlock{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除用户聊天记录的编辑历史
+	if err := tx.Where("chat_id IN (SELECT id FROM chat WHERE user_id_from = ? OR user_id_to = ?)", userId, userId).
+		Delete(&models.ChatEdit{}).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -69,6 +111,20 @@ func DeleteUserByUserId(userId int64) error {
 		return err
 	}
 
+	// 退出用户加入的群聊，群主离开的群聊由最早加入的成员接任
+	var rooms []models.ChatRoom
+	if err := tx.Where("id IN (SELECT room_id FROM chat_room_member WHERE user_id = ?)", userId).
+		Find(&rooms).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	for i := range rooms {
+		if err := removeChatRoomMember(tx, &rooms[i], userId); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
 	// 删除用户的活动评论
 	if err := tx.Where("user_id = ?", userId).
 		Delete(&models.ActivityComment{}).Error; err != nil {
@@ -83,6 +139,27 @@ func DeleteUserByUserId(userId int64) error {
 		return err
 	}
 
+	// 删除用户发出及收到的活动邀请
+	if err := tx.Where("inviter_id = ? OR invitee_id = ?", userId, userId).
+		Delete(&models.ActivityInvitation{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除用户的入团申请
+	if err := tx.Where("user_id = ?", userId).
+		Delete(&models.ActivityJoinRequest{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除用户的时间投票记录
+	if err := tx.Where("user_id = ?", userId).
+		Delete(&models.ActivityPollVote{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 删除用户创建的活动（或者考虑转移所有权）
 	if err := tx.Where("user_id = ?", userId).
 		Delete(&models.Activity{}).Error; err != nil {
@@ -90,6 +167,41 @@ func DeleteUserByUserId(userId int64) error {
 		return err
 	}
 
+	// 删除用户的隐私设置
+	if err := tx.Where("user_id = ?", userId).
+		Delete(&models.UserSetting{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除用户的最近在线记录
+	if err := tx.Where("user_id = ?", userId).
+		Delete(&models.UserPresence{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除用户的推送设备令牌
+	if err := tx.Where("user_id = ?", userId).
+		Delete(&models.DeviceToken{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除用户收到的及由用户触发的通知
+	if err := tx.Where("user_id = ? OR actor_id = ?", userId, userId).
+		Delete(&models.Notification{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// 删除用户的兴趣标签
+	if err := tx.Where("user_id = ?", userId).
+		Delete(&models.UserInterest{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// 最后删除用户本身
 	if err := tx.Delete(&models.User{}, userId).Error; err != nil {
 		tx.Rollback()
@@ -105,8 +217,8 @@ func CountUserRelations(userId int64) (map[string]int64, error) {
 
 	// 统计好友数
 	var friendCount int64
-	if err := config.DB.Model(&models.Friend{}).
-		Where("user_id = ? AND status = ?", userId, 1).
+	if err := config.DB.Model(&models.Friendship{}).
+		Where("(user_low_id = ? OR user_high_id = ?) AND status = ?", userId, userId, models.FriendshipAccepted).
 		Count(&friendCount).Error; err != nil {
 		return nil, err
 	}
@@ -133,7 +245,7 @@ func CountUserRelations(userId int64) (map[string]int64, error) {
 	// 统计未读消息数
 	var unreadMessageCount int64
 	if err := config.DB.Model(&models.Chat{}).
-		Where("user_id_to = ? AND status_to = ?", userId, 2).
+		Where("user_id_to = ? AND status_to <> 0 AND read_time IS NULL", userId).
 		Count(&unreadMessageCount).Error; err != nil {
 		return nil, err
 	}
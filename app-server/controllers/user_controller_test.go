@@ -89,6 +89,26 @@ func TestGetUserByUserId(t *testing.T) {
 	assert.NoError(t, mock2.ExpectationsWereMet())
 }
 
+func TestGetUsersByIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	// 没有用户时不查询数据库
+	users, err := GetUsersByIds(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, users)
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user` WHERE id IN (?,?)")).
+		WithArgs(int64(2), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "username"}).AddRow(2, "u2"))
+
+	users, err = GetUsersByIds([]int64{2, 3})
+	assert.NoError(t, err)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "u2", users[0].Username)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
 func TestGetUserByUserName(t *testing.T) {
 	mock, teardown := SetupMockDB(t)
 	defer teardown()
@@ -162,15 +182,56 @@ func TestDeleteUserByUserId(t *testing.T) {
 	mock.ExpectBegin()
 
 	// 删除用户的好友关系
-	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend` WHERE user_id = ? OR friend_id = ?")).
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friendship` WHERE user_low_id = ? OR user_high_id = ?")).
 		WithArgs(userId, userId).
 		WillReturnResult(sqlmock.NewResult(1, 2)) // 假设删除了两条好友记录
 
+	// 删除用户的好友分组及备注
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_group` WHERE user_id = ?")).
+		WithArgs(userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_group_member` WHERE user_id = ? OR friend_id = ?")).
+		WithArgs(userId, userId).
+		WillReturnResult(sqlmock.NewResult(1, 3))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend_remark` WHERE user_id = ? OR friend_id = ?")).
+		WithArgs(userId, userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// 删除双方向的屏蔽关系
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_block` WHERE user_id = ? OR blocked_user_id = ?")).
+		WithArgs(userId, userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// 删除用户聊天记录的编辑历史
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `chat_edit` WHERE chat_id IN (SELECT id FROM chat WHERE user_id_from = ? OR user_id_to = ?)")).
+		WithArgs(userId, userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	// 删除用户的聊天记录
 	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `chat` WHERE user_id_from = ? OR user_id_to = ?")).
 		WithArgs(userId, userId).
 		WillReturnResult(sqlmock.NewResult(1, 3)) // 假设删除了三条聊天记录
 
+	// 退出用户加入的群聊，群主离开时由最早加入的成员接任
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_room` WHERE id IN (SELECT room_id FROM chat_room_member WHERE user_id = ?)")).
+		WithArgs(userId).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "owner_id"}).AddRow(5, userId).AddRow(6, 2))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `chat_room_member` WHERE room_id = ? AND user_id = ?")).
+		WithArgs(int64(5), userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `chat_room_member` WHERE room_id = ? ORDER BY join_time ASC, id ASC")).
+		WithArgs(int64(5), 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "room_id", "user_id"}).AddRow(9, 5, 3))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat_room_member` SET `role`=? WHERE `id` = ?")).
+		WithArgs(models.ChatRoomRoleOwner, int64(9)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("UPDATE `chat_room` SET `owner_id`=? WHERE `id` = ?")).
+		WithArgs(int64(3), int64(5)).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `chat_room_member` WHERE room_id = ? AND user_id = ?")).
+		WithArgs(int64(6), userId).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
 	// 删除用户的活动评论
 	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_comment` WHERE user_id = ?")).
 		WithArgs(userId).
@@ -181,11 +242,51 @@ func TestDeleteUserByUserId(t *testing.T) {
 		WithArgs(userId).
 		WillReturnResult(sqlmock.NewResult(1, 2))
 
+	// 删除用户发出及收到的活动邀请
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_invitation` WHERE inviter_id = ? OR invitee_id = ?")).
+		WithArgs(userId, userId).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+
+	// 删除用户的入团申请
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_join_request` WHERE user_id = ?")).
+		WithArgs(userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// 删除用户的时间投票记录
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity_poll_vote` WHERE user_id = ?")).
+		WithArgs(userId).
+		WillReturnResult(sqlmock.NewResult(1, 3))
+
 	// 删除用户创建的活动
 	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `activity` WHERE user_id = ?")).
 		WithArgs(userId).
 		WillReturnResult(sqlmock.NewResult(1, 1))
 
+	// 删除用户的隐私设置
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_setting` WHERE user_id = ?")).
+		WithArgs(userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// 删除用户的最近在线记录
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_presence` WHERE user_id = ?")).
+		WithArgs(userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// 删除用户的推送设备令牌
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `device_token` WHERE user_id = ?")).
+		WithArgs(userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// 删除用户收到的及由用户触发的通知
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `notification` WHERE user_id = ? OR actor_id = ?")).
+		WithArgs(userId, userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// 删除用户的兴趣标签
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_interest` WHERE user_id = ?")).
+		WithArgs(userId).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
 	// 删除用户本身
 	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user` WHERE `user`.`id` = ?")).
 		WithArgs(userId).
@@ -203,7 +304,7 @@ func TestDeleteUserByUserId(t *testing.T) {
 	defer teardown2()
 
 	mock2.ExpectBegin()
-	mock2.ExpectExec(regexp.QuoteMeta("DELETE FROM `friend` WHERE user_id = ? OR friend_id = ?")).
+	mock2.ExpectExec(regexp.QuoteMeta("DELETE FROM `friendship` WHERE user_low_id = ? OR user_high_id = ?")).
 		WithArgs(userId, userId).
 		WillReturnError(errors.New("delete friend error"))
 	mock2.ExpectRollback()
@@ -221,8 +322,8 @@ func TestCountUserRelations(t *testing.T) {
 
 	// 模拟统计好友数查询
 	friendCountRows := sqlmock.NewRows([]string{"count"}).AddRow(5)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `friend` WHERE user_id = ? AND status = ?")).
-		WithArgs(userId, 1).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `friendship` WHERE (user_low_id = ? OR user_high_id = ?) AND status = ?")).
+		WithArgs(userId, userId, 1).
 		WillReturnRows(friendCountRows)
 
 	// 模拟统计创建的活动数查询 - 修改这里匹配硬编码的if_delete = 0
@@ -239,8 +340,8 @@ func TestCountUserRelations(t *testing.T) {
 
 	// 模拟统计未读消息数查询
 	unreadMessageCountRows := sqlmock.NewRows([]string{"count"}).AddRow(12)
-	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `chat` WHERE user_id_to = ? AND status_to = ?")).
-		WithArgs(userId, 2).
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `chat` WHERE user_id_to = ? AND status_to <> 0 AND read_time IS NULL")).
+		WithArgs(userId).
 		WillReturnRows(unreadMessageCountRows)
 
 	relations, err := CountUserRelations(userId)
@@ -255,8 +356,8 @@ func TestCountUserRelations(t *testing.T) {
 	mock2, teardown2 := SetupMockDB(t)
 	defer teardown2()
 
-	mock2.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `friend` WHERE user_id = ? AND status = ?")).
-		WithArgs(userId, 1).
+	mock2.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `friendship` WHERE (user_low_id = ? OR user_high_id = ?) AND status = ?")).
+		WithArgs(userId, userId, 1).
 		WillReturnError(errors.New("count friend error"))
 
 	relations, err = CountUserRelations(userId)
@@ -0,0 +1,87 @@
+package controllers
+
+import (
+	"time"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// SetUserInterests 用新的兴趣标签替换用户原有的全部标签
+func SetUserInterests(userId int64, tags []string, now time.Time) error {
+	tx := config.DB.Begin()
+
+	defer func() {
+		if r := recover(); r != nil {
+			tx.Rollback()
+		}
+	}()
+
+	if err := tx.Where("user_id = ?", userId).Delete(&models.UserInterest{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if len(tags) > 0 {
+		interests := make([]models.UserInterest, 0, len(tags))
+		for _, tag := range tags {
+			interests = append(interests, models.UserInterest{UserId: userId, Tag: tag, CreateTime: now})
+		}
+		if err := tx.Create(&interests).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	return tx.Commit().Error
+}
+
+// GetUserInterests 获取用户的兴趣标签，按添加顺序排列
+func GetUserInterests(userId int64) ([]string, error) {
+	tags := []string{}
+	if err := config.DB.Model(&models.UserInterest{}).
+		Where("user_id = ?", userId).
+		Order("id").
+		Pluck("tag", &tags).Error; err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetUserInterestsByUserIds 批量获取用户的兴趣标签，键为用户Id，标签按添加顺序排列
+func GetUserInterestsByUserIds(userIds []int64) (map[int64][]string, error) {
+	interests := make(map[int64][]string)
+	if len(userIds) == 0 {
+		return interests, nil
+	}
+	var rows []models.UserInterest
+	if err := config.DB.Where("user_id IN ?", userIds).
+		Order("id").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		interests[row.UserId] = append(interests[row.UserId], row.Tag)
+	}
+	return interests, nil
+}
+
+// GetSharedInterests 获取与用户有相同兴趣标签的其他用户及相同的标签
+func GetSharedInterests(userId int64) (map[int64][]string, error) {
+	var rows []struct {
+		UserId int64
+		Tag    string
+	}
+	if err := config.DB.Table("user_interest AS mine").
+		Select("other.user_id, other.tag").
+		Joins("JOIN user_interest AS other ON other.tag = mine.tag AND other.user_id <> mine.user_id").
+		Where("mine.user_id = ?", userId).
+		Order("other.id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	shared := make(map[int64][]string)
+	for _, row := range rows {
+		shared[row.UserId] = append(shared[row.UserId], row.Tag)
+	}
+	return shared, nil
+}
@@ -0,0 +1,93 @@
+package controllers
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetUserInterests(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_interest` WHERE user_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec(regexp.QuoteMeta("INSERT INTO `user_interest` (`user_id`,`tag`,`create_time`) VALUES (?,?,?),(?,?,?)")).
+		WithArgs(int64(1), "徒步", now, int64(1), "摄影", now).
+		WillReturnResult(sqlmock.NewResult(1, 2))
+	mock.ExpectCommit()
+
+	err := SetUserInterests(1, []string{"徒步", "摄影"}, now)
+	assert.NoError(t, err)
+
+	// 清空标签时只删除
+	mock.ExpectBegin()
+	mock.ExpectExec(regexp.QuoteMeta("DELETE FROM `user_interest` WHERE user_id = ?")).
+		WithArgs(int64(1)).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	err = SetUserInterests(1, nil, now)
+	assert.NoError(t, err)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserInterests(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT `tag` FROM `user_interest` WHERE user_id = ? ORDER BY id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"tag"}).AddRow("徒步").AddRow("摄影"))
+
+	tags, err := GetUserInterests(1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"徒步", "摄影"}, tags)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetSharedInterests(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT other.user_id, other.tag FROM user_interest AS mine " +
+		"JOIN user_interest AS other ON other.tag = mine.tag AND other.user_id <> mine.user_id " +
+		"WHERE mine.user_id = ? ORDER BY other.id")).
+		WithArgs(int64(1)).
+		WillReturnRows(sqlmock.NewRows([]string{"user_id", "tag"}).
+			AddRow(2, "徒步").AddRow(3, "徒步").AddRow(2, "摄影"))
+
+	shared, err := GetSharedInterests(1)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]string{2: {"徒步", "摄影"}, 3: {"徒步"}}, shared)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGetUserInterestsByUserIds(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user_interest` WHERE user_id IN (?,?) ORDER BY id")).
+		WithArgs(int64(2), int64(3)).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "user_id", "tag", "create_time"}).
+			AddRow(1, 2, "badminton", now).
+			AddRow(2, 3, "hiking", now).
+			AddRow(3, 2, "hiking", now))
+
+	interests, err := GetUserInterestsByUserIds([]int64{2, 3})
+	assert.NoError(t, err)
+	assert.Equal(t, map[int64][]string{2: {"badminton", "hiking"}, 3: {"hiking"}}, interests)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 没有用户时不查询
+	interests, err = GetUserInterestsByUserIds(nil)
+	assert.NoError(t, err)
+	assert.Empty(t, interests)
+}
@@ -0,0 +1,84 @@
+package controllers
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"hobbyhub-server/config"
+	"hobbyhub-server/models"
+)
+
+// likeEscaper 转义 LIKE 中的通配符，使用 ! 作为转义字符以兼容 MySQL 与 SQLite
+var likeEscaper = strings.NewReplacer("!", "!!", "%", "!%", "_", "!_")
+
+// planarDistanceSQL 以度为单位的平面近似距离的平方，参数依次为中心点纬度、纬度、经度、经度及经度缩放比例的平方
+const planarDistanceSQL = "(lat - ?) * (lat - ?) + (lon - ?) * (lon - ?) * ?"
+
+// planarDistanceVars 平面近似距离的参数
+func planarDistanceVars(filter models.UserSearchFilter) []interface{} {
+	return []interface{}{filter.Lat, filter.Lat, filter.Lon, filter.Lon, filter.LonScale * filter.LonScale}
+}
+
+// whereWithinDistance 只保留半径内已设置位置且未隐藏距离的用户
+func whereWithinDistance(query *gorm.DB, filter models.UserSearchFilter) *gorm.DB {
+	return query.Where("lat BETWEEN ? AND ? AND lon BETWEEN ? AND ? AND (lat <> 0 OR lon <> 0)",
+		filter.MinLat, filter.MaxLat, filter.MinLon, filter.MaxLon).
+		Where(planarDistanceSQL+" <= ?", append(planarDistanceVars(filter), filter.RadiusDeg*filter.RadiusDeg)...).
+		Where("id NOT IN (SELECT user_id FROM user_setting WHERE hide_distance = ?)", true)
+}
+
+// orderByDistance 按距离从近到远排列，距离相同时按用户Id排列
+func orderByDistance(filter models.UserSearchFilter) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                planarDistanceSQL + ", id ASC",
+		Vars:               planarDistanceVars(filter),
+		WithoutParentheses: true,
+	}}
+}
+
+// SearchUsers 按条件分页搜索用户，排除设置了不出现在搜索结果中的用户。
+// 按距离筛选时从近到远排列；有关键字时以关键字开头的用户排在前面，其余按用户Id排列
+func SearchUsers(filter models.UserSearchFilter, page, pageSize int) ([]models.User, int64, error) {
+	query := config.DB.Model(&models.User{}).
+		Where("id NOT IN (SELECT user_id FROM user_setting WHERE hide_from_search = ?)", true)
+	if len(filter.ExcludeIds) > 0 {
+		query = query.Where("id NOT IN ?", filter.ExcludeIds)
+	}
+	keyword := likeEscaper.Replace(filter.Keyword)
+	if keyword != "" {
+		query = query.Where("username LIKE ? ESCAPE '!' OR name LIKE ? ESCAPE '!'", "%"+keyword+"%", "%"+keyword+"%")
+	}
+	if filter.Tag != "" {
+		query = query.Where("id IN (SELECT user_id FROM user_interest WHERE tag = ?)", filter.Tag)
+	}
+	if filter.WithinDistance {
+		query = whereWithinDistance(query, filter)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	switch {
+	case filter.WithinDistance:
+		query = query.Order(orderByDistance(filter))
+	case keyword != "":
+		query = query.Order(clause.OrderBy{Expression: clause.Expr{
+			SQL:                "CASE WHEN username LIKE ? ESCAPE '!' OR name LIKE ? ESCAPE '!' THEN 0 ELSE 1 END, id ASC",
+			Vars:               []interface{}{keyword + "%", keyword + "%"},
+			WithoutParentheses: true,
+		}})
+	default:
+		query = query.Order("id ASC")
+	}
+	var users []models.User
+	if err := query.Offset((page - 1) * pageSize).
+		Limit(pageSize).
+		Find(&users).Error; err != nil {
+		return nil, 0, err
+	}
+	return users, total, nil
+}
@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"database/sql/driver"
+	"regexp"
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+var userColumns = []string{"id", "username", "password", "name", "gender", "addr", "head_img", "create_time", "lat", "lon"}
+
+func TestSearchUsers(t *testing.T) {
+	mock, teardown := SetupMockDB(t)
+	defer teardown()
+
+	now := time.Now()
+	filter := models.UserSearchFilter{Keyword: "li_", Tag: "hiking", ExcludeIds: []int64{4, 1}}
+
+	// 测试场景1：按关键字及兴趣标签搜索，通配符被转义
+	where := "WHERE id NOT IN (SELECT user_id FROM user_setting WHERE hide_from_search = ?) AND id NOT IN (?,?) " +
+		"AND (username LIKE ? ESCAPE '!' OR name LIKE ? ESCAPE '!') " +
+		"AND id IN (SELECT user_id FROM user_interest WHERE tag = ?)"
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `user` "+where)).
+		WithArgs(true, int64(4), int64(1), "%li!_%", "%li!_%", "hiking").
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user` "+where+
+		" ORDER BY CASE WHEN username LIKE ? ESCAPE '!' OR name LIKE ? ESCAPE '!' THEN 0 ELSE 1 END, id ASC LIMIT ? OFFSET ?")).
+		WithArgs(true, int64(4), int64(1), "%li!_%", "%li!_%", "hiking", "li!_%", "li!_%", 10, 10).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(5, "li_lei", "", "李雷", "male", "", "", now, 0, 0))
+
+	users, total, err := SearchUsers(filter, 2, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, users, 1)
+	assert.Equal(t, "li_lei", users[0].Username)
+	assert.NoError(t, mock.ExpectationsWereMet())
+
+	// 测试场景2：按距离搜索时在数据库中筛选半径内的用户并按距离从近到远分页，排除隐藏距离的用户
+	filter = models.UserSearchFilter{
+		WithinDistance: true, Lat: 40, Lon: 116, RadiusDeg: 0.5, LonScale: 0.8,
+		MinLat: 39.5, MaxLat: 40.5, MinLon: 115.4, MaxLon: 116.6,
+	}
+	where = "WHERE id NOT IN (SELECT user_id FROM user_setting WHERE hide_from_search = ?) " +
+		"AND (lat BETWEEN ? AND ? AND lon BETWEEN ? AND ? AND (lat <> 0 OR lon <> 0)) " +
+		"AND (lat - ?) * (lat - ?) + (lon - ?) * (lon - ?) * ? <= ? " +
+		"AND id NOT IN (SELECT user_id FROM user_setting WHERE hide_distance = ?)"
+	whereArgs := []driver.Value{true, 39.5, 40.5, 115.4, 116.6, 40.0, 40.0, 116.0, 116.0, sqlmock.AnyArg(), 0.25, true}
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT count(*) FROM `user` " + where)).
+		WithArgs(whereArgs...).
+		WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(600))
+	mock.ExpectQuery(regexp.QuoteMeta("SELECT * FROM `user` " + where +
+		" ORDER BY (lat - ?) * (lat - ?) + (lon - ?) * (lon - ?) * ?, id ASC LIMIT ? OFFSET ?")).
+		WithArgs(append(whereArgs, 40.0, 40.0, 116.0, 116.0, sqlmock.AnyArg(), 10, 590)...).
+		WillReturnRows(sqlmock.NewRows(userColumns).
+			AddRow(6, "hanmeimei", "", "韩梅梅", "female", "", "", now, 40.4, 116.0))
+
+	// 半径内超过500个用户时，总数及最后一页仍然准确
+	users, total, err = SearchUsers(filter, 60, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(600), total)
+	assert.Equal(t, int64(6), users[0].Id)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
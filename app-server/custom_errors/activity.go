@@ -0,0 +1,10 @@
+package custom_errors
+
+import "errors"
+
+var ErrNotActivityMember = errors.New("user is not a member of the activity")
+var ErrActivityPermissionDenied = errors.New("permission denied for the activity")
+var ErrPhotoAlreadyAttached = errors.New("file is already attached to the activity")
+var ErrAlreadyActivityMember = errors.New("user is already a member of the activity")
+var ErrJoinRequestPending = errors.New("join request is already pending")
+var ErrPollAlreadyExists = errors.New("activity already has an open time poll")
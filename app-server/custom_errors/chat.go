@@ -0,0 +1,6 @@
+package custom_errors
+
+import "errors"
+
+var ErrNotChatRoomMember = errors.New("user is not a member of the chat room")
+var ErrChatRecalled = errors.New("chat message has been recalled")
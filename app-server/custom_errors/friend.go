@@ -0,0 +1,11 @@
+package custom_errors
+
+import "errors"
+
+var ErrFriendSelf = errors.New("unable to befriend oneself")
+var ErrFriendAlreadyExists = errors.New("friend already exists")
+var ErrFriendRequestAlreadySent = errors.New("friend request already sent")
+var ErrFriendRequestNotFound = errors.New("friend request not found")
+var ErrFriendRequestExpired = errors.New("friend request has expired")
+var ErrNotFriends = errors.New("users are not friends")
+var ErrInvalidFriendAction = errors.New("invalid friend action")
@@ -3,3 +3,5 @@ package custom_errors
 import "errors"
 
 var ErrUserIdRequired = errors.New("user ID is required")
+var ErrUserAlreadyBlocked = errors.New("user is already blocked")
+var ErrUserNotBlocked = errors.New("user is not blocked")
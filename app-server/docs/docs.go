@@ -18,7 +18,7 @@ const docTemplate = `{
     "paths": {
         "/v1/activity": {
             "get": {
-                "description": "获取所有活动的id与名称",
+                "description": "获取当前用户可见的所有活动，未登录时只返回公开活动",
                 "consumes": [
                     "application/json"
                 ],
@@ -29,6 +29,14 @@ const docTemplate = `{
                     "活动相关接口"
                 ],
                 "summary": "获取所有活动Id",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -54,7 +62,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "创建新的活动",
+                "description": "创建新的活动，state 为 4 时创建草稿活动，开始时间可通过时间投票确定",
                 "consumes": [
                     "application/json"
                 ],
@@ -110,7 +118,7 @@ const docTemplate = `{
         },
         "/v1/activity/comment/{commentId}": {
             "delete": {
-                "description": "删除指定活动的指定评论",
+                "description": "删除指定评论，评论作者、活动组织者或联合组织者可操作",
                 "consumes": [
                     "application/json"
                 ],
@@ -159,6 +167,119 @@ const docTemplate = `{
                 }
             }
         },
+        "/v1/activity/invitation": {
+            "get": {
+                "description": "获取当前用户收到的所有待处理活动邀请",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "获取收到的活动邀请",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.activityInvitationResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/invitation/{invitationId}": {
+            "post": {
+                "description": "接受或拒绝收到的活动邀请，接受后加入活动",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "处理活动邀请",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "邀请id",
+                        "name": "invitationId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "处理结果",
+                        "name": "response",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.respondInvitationRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ActivityInvitation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
         "/v1/activity/member": {
             "get": {
                 "description": "获取用户参加的所有活动",
@@ -226,6 +347,12 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token，非公开活动需要",
+                        "name": "Authorization",
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -253,7 +380,7 @@ const docTemplate = `{
                 }
             },
             "post": {
-                "description": "修改指定活动",
+                "description": "修改指定活动，活动组织者或联合组织者可操作，修改会记入历史，时间或地点变更时通知成员",
                 "consumes": [
                     "application/json"
                 ],
@@ -314,7 +441,7 @@ const docTemplate = `{
                 }
             },
             "delete": {
-                "description": "软删除指定活动",
+                "description": "软删除指定活动，活动组织者或联合组织者可操作",
                 "consumes": [
                     "application/json"
                 ],
@@ -363,9 +490,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/activity/{id}/comment": {
+        "/v1/activity/{id}/announcement": {
             "get": {
-                "description": "获取指定活动的所有评论",
+                "description": "分页获取活动公告，置顶公告在前，其余按时间倒序，并返回当前用户是否已读及已读人数",
                 "consumes": [
                     "application/json"
                 ],
@@ -375,7 +502,7 @@ const docTemplate = `{
                 "tags": [
                     "活动相关接口"
                 ],
-                "summary": "获取活动评论",
+                "summary": "获取活动公告",
                 "parameters": [
                     {
                         "type": "integer",
@@ -383,16 +510,47 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.ActivityComment"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.announcementResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -401,6 +559,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -410,7 +574,7 @@ const docTemplate = `{
                 }
             },
             "put": {
-                "description": "添加指定活动的评论",
+                "description": "活动组织者或联合组织者发布公告，公告会推送到每位成员的通知中",
                 "consumes": [
                     "application/json"
                 ],
@@ -420,7 +584,7 @@ const docTemplate = `{
                 "tags": [
                     "活动相关接口"
                 ],
-                "summary": "添加活动评论",
+                "summary": "发布活动公告",
                 "parameters": [
                     {
                         "type": "integer",
@@ -430,12 +594,12 @@ const docTemplate = `{
                         "required": true
                     },
                     {
-                        "description": "评论内容",
-                        "name": "comment",
+                        "description": "公告内容",
+                        "name": "announcement",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.activityCommentResponse"
+                            "$ref": "#/definitions/api.announcementRequest"
                         }
                     },
                     {
@@ -450,7 +614,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.ActivityAnnouncement"
                         }
                     },
                     "400": {
@@ -459,6 +623,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -468,9 +638,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/activity/{id}/member": {
-            "get": {
-                "description": "获取指定活动所有成员",
+        "/v1/activity/{id}/announcement/{announcementId}": {
+            "post": {
+                "description": "活动组织者或联合组织者修改公告内容或置顶状态，未传的字段保持不变",
                 "consumes": [
                     "application/json"
                 ],
@@ -480,7 +650,7 @@ const docTemplate = `{
                 "tags": [
                     "活动相关接口"
                 ],
-                "summary": "获取活动成员",
+                "summary": "修改活动公告",
                 "parameters": [
                     {
                         "type": "integer",
@@ -488,13 +658,36 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "公告id",
+                        "name": "announcementId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "公告内容",
+                        "name": "announcement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.announcementRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/models.ActivityAnnouncement"
                         }
                     },
                     "400": {
@@ -503,6 +696,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -511,8 +710,8 @@ const docTemplate = `{
                     }
                 }
             },
-            "put": {
-                "description": "加入指定活动",
+            "delete": {
+                "description": "活动组织者或联合组织者删除公告",
                 "consumes": [
                     "application/json"
                 ],
@@ -522,7 +721,7 @@ const docTemplate = `{
                 "tags": [
                     "活动相关接口"
                 ],
-                "summary": "加入活动",
+                "summary": "删除活动公告",
                 "parameters": [
                     {
                         "type": "integer",
@@ -531,6 +730,13 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "公告id",
+                        "name": "announcementId",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "JWT Token",
@@ -552,6 +758,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -559,9 +771,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "退出指定活动",
+            }
+        },
+        "/v1/activity/{id}/announcement/{announcementId}/read": {
+            "get": {
+                "description": "活动组织者或联合组织者查看公告的已读记录及尚未阅读的参与者",
                 "consumes": [
                     "application/json"
                 ],
@@ -571,7 +785,7 @@ const docTemplate = `{
                 "tags": [
                     "活动相关接口"
                 ],
-                "summary": "退出活动",
+                "summary": "获取公告阅读情况",
                 "parameters": [
                     {
                         "type": "integer",
@@ -580,6 +794,13 @@ const docTemplate = `{
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "公告id",
+                        "name": "announcementId",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
                         "description": "JWT Token",
@@ -592,7 +813,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/api.announcementReadStatusResponse"
                         }
                     },
                     "400": {
@@ -601,6 +822,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -608,11 +835,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/admin/activity": {
-            "put": {
-                "description": "创建一个新的活动",
+            },
+            "post": {
+                "description": "活动参与者标记公告已读，对应的通知也会一并标记为已读",
                 "consumes": [
                     "application/json"
                 ],
@@ -620,32 +845,37 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "管理相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "创建活动",
+                "summary": "标记公告已读",
                 "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "公告id",
+                        "name": "announcementId",
+                        "in": "path",
+                        "required": true
+                    },
                     {
                         "type": "string",
-                        "description": "Admin JWT Token",
+                        "description": "JWT Token",
                         "name": "Authorization",
                         "in": "header",
                         "required": true
-                    },
-                    {
-                        "description": "活动内容",
-                        "name": "activity",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/models.Activity"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Activity"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
@@ -654,14 +884,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "403": {
+                        "description": "Forbidden",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -669,9 +899,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/admin/login": {
-            "post": {
-                "description": "用于管理员的用户名密码登录",
+        "/v1/activity/{id}/chat": {
+            "get": {
+                "description": "获取活动对应的群聊，活动参与者自动成为群成员",
                 "consumes": [
                     "application/json"
                 ],
@@ -679,25 +909,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "管理相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "管理员登录",
+                "summary": "获取活动群聊",
                 "parameters": [
                     {
-                        "description": "登录请求体，包含用户名和密码",
-                        "name": "loginRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.UsernameAndPassword"
-                        }
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "JWT Token",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.JWTResponse"
+                            "$ref": "#/definitions/models.ChatRoom"
                         }
                     },
                     "400": {
@@ -706,6 +941,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -715,9 +956,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/admin/users": {
+        "/v1/activity/{id}/comment": {
             "get": {
-                "description": "获取所有用户信息",
+                "description": "获取指定活动的所有评论，只有可以查看活动的用户能获取，登录时不返回与自己存在屏蔽关系的用户的评论",
                 "consumes": [
                     "application/json"
                 ],
@@ -725,37 +966,31 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "管理相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "获取所有用户",
+                "summary": "获取活动评论",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Admin JWT Token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "integer",
-                        "description": "页码，默认为1",
-                        "name": "page",
-                        "in": "query"
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
-                        "type": "integer",
-                        "description": "每页数量，默认为10",
-                        "name": "pageSize",
-                        "in": "query"
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "用户列表",
+                        "description": "OK",
                         "schema": {
                             "type": "array",
                             "items": {
-                                "$ref": "#/definitions/models.User"
+                                "$ref": "#/definitions/models.ActivityComment"
                             }
                         }
                     },
@@ -770,19 +1005,11 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
-                    },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
                     }
                 }
-            }
-        },
-        "/v1/chat": {
-            "get": {
-                "description": "根据两个用户的id获取聊天记录列表",
+            },
+            "put": {
+                "description": "添加指定活动的评论，非公开活动只有可以查看活动的用户（成员、组织者的好友或受邀用户）能评论",
                 "consumes": [
                     "application/json"
                 ],
@@ -790,28 +1017,25 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "聊天相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "获取聊天记录",
+                "summary": "添加活动评论",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "对方用户ID",
-                        "name": "to_user_id",
-                        "in": "query",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "开始时间，格式为YYYY-MM-DD HH:MM:SS",
-                        "name": "starttime",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "结束时间，格式为YYYY-MM-DD HH:MM:SS",
-                        "name": "endtime",
-                        "in": "query"
+                        "description": "评论内容",
+                        "name": "comment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.activityCommentResponse"
+                        }
                     },
                     {
                         "type": "string",
@@ -825,10 +1049,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Chat"
-                            }
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
@@ -844,9 +1065,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "发送聊天消息",
+            }
+        },
+        "/v1/activity/{id}/cover": {
+            "put": {
+                "description": "活动组织者或联合组织者从相册中选择一张图片作为活动封面",
                 "consumes": [
                     "application/json"
                 ],
@@ -854,17 +1077,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "聊天相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "发送聊天消息",
+                "summary": "设置活动封面",
                 "parameters": [
                     {
-                        "description": "聊天记录内容",
-                        "name": "chat",
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "封面图片",
+                        "name": "cover",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.newChatRequest"
+                            "$ref": "#/definitions/api.activityCoverRequest"
                         }
                     },
                     {
@@ -879,10 +1109,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.Chat"
-                            }
+                            "$ref": "#/definitions/models.Activity"
                         }
                     },
                     "400": {
@@ -891,6 +1118,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -900,9 +1133,9 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/chat/{id}": {
-            "delete": {
-                "description": "删除聊天消息",
+        "/v1/activity/{id}/expense": {
+            "get": {
+                "description": "分页获取活动的费用历史及分摊明细，按时间倒序，仅活动参与者可查看",
                 "consumes": [
                     "application/json"
                 ],
@@ -910,17 +1143,29 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "聊天相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "删除聊天消息",
+                "summary": "获取活动费用记录",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "需要删除的Chat 记录ID",
+                        "description": "活动id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
                     {
                         "type": "string",
                         "description": "JWT Token",
@@ -933,7 +1178,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.expenseResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -942,6 +1202,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -949,48 +1215,41 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/file": {
-            "post": {
-                "description": "上传文件",
+            },
+            "put": {
+                "description": "活动参与者记录一笔费用，金额在分摊人之间平均分摊，除不尽的部分按用户Id顺序每人多摊1分",
                 "consumes": [
-                    "multipart/form-data"
+                    "application/json"
                 ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "文件相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "上传文件",
+                "summary": "记录活动费用",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "JWT token",
-                        "name": "Authorization",
-                        "in": "header",
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "文件名",
-                        "name": "filename",
-                        "in": "formData",
-                        "required": true
+                        "description": "费用信息",
+                        "name": "expense",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.addExpenseRequest"
+                        }
                     },
                     {
                         "type": "string",
-                        "description": "文件Hash",
-                        "name": "filehash",
-                        "in": "formData",
-                        "required": true
-                    },
-                    {
-                        "type": "file",
-                        "description": "文件数据",
-                        "name": "file",
-                        "in": "formData",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     }
                 ],
@@ -998,7 +1257,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.File"
+                            "$ref": "#/definitions/api.expenseResponse"
                         }
                     },
                     "400": {
@@ -1007,8 +1266,14 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
@@ -1016,38 +1281,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/file/{id}": {
+        "/v1/activity/{id}/expense/balance": {
             "get": {
-                "description": "下载文件",
+                "description": "按币种计算每位参与者的净余额（正数为应收，负数为应付）及最少笔数的结算转账建议",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
-                    "application/octet-stream",
                     "application/json"
                 ],
                 "tags": [
-                    "文件相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "下载文件",
+                "summary": "获取活动费用余额",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "JWT token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "integer",
-                        "description": "文件ID",
+                        "description": "活动id",
                         "name": "id",
                         "in": "path",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "file"
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.currencyBalanceResponse"
+                            }
                         }
                     },
                     "400": {
@@ -1056,72 +1326,54 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     }
                 }
-            },
-            "delete": {
-                "description": "删除文件",
+            }
+        },
+        "/v1/activity/{id}/history": {
+            "get": {
+                "description": "分页获取活动的修订记录及每次修改的字段，按版本号倒序，仅活动参与者可查看",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "文件相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "删除文件",
+                "summary": "获取活动修改历史",
                 "parameters": [
-                    {
-                        "type": "string",
-                        "description": "JWT token",
-                        "name": "Authorization",
-                        "in": "header",
-                        "required": true
-                    },
                     {
                         "type": "integer",
-                        "description": "文件ID",
+                        "description": "活动id",
                         "name": "id",
                         "in": "path",
                         "required": true
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
-                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
                     },
-                    "500": {
-                        "description": "Internal Server Error",
-                        "schema": {
-                            "$ref": "#/definitions/models.ErrorResponse"
-                        }
-                    }
-                }
-            }
-        },
-        "/v1/friend": {
-            "get": {
-                "description": "通过用户ID获取好友列表",
-                "produces": [
-                    "application/json"
-                ],
-                "tags": [
-                    "好友相关接口"
-                ],
-                "summary": "获取好友列表",
-                "parameters": [
                     {
                         "type": "string",
                         "description": "JWT Token",
@@ -1134,10 +1386,22 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "array",
-                            "items": {
-                                "$ref": "#/definitions/models.User"
-                            }
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.activityRevisionResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -1146,6 +1410,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1153,24 +1423,36 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/activity/{id}/invitation": {
             "put": {
-                "description": "通过ID更新好友状态",
+                "description": "活动成员邀请一个或多个好友或整个好友分组参加活动，friendIds 与 groupIds 至少指定一项。\n需审批才能加入的活动只有组织者或联合组织者可以邀请。非好友、已是成员或已有待处理邀请的用户会被跳过",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "好友相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "更新好友状态",
+                "summary": "邀请好友参加活动",
                 "parameters": [
                     {
-                        "description": "好友申请信息",
-                        "name": "updatefriendRequest",
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "被邀请的好友",
+                        "name": "invitation",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.UpdateFriendRequest"
+                            "$ref": "#/definitions/api.inviteFriendsRequest"
                         }
                     },
                     {
@@ -1185,7 +1467,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.Friend"
+                            "$ref": "#/definitions/api.inviteFriendsResponse"
                         }
                     },
                     "400": {
@@ -1194,6 +1476,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1201,25 +1489,28 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "通过用户ID发送好友申请",
+            }
+        },
+        "/v1/activity/{id}/invitation/stats": {
+            "get": {
+                "description": "活动组织者或联合组织者查看活动邀请的发送与处理情况",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "好友相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "发送好友申请",
+                "summary": "获取活动邀请统计",
                 "parameters": [
                     {
-                        "description": "好友申请信息",
-                        "name": "friendRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.FriendRequest"
-                        }
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
@@ -1233,7 +1524,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.SuccessResponse"
+                            "$ref": "#/definitions/api.invitationStatsResponse"
                         }
                     },
                     "400": {
@@ -1242,6 +1533,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1251,30 +1548,32 @@ const docTemplate = `{
                 }
             }
         },
-        "/v1/friend/{id}": {
-            "delete": {
-                "description": "通过ID删除好友状态",
+        "/v1/activity/{id}/member": {
+            "get": {
+                "description": "获取指定活动所有成员",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "好友相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "删除好友",
+                "summary": "获取活动成员",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "需要删除的Friend 记录ID",
+                        "description": "活动id",
                         "name": "id",
                         "in": "path",
                         "required": true
                     },
                     {
                         "type": "string",
-                        "description": "JWT Token",
+                        "description": "JWT Token，非公开活动需要",
                         "name": "Authorization",
-                        "in": "header",
-                        "required": true
+                        "in": "header"
                     }
                 ],
                 "responses": {
@@ -1297,11 +1596,9 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/login": {
-            "post": {
-                "description": "用户名密码登录",
+            },
+            "put": {
+                "description": "加入指定活动，需审批的活动会提交入团申请等待组织者处理",
                 "consumes": [
                     "application/json"
                 ],
@@ -1309,25 +1606,30 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "用户登录",
+                "summary": "加入活动",
                 "parameters": [
                     {
-                        "description": "登录请求体，包含用户名和密码",
-                        "name": "loginRequest",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/api.UsernameAndPassword"
-                        }
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "JWT Token",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.JWTResponse"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
@@ -1343,43 +1645,40 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/v1/user": {
-            "get": {
-                "description": "通过用户ID获取用户信息；可选用户id或用户名查询，优先使用用户id；不填写id或用户名，使用jwt token获取",
+            },
+            "delete": {
+                "description": "退出指定活动",
+                "consumes": [
+                    "application/json"
+                ],
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "用户相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "获取用户信息",
+                "summary": "退出活动",
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "用户ID",
+                        "description": "活动id",
                         "name": "id",
-                        "in": "query"
-                    },
-                    {
-                        "type": "string",
-                        "description": "用户名",
-                        "name": "username",
-                        "in": "query"
+                        "in": "path",
+                        "required": true
                     },
                     {
                         "type": "string",
                         "description": "JWT Token",
                         "name": "Authorization",
-                        "in": "header"
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.SuccessResponse"
                         }
                     },
                     "400": {
@@ -1395,9 +1694,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "put": {
-                "description": "用户名密码注册",
+            }
+        },
+        "/v1/activity/{id}/member/{userId}/role": {
+            "post": {
+                "description": "活动组织者将成员设为联合组织者或降为普通成员",
                 "consumes": [
                     "application/json"
                 ],
@@ -1405,25 +1706,46 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "用户注册",
+                "summary": "修改成员角色",
                 "parameters": [
                     {
-                        "description": "注册请求体，包含用户名和密码",
-                        "name": "loginRequest",
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "成员用户id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "目标角色",
+                        "name": "role",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/api.UsernameAndPassword"
+                            "$ref": "#/definitions/api.updateMemberRoleRequest"
                         }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "JWT Token",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/api.JWTResponse"
+                            "$ref": "#/definitions/models.ActivityMember"
                         }
                     },
                     "400": {
@@ -1432,6 +1754,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1439,9 +1767,11 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
+            }
+        },
+        "/v1/activity/{id}/owner": {
             "post": {
-                "description": "更新用户信息",
+                "description": "活动组织者将活动转让给另一名成员，原组织者成为联合组织者",
                 "consumes": [
                     "application/json"
                 ],
@@ -1449,17 +1779,24 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "用户相关接口"
+                    "活动相关接口"
                 ],
-                "summary": "更新用户信息",
+                "summary": "转让活动",
                 "parameters": [
                     {
-                        "description": "用户数据，必须包含id",
-                        "name": "userInfo",
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新组织者",
+                        "name": "owner",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/api.transferOwnershipRequest"
                         }
                     },
                     {
@@ -1472,9 +1809,9 @@ const docTemplate = `{
                 ],
                 "responses": {
                     "200": {
-                        "description": "修改后的用户信息",
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/models.User"
+                            "$ref": "#/definitions/models.Activity"
                         }
                     },
                     "400": {
@@ -1483,6 +1820,12 @@ const docTemplate = `{
                             "$ref": "#/definitions/models.ErrorResponse"
                         }
                     },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
                     "404": {
                         "description": "Not Found",
                         "schema": {
@@ -1491,151 +1834,6350 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
+        },
+        "/v1/activity/{id}/photo": {
+            "get": {
+                "description": "分页获取指定活动的相册图片，按排序序号升序，只有可以查看活动的用户能获取",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "获取活动相册",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.activityPhotoResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "活动成员将自己上传的文件添加到活动相册，内容相同的文件（包括重复上传的同一文件）在同一相册中只能添加一次",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "添加活动相册图片",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "相册图片信息",
+                        "name": "photo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.activityPhotoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.activityPhotoResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/{id}/photo/order": {
+            "put": {
+                "description": "活动组织者或联合组织者按给定的图片id顺序重排相册",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "调整活动相册顺序",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "图片顺序",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.sortActivityPhotosRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/{id}/photo/{photoId}": {
+            "post": {
+                "description": "修改相册图片的说明，上传者、活动组织者或联合组织者可操作。调整顺序请使用相册排序接口",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "修改活动相册图片",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "相册图片id",
+                        "name": "photoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "相册图片信息",
+                        "name": "photo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.updateActivityPhotoRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ActivityPhoto"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "从活动相册移除图片，上传者可删除自己的图片，活动组织者或联合组织者可删除任意图片",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "删除活动相册图片",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "相册图片id",
+                        "name": "photoId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/{id}/poll": {
+            "get": {
+                "description": "获取活动最近一次时间投票的候选开始时间、实时票数及当前用户的选择，仅活动参与者可查看",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "获取活动时间投票",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.pollResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "活动组织者或联合组织者为草稿状态的活动提出至少两个候选开始时间，同一活动同时只能有一个进行中的投票，确定时间后可再次发起",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "发起活动时间投票",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "候选时间",
+                        "name": "poll",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createPollRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.pollResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/{id}/poll/finalize": {
+            "post": {
+                "description": "活动组织者或联合组织者选定一个候选时间，活动开始时间随之更新；开始时间变化时通知所有参与者，否则通知所有投票者",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "确定活动时间",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "选定的候选时间",
+                        "name": "option",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.finalizePollRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.pollResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/{id}/poll/vote": {
+            "post": {
+                "description": "活动参与者对一个或多个候选时间投票，重复投票会覆盖之前的选择",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "参与活动时间投票",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "投票内容",
+                        "name": "votes",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.votePollRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.pollResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/{id}/request": {
+            "get": {
+                "description": "活动组织者或联合组织者获取活动所有待审批的入团申请",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "获取入团申请",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ActivityJoinRequest"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/{id}/request/{requestId}": {
+            "post": {
+                "description": "活动组织者或联合组织者通过或拒绝入团申请，通过后申请人加入活动",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "审批入团申请",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "申请id",
+                        "name": "requestId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "审批结果",
+                        "name": "review",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.reviewJoinRequestRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ActivityJoinRequest"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/activity/{id}/settlement": {
+            "get": {
+                "description": "分页获取活动已付结算的历史记录，按时间倒序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "获取活动结算记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/api.settlementResponse"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "付款人或收款人将一笔结算转账标记为已付，金额不能超过双方当前的应付与应收",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "活动相关接口"
+                ],
+                "summary": "标记结算已付",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "活动id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "结算信息",
+                        "name": "settlement",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.addSettlementRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.settlementResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/activity": {
+            "put": {
+                "description": "创建一个新的活动",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理相关接口"
+                ],
+                "summary": "创建活动",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "活动内容",
+                        "name": "activity",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Activity"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Activity"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/login": {
+            "post": {
+                "description": "用于管理员的用户名密码登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理相关接口"
+                ],
+                "summary": "管理员登录",
+                "parameters": [
+                    {
+                        "description": "登录请求体，包含用户名和密码",
+                        "name": "loginRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UsernameAndPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT Token",
+                        "schema": {
+                            "$ref": "#/definitions/api.JWTResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/admin/users": {
+            "get": {
+                "description": "获取所有用户信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "管理相关接口"
+                ],
+                "summary": "获取所有用户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Admin JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "用户列表",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.User"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat": {
+            "get": {
+                "description": "按消息Id倒序获取与指定用户的聊天记录，不包含自己已删除的消息。\n使用 before_id 翻页：下一页传入本页最小的消息Id，返回条数少于 limit 时表示没有更早的记录。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "获取聊天记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "对方用户ID",
+                        "name": "to_user_id",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "只返回Id小于该值的消息，不传则从最新消息开始",
+                        "name": "before_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回条数，默认为20，最大为100",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间，格式为YYYY-MM-DD HH:MM:SS",
+                        "name": "starttime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间，格式为YYYY-MM-DD HH:MM:SS",
+                        "name": "endtime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Chat"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "发送聊天消息，支持文本、图片、文件、活动卡片及位置消息，图片和文件消息返回附件预览信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "发送聊天消息",
+                "parameters": [
+                    {
+                        "description": "聊天记录内容",
+                        "name": "chat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.newChatRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Chat"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/conversations": {
+            "get": {
+                "description": "分页获取私聊会话列表，每个聊天对象一行，包含最近一条可见消息、未读数及对方资料，按最近消息时间倒序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "获取会话列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ChatConversation"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/read": {
+            "post": {
+                "description": "将与指定用户会话中 Id 不大于 last_id 的对方消息标记为已读，并向对方推送已读回执",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "标记消息已读",
+                "parameters": [
+                    {
+                        "description": "会话及已读位置",
+                        "name": "read",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.markChatReadRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.markChatReadResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/room": {
+            "get": {
+                "description": "分页获取当前用户加入的群聊（含活动群聊），按最后活跃时间倒序",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "获取我的群聊",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ChatRoom"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "创建普通群聊并拉入好友，创建者成为群主",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "创建群聊",
+                "parameters": [
+                    {
+                        "description": "群聊信息",
+                        "name": "room",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.createChatRoomRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChatRoom"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/room/{id}": {
+            "get": {
+                "description": "获取群聊信息及成员列表，仅群成员可查看",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "获取群聊详情",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "群聊id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.chatRoomDetailResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "群主修改普通群聊名称，活动群聊名称跟随活动",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "修改群聊名称",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "群聊id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "群聊名称",
+                        "name": "room",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.updateChatRoomRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChatRoom"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/room/{id}/member": {
+            "put": {
+                "description": "群主将好友拉入普通群聊，活动群聊成员随活动参与者同步",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "添加群成员",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "群聊id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新成员",
+                        "name": "members",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.addChatRoomMembersRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/room/{id}/member/{userId}": {
+            "delete": {
+                "description": "群主移除普通群聊成员，成员也可移除自己以退出群聊；群主退出时由最早加入的成员接任",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "移除群成员",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "群聊id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "成员用户id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/room/{id}/message": {
+            "get": {
+                "description": "分页获取群聊消息，按时间倒序，不包含自己已删除的消息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "获取群聊记录",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "群聊id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ChatRoomMessage"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "群成员向群聊发送消息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "发送群消息",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "群聊id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "消息内容",
+                        "name": "message",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.sendChatRoomMessageRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.ChatRoomMessage"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/room/{id}/message/{messageId}": {
+            "delete": {
+                "description": "从自己的群聊记录中删除一条消息，其他成员仍可见",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "删除群消息",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "群聊id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "消息id",
+                        "name": "messageId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/search": {
+            "get": {
+                "description": "在自己可见且未撤回的私聊消息中搜索关键词，按消息Id倒序分页。\nSQLite 使用 FTS5、MySQL 使用 FULLTEXT 索引，未建立全文索引时使用模糊匹配。\n每条结果包含以 \u003cem\u003e\u003c/em\u003e 高亮关键词的片段，以及同一会话中前后若干条消息用于跳转定位。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "搜索聊天记录",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "搜索关键词",
+                        "name": "q",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "只搜索与该用户的会话",
+                        "name": "to_user_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "开始时间，格式为YYYY-MM-DD HH:MM:SS",
+                        "name": "starttime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "结束时间，格式为YYYY-MM-DD HH:MM:SS",
+                        "name": "endtime",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "返回命中消息前后各多少条消息，默认为2，最大为10，0表示不返回",
+                        "name": "context",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页条数，默认为10，最大为100",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.ChatSearchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/typing": {
+            "post": {
+                "description": "通知私聊对象或群聊其他成员自己正在输入，对方通过 typing 事件收到。客户端应在输入期间每隔几秒发送一次，\n停止发送即视为结束输入。已建立实时连接时也可直接发送 {\"type\":\"typing\",\"data\":{\"userId\":2}}。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "发送正在输入提示",
+                "parameters": [
+                    {
+                        "description": "会话",
+                        "name": "typing",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.typingRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/unread": {
+            "get": {
+                "description": "获取未读私聊消息总数及各会话的未读数",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "获取未读消息数",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.chatUnreadResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/{id}": {
+            "put": {
+                "description": "发送方在撤回时限内编辑文本消息，编辑前的内容保存到编辑历史。编辑后通过 chat_edited 事件推送给双方。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "编辑聊天消息",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "消息Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "新的消息内容",
+                        "name": "chat",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.editChatRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Chat"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除聊天消息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "删除聊天消息",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "需要删除的Chat 记录ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/{id}/edits": {
+            "get": {
+                "description": "会话双方可查看消息每次编辑前的内容，按编辑时间升序。撤回的消息没有编辑历史。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "获取聊天消息的编辑历史",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "消息Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ChatEdit"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/chat/{id}/recall": {
+            "post": {
+                "description": "发送方在时限内撤回消息，双方的消息内容和附件均被清除，消息类型变为 recalled 作为“消息已撤回”占位。\n撤回后通过 chat_recalled 事件推送给双方。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "撤回聊天消息",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "消息Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Chat"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/file": {
+            "post": {
+                "description": "上传文件",
+                "consumes": [
+                    "multipart/form-data"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "文件相关接口"
+                ],
+                "summary": "上传文件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文件名",
+                        "name": "filename",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "文件Hash",
+                        "name": "filehash",
+                        "in": "formData",
+                        "required": true
+                    },
+                    {
+                        "type": "file",
+                        "description": "文件数据",
+                        "name": "file",
+                        "in": "formData",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.File"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/file/{id}": {
+            "get": {
+                "description": "下载文件",
+                "produces": [
+                    "application/octet-stream",
+                    "application/json"
+                ],
+                "tags": [
+                    "文件相关接口"
+                ],
+                "summary": "下载文件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "文件ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除文件",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "文件相关接口"
+                ],
+                "summary": "删除文件",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "文件ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend": {
+            "get": {
+                "description": "获取当前用户的所有好友关系，包括待处理及被拒绝的申请，已成为好友的记录附带在线状态、备注名及所在分组。\n指定 group_id 时只返回该分组中的好友",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "获取好友列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "好友分组Id",
+                        "name": "group_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.FriendResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "同意或拒绝对方发给自己的好友申请，不能处理自己发出的申请",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "处理好友申请",
+                "parameters": [
+                    {
+                        "description": "好友申请信息",
+                        "name": "updatefriendRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UpdateFriendRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.FriendResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "通过用户ID发送好友申请，可附带附言。对方已向自己发出申请时直接成为好友；被拒绝或已过期的申请可以重新发送",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "发送好友申请",
+                "parameters": [
+                    {
+                        "description": "好友申请信息",
+                        "name": "friendRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.FriendRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/block": {
+            "get": {
+                "description": "分页获取自己屏蔽的用户，按屏蔽时间倒序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "获取屏蔽列表",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页条数，默认为10，最大为100",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.BlockedUser"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "屏蔽后双方不能互相发送好友申请、私聊消息或活动邀请，也互相看不到对方的活动评论。\n屏蔽会解除双方的好友关系并拒绝双方之间待处理的活动邀请，取消屏蔽后好友关系不会自动恢复。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "屏蔽用户",
+                "parameters": [
+                    {
+                        "description": "要屏蔽的用户",
+                        "name": "block",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.blockUserRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/block/{userId}": {
+            "delete": {
+                "description": "取消对指定用户的屏蔽，之前解除的好友关系需要重新申请",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "取消屏蔽",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "被屏蔽的用户Id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/group": {
+            "get": {
+                "description": "获取当前用户的所有好友分组及其中的好友，按用户设置的顺序排列",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "获取好友分组",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FriendGroupInfo"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "创建一个新的好友分组，排在已有分组之后。分组名称不能重复，每个用户最多50个分组",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "创建好友分组",
+                "parameters": [
+                    {
+                        "description": "分组信息",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.friendGroupRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FriendGroupInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/group/order": {
+            "put": {
+                "description": "按给定顺序重新排列自己的全部好友分组，group_ids 必须恰好包含当前的所有分组",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "调整好友分组顺序",
+                "parameters": [
+                    {
+                        "description": "分组顺序",
+                        "name": "order",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.reorderFriendGroupsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/group/{id}": {
+            "put": {
+                "description": "修改自己的好友分组名称",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "重命名好友分组",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "分组Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "分组信息",
+                        "name": "group",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.friendGroupRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除自己的好友分组，分组中的好友关系不受影响",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "删除好友分组",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "分组Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/group/{id}/member": {
+            "put": {
+                "description": "用给定的好友替换分组中的全部成员，只能加入已互为好友的用户，一个好友可以同时在多个分组中",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "设置好友分组成员",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "分组Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "分组成员",
+                        "name": "members",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.friendGroupMembersRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.FriendGroupInfo"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/presence": {
+            "get": {
+                "description": "批量获取所有好友的在线状态（online、away、offline）及最近在线时间，按好友的隐私设置隐藏",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "获取好友在线状态",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Presence"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/remark": {
+            "put": {
+                "description": "给已互为好友的用户设置只有自己可见的备注名，备注为空时清除备注",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "设置好友备注",
+                "parameters": [
+                    {
+                        "description": "备注信息",
+                        "name": "remark",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.friendRemarkRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/request/incoming": {
+            "get": {
+                "description": "分页获取别人发给自己、尚未处理且未过期的好友申请，按申请时间倒序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "获取收到的好友申请",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页条数，默认为10，最大为100",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FriendRequestInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/request/outgoing": {
+            "get": {
+                "description": "分页获取自己发出、对方尚未处理且未过期的好友申请，按申请时间倒序",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "获取发出的好友申请",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页条数，默认为10，最大为100",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.FriendRequestInfo"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/request/{userId}": {
+            "delete": {
+                "description": "撤回自己发给指定用户、对方尚未处理的好友申请，对方通过 friend_cancelled 事件收到通知",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "撤回好友申请",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "申请对象的用户Id",
+                        "name": "userId",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/suggestions": {
+            "get": {
+                "description": "推荐可能认识的用户，按共同好友、一起参加过的活动、相同兴趣标签及距离综合排序，每条推荐附带理由。\n已是好友、有待处理好友申请、屏蔽关系中的用户及设置了不出现在搜索结果中的用户不会被推荐。\n共同活动只统计公开活动。距离理由只在双方都设置了位置且对方未隐藏距离时出现，且只给出大致公里数。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "获取好友推荐",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "返回数量，默认为20，最大为50",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.FriendSuggestion"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/friend/{id}": {
+            "delete": {
+                "description": "解除与好友的关系，只能删除自己参与且已成为好友的关系，对方通过 friend_removed 事件收到通知",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "好友相关接口"
+                ],
+                "summary": "删除好友",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "好友关系记录ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/login": {
+            "post": {
+                "description": "用户名密码登录",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "用户登录",
+                "parameters": [
+                    {
+                        "description": "登录请求体，包含用户名和密码",
+                        "name": "loginRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UsernameAndPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT Token",
+                        "schema": {
+                            "$ref": "#/definitions/api.JWTResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notification": {
+            "get": {
+                "description": "分页获取当前用户收到的通知，按时间倒序，并返回未读通知数量。\npayload 按通知类型解析：friend_request、friend_accepted 为触发用户信息及申请附言 message，\nactivity_joined 额外包含 activityName，activity_comment 额外包含 activityName、commentId 及评论摘要 excerpt。\n新通知也会通过实时连接的 notification 事件推送。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通知相关接口"
+                ],
+                "summary": "获取通知",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "为 true 时只返回未读通知",
+                        "name": "unread",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页数量，默认为10",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.NotificationPage"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.Notification"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notification/read-all": {
+            "post": {
+                "description": "将自己所有未读通知标记为已读",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通知相关接口"
+                ],
+                "summary": "全部标记已读",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.markAllNotificationsReadResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notification/unread-count": {
+            "get": {
+                "description": "获取当前用户的未读通知数量，用于显示角标",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通知相关接口"
+                ],
+                "summary": "获取未读通知数量",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.unreadNotificationResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/notification/{id}/read": {
+            "post": {
+                "description": "将自己的一条通知标记为已读，已读的通知重复标记不报错",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "通知相关接口"
+                ],
+                "summary": "标记通知已读",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "通知Id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Notification"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user": {
+            "get": {
+                "description": "通过用户ID获取用户信息；可选用户id或用户名查询，优先使用用户id；不填写id或用户名，使用jwt token获取",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "获取用户信息",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户ID",
+                        "name": "id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "用户名",
+                        "name": "username",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "用户名密码注册",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "用户注册",
+                "parameters": [
+                    {
+                        "description": "注册请求体，包含用户名和密码",
+                        "name": "loginRequest",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.UsernameAndPassword"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "JWT Token",
+                        "schema": {
+                            "$ref": "#/definitions/api.JWTResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "更新用户信息",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "更新用户信息",
+                "parameters": [
+                    {
+                        "description": "用户数据，必须包含id",
+                        "name": "userInfo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "修改后的用户信息",
+                        "schema": {
+                            "$ref": "#/definitions/models.User"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/device": {
+            "get": {
+                "description": "获取自己登记的所有推送设备，最近登记的在前",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "获取推送设备",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.DeviceToken"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "登记当前设备的推送令牌，没有实时连接时将通过推送服务收到聊天消息、好友申请、活动邀请及活动变更。\n同一令牌再次登记时转移给当前用户，退出登录时应删除令牌。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "登记推送设备",
+                "parameters": [
+                    {
+                        "description": "设备信息",
+                        "name": "device",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.registerDeviceRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeviceToken"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "删除自己登记的推送令牌，该设备之后不再收到推送",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "删除推送设备",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "设备令牌",
+                        "name": "token",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/interest": {
+            "get": {
+                "description": "获取指定用户的兴趣标签，未传 userId 时获取自己的",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "获取兴趣标签",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "用户Id",
+                        "name": "userId",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.interestsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "description": "用新的兴趣标签替换自己原有的全部标签，最多20个，每个不超过20个字符，英文统一转为小写。\n兴趣标签用于好友推荐及用户搜索。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "设置兴趣标签",
+                "parameters": [
+                    {
+                        "description": "兴趣标签",
+                        "name": "interests",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.updateInterestsRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.interestsResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/privacy": {
+            "get": {
+                "description": "获取自己的在线状态、最近在线时间、正在输入提示及用户搜索的隐私设置",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "获取隐私设置",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSetting"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "修改在线状态、最近在线时间、正在输入提示及用户搜索的隐私设置，未传的字段保持不变。修改后立即向好友推送新的在线状态。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "修改隐私设置",
+                "parameters": [
+                    {
+                        "description": "隐私设置",
+                        "name": "setting",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.updatePrivacyRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSetting"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/push-setting": {
+            "post": {
+                "description": "按类别关闭或开启离线推送（聊天消息、好友申请、活动邀请、活动变更），未传的字段保持不变。\n推送设置与隐私设置保存在同一记录中，可通过获取隐私设置接口查看。",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "修改推送设置",
+                "parameters": [
+                    {
+                        "description": "推送设置",
+                        "name": "setting",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.updatePushSettingRequest"
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.UserSetting"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/user/search": {
+            "get": {
+                "description": "按用户名或姓名搜索用户，以关键字开头的排在前面，不传关键字时按注册顺序浏览用户目录。\n可按兴趣标签筛选，也可按与自己的距离筛选（需要自己已设置位置），按距离筛选时结果从近到远排列。\n设置了不出现在搜索结果中的用户、自己及屏蔽关系中的用户不会出现；对方隐藏距离时不返回距离，也不会出现在按距离筛选的结果中。",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "用户相关接口"
+                ],
+                "summary": "搜索用户",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "关键字，匹配用户名或姓名，最多50个字符",
+                        "name": "q",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "兴趣标签",
+                        "name": "tag",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "与自己的最大距离（整数公里），1到100",
+                        "name": "distance",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "页码，默认为1",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "每页条数，默认为10，最大为100",
+                        "name": "pageSize",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/models.PageResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "items": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/models.UserSearchResult"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/v1/ws": {
+            "get": {
+                "description": "升级为 WebSocket 连接，推送新消息、消息删除、好友申请及站内通知事件。浏览器无法设置请求头时可通过 token 参数传递 JWT。\n服务端每30秒发送 {\"type\":\"ping\"}，客户端也可发送 {\"type\":\"ping\"} 并收到 {\"type\":\"pong\"}，75秒内无任何消息将断开连接。\n传入 last_id 时会先补发该Id之后的私聊消息，随后发送 backfill_done 事件。\n客户端可发送 {\"type\":\"presence\",\"data\":{\"status\":\"away\"}} 切换为离开（online 恢复），\n发送 {\"type\":\"typing\",\"data\":{\"userId\":2}} 或 {\"type\":\"typing\",\"data\":{\"roomId\":3}} 发送正在输入提示。",
+                "tags": [
+                    "聊天相关接口"
+                ],
+                "summary": "建立实时推送连接",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "JWT Token",
+                        "name": "Authorization",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "JWT Token，未设置请求头时使用",
+                        "name": "token",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "客户端已收到的最后一条私聊消息Id",
+                        "name": "last_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/models.WSEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/models.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
         "api.FriendRequest": {
             "type": "object",
-            "required": [
-                "user_id"
-            ],
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "message": {
+                    "description": "申请附言，最多100个字符",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "好友用户ID",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.FriendResponse": {
+            "type": "object",
+            "required": [
+                "create_time",
+                "friend_id",
+                "id",
+                "status"
+            ],
+            "properties": {
+                "create_time": {
+                    "description": "创建时间",
+                    "type": "string"
+                },
+                "friend_id": {
+                    "description": "好友ID",
+                    "type": "integer"
+                },
+                "group_ids": {
+                    "description": "好友所在的分组Id",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "description": "好友关系记录ID",
+                    "type": "integer"
+                },
+                "presence": {
+                    "description": "在线状态，仅已成为好友时返回",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Presence"
+                        }
+                    ]
+                },
+                "remark": {
+                    "description": "自己给好友设置的备注名",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "好友状态（0: 拒绝, 1: 接受, 2: 等待接受, 3：已发出申请）",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.JWTResponse": {
+            "type": "object",
+            "properties": {
+                "token": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.UpdateFriendRequest": {
+            "type": "object",
+            "required": [
+                "friend_id",
+                "status"
+            ],
+            "properties": {
+                "friend_id": {
+                    "description": "申请人ID",
+                    "type": "integer"
+                },
+                "status": {
+                    "description": "处理结果，0-拒绝，1-同意",
+                    "type": "integer",
+                    "enum": [
+                        0,
+                        1
+                    ]
+                }
+            }
+        },
+        "api.UsernameAndPassword": {
+            "type": "object",
+            "required": [
+                "password",
+                "username"
+            ],
+            "properties": {
+                "password": {
+                    "type": "string"
+                },
+                "username": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.activityCommentResponse": {
+            "type": "object",
+            "properties": {
+                "comment": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.activityCoverRequest": {
+            "type": "object",
+            "required": [
+                "photoId"
+            ],
+            "properties": {
+                "photoId": {
+                    "description": "相册图片Id",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.activityInvitationResponse": {
+            "type": "object",
+            "properties": {
+                "activity": {
+                    "$ref": "#/definitions/api.simpleActivity"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inviterId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.activityPhotoRequest": {
+            "type": "object",
+            "required": [
+                "fileId"
+            ],
+            "properties": {
+                "caption": {
+                    "description": "图片说明",
+                    "type": "string"
+                },
+                "fileId": {
+                    "description": "已上传的文件Id",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.activityPhotoResponse": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "caption": {
+                    "type": "string"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "fileId": {
+                    "type": "integer"
+                },
+                "height": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "sortOrder": {
+                    "type": "integer"
+                },
+                "thumbnailId": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.activityRevisionResponse": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ActivityRevisionField"
+                    }
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "editorId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.addChatRoomMembersRequest": {
+            "type": "object",
+            "properties": {
+                "user_ids": {
+                    "description": "新成员，必须是操作者的好友",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.addExpenseRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "金额，如 \"12.34\"",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "币种，如 \"CNY\"",
+                    "type": "string"
+                },
+                "description": {
+                    "description": "费用说明",
+                    "type": "string"
+                },
+                "participantIds": {
+                    "description": "分摊人Id，默认为全部活动参与者",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "payerId": {
+                    "description": "付款人Id，默认为当前用户",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.addSettlementRequest": {
+            "type": "object",
+            "required": [
+                "amount",
+                "currency",
+                "toUserId"
+            ],
+            "properties": {
+                "amount": {
+                    "description": "金额，如 \"12.34\"",
+                    "type": "string"
+                },
+                "currency": {
+                    "description": "币种",
+                    "type": "string"
+                },
+                "fromUserId": {
+                    "description": "付款人Id，默认为当前用户",
+                    "type": "integer"
+                },
+                "toUserId": {
+                    "description": "收款人Id",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.announcementReadStatusResponse": {
+            "type": "object",
+            "properties": {
+                "reads": {
+                    "description": "已读记录",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ActivityAnnouncementRead"
+                    }
+                },
+                "unreadUserIds": {
+                    "description": "尚未阅读的参与者",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.announcementRequest": {
+            "type": "object",
+            "properties": {
+                "content": {
+                    "type": "string"
+                },
+                "pinned": {
+                    "description": "0: 不置顶, 1: 置顶",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.announcementResponse": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "authorId": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pinned": {
+                    "type": "integer"
+                },
+                "read": {
+                    "description": "当前用户是否已读",
+                    "type": "boolean"
+                },
+                "readCount": {
+                    "description": "已读人数",
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updateTime": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.blockUserRequest": {
+            "type": "object",
+            "required": [
+                "user_id"
+            ],
+            "properties": {
+                "user_id": {
+                    "description": "要屏蔽的用户Id",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.chatRoomDetailResponse": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "integer"
+                },
+                "create_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "members": {
+                    "description": "群成员",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChatRoomMember"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "integer"
+                },
+                "update_time": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.chatUnreadResponse": {
+            "type": "object",
+            "properties": {
+                "conversations": {
+                    "description": "各会话的未读数，只包含有未读消息的会话",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ChatUnreadCount"
+                    }
+                },
+                "total": {
+                    "description": "未读消息总数",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.createChatRoomRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "群聊名称",
+                    "type": "string"
+                },
+                "user_ids": {
+                    "description": "初始成员，必须是创建者的好友",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.createPollRequest": {
+            "type": "object",
+            "required": [
+                "startTimes"
+            ],
+            "properties": {
+                "startTimes": {
+                    "description": "候选开始时间，格式 2006-01-02 15:04:05",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.currencyBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "balances": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.memberBalanceResponse"
+                    }
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "transfers": {
+                    "description": "建议的结算转账",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.transferResponse"
+                    }
+                }
+            }
+        },
+        "api.editChatRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "description": "新的消息内容",
+                    "type": "string"
+                }
+            }
+        },
+        "api.expenseResponse": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "creatorId": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payerId": {
+                    "type": "integer"
+                },
+                "shares": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.expenseShareResponse"
+                    }
+                }
+            }
+        },
+        "api.expenseShareResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.finalizePollRequest": {
+            "type": "object",
+            "required": [
+                "optionId"
+            ],
+            "properties": {
+                "optionId": {
+                    "description": "最终确定的候选时间Id",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.friendGroupMembersRequest": {
+            "type": "object",
+            "properties": {
+                "friend_ids": {
+                    "description": "分组中的全部好友Id，为空表示清空分组",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.friendGroupRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "分组名称，最多20个字符",
+                    "type": "string"
+                }
+            }
+        },
+        "api.friendRemarkRequest": {
+            "type": "object",
+            "required": [
+                "friend_id"
+            ],
+            "properties": {
+                "friend_id": {
+                    "description": "好友ID",
+                    "type": "integer"
+                },
+                "remark": {
+                    "description": "备注名，最多30个字符，为空表示清除备注",
+                    "type": "string"
+                }
+            }
+        },
+        "api.interestsResponse": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.invitationStatsResponse": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "declined": {
+                    "type": "integer"
+                },
+                "pending": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.inviteFriendsRequest": {
+            "type": "object",
+            "properties": {
+                "friendIds": {
+                    "description": "被邀请的好友Id列表",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "groupIds": {
+                    "description": "被邀请的好友分组Id列表，分组中的所有好友都会被邀请",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.inviteFriendsResponse": {
+            "type": "object",
+            "properties": {
+                "invited": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.skippedInvitation"
+                    }
+                }
+            }
+        },
+        "api.markAllNotificationsReadResponse": {
+            "type": "object",
+            "properties": {
+                "updated": {
+                    "description": "本次标记为已读的通知数量",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.markChatReadRequest": {
+            "type": "object",
+            "required": [
+                "last_id",
+                "user_id"
+            ],
+            "properties": {
+                "last_id": {
+                    "description": "已读到的最后一条消息Id",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "会话对方用户Id",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.markChatReadResponse": {
+            "type": "object",
+            "properties": {
+                "updated": {
+                    "description": "本次标记为已读的消息数",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.memberBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "balance": {
+                    "description": "正数为应收，负数为应付",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.newChatRequest": {
+            "type": "object",
+            "required": [
+                "user_id_to"
+            ],
+            "properties": {
+                "activity_id": {
+                    "description": "活动卡片消息的活动Id",
+                    "type": "integer"
+                },
+                "content": {
+                    "description": "聊天内容，文本消息必填，其他类型可作为附言",
+                    "type": "string"
+                },
+                "file_id": {
+                    "description": "图片或文件消息的文件Id，须为自己上传或会话中收到的文件",
+                    "type": "integer"
+                },
+                "lat": {
+                    "description": "位置消息的纬度",
+                    "type": "number"
+                },
+                "lon": {
+                    "description": "位置消息的经度",
+                    "type": "number"
+                },
+                "msg_type": {
+                    "description": "消息类型：text（默认）、image、file、activity、location",
+                    "type": "string"
+                },
+                "user_id_to": {
+                    "description": "接收者用户Id",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.pollOptionResponse": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "maybe": {
+                    "type": "integer"
+                },
+                "myChoice": {
+                    "description": "当前用户的选择，0 表示未投票",
+                    "type": "integer"
+                },
+                "no": {
+                    "type": "integer"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "yes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.pollResponse": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "creatorId": {
+                    "type": "integer"
+                },
+                "finalOptionId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.pollOptionResponse"
+                    }
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.pollVoteItem": {
+            "type": "object",
+            "properties": {
+                "choice": {
+                    "description": "1: 可以, 2: 可能, 3: 不行",
+                    "type": "integer"
+                },
+                "optionId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.registerDeviceRequest": {
+            "type": "object",
+            "required": [
+                "platform",
+                "token"
+            ],
+            "properties": {
+                "platform": {
+                    "description": "设备平台：android、ios、harmony、web",
+                    "type": "string"
+                },
+                "token": {
+                    "description": "推送服务下发的设备令牌",
+                    "type": "string"
+                }
+            }
+        },
+        "api.reorderFriendGroupsRequest": {
+            "type": "object",
+            "properties": {
+                "group_ids": {
+                    "description": "按新顺序排列的全部分组Id",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.respondInvitationRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "description": "处理结果，1-接受，2-拒绝",
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "api.reviewJoinRequestRequest": {
+            "type": "object",
+            "required": [
+                "status"
+            ],
+            "properties": {
+                "status": {
+                    "description": "审批结果，1-通过，2-拒绝",
+                    "type": "integer",
+                    "enum": [
+                        1,
+                        2
+                    ]
+                }
+            }
+        },
+        "api.sendChatRoomMessageRequest": {
+            "type": "object",
+            "required": [
+                "content"
+            ],
+            "properties": {
+                "content": {
+                    "description": "消息内容",
+                    "type": "string"
+                }
+            }
+        },
+        "api.settlementResponse": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "amount": {
+                    "type": "string"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "creatorId": {
+                    "type": "integer"
+                },
+                "currency": {
+                    "type": "string"
+                },
+                "fromUserId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "toUserId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.simpleActivity": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.skippedInvitation": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.sortActivityPhotosRequest": {
+            "type": "object",
+            "required": [
+                "photoIds"
+            ],
+            "properties": {
+                "photoIds": {
+                    "description": "按新顺序排列的相册图片Id",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "api.transferOwnershipRequest": {
+            "type": "object",
+            "properties": {
+                "userId": {
+                    "description": "新组织者用户id，必须是活动成员",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.transferResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "fromUserId": {
+                    "type": "integer"
+                },
+                "toUserId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.typingRequest": {
+            "type": "object",
+            "properties": {
+                "roomId": {
+                    "description": "群聊Id，与 userId 二选一",
+                    "type": "integer"
+                },
+                "userId": {
+                    "description": "私聊对象Id，与 roomId 二选一",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.unreadNotificationResponse": {
+            "type": "object",
+            "properties": {
+                "unreadCount": {
+                    "description": "未读通知数量",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.updateActivityPhotoRequest": {
+            "type": "object",
+            "properties": {
+                "caption": {
+                    "description": "图片说明",
+                    "type": "string"
+                }
+            }
+        },
+        "api.updateChatRoomRequest": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "description": "新的群聊名称",
+                    "type": "string"
+                }
+            }
+        },
+        "api.updateInterestsRequest": {
+            "type": "object",
+            "properties": {
+                "tags": {
+                    "description": "兴趣标签，替换原有的全部标签，空数组表示清空",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "api.updateMemberRoleRequest": {
+            "type": "object",
+            "properties": {
+                "role": {
+                    "description": "1: 设为联合组织者, 0: 降为普通成员",
+                    "type": "integer"
+                }
+            }
+        },
+        "api.updatePrivacyRequest": {
+            "type": "object",
+            "properties": {
+                "hideDistance": {
+                    "description": "不在用户搜索中展示与对方的距离，也不参与按距离筛选",
+                    "type": "boolean"
+                },
+                "hideFromSearch": {
+                    "description": "不出现在用户搜索结果中",
+                    "type": "boolean"
+                },
+                "hideLastSeen": {
+                    "description": "不向好友展示最近在线时间",
+                    "type": "boolean"
+                },
+                "hidePresence": {
+                    "description": "不向好友展示在线状态",
+                    "type": "boolean"
+                },
+                "hideTyping": {
+                    "description": "不向对方发送正在输入提示",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.updatePushSettingRequest": {
+            "type": "object",
+            "properties": {
+                "muteActivityPush": {
+                    "description": "关闭活动变更推送",
+                    "type": "boolean"
+                },
+                "muteChatPush": {
+                    "description": "关闭聊天消息推送",
+                    "type": "boolean"
+                },
+                "muteFriendPush": {
+                    "description": "关闭好友申请推送",
+                    "type": "boolean"
+                },
+                "muteInvitationPush": {
+                    "description": "关闭活动邀请推送",
+                    "type": "boolean"
+                }
+            }
+        },
+        "api.votePollRequest": {
+            "type": "object",
+            "required": [
+                "votes"
+            ],
+            "properties": {
+                "votes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/api.pollVoteItem"
+                    }
+                }
+            }
+        },
+        "models.Activity": {
+            "type": "object",
+            "properties": {
+                "addr": {
+                    "type": "string"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "headImg": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ifDelete": {
+                    "type": "integer"
+                },
+                "intro": {
+                    "type": "string"
+                },
+                "joinMode": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "startTime": {
+                    "type": "string"
+                },
+                "state": {
+                    "type": "integer"
+                },
+                "updateTime": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                },
+                "visibility": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ActivityAnnouncement": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "authorId": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "pinned": {
+                    "type": "integer"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updateTime": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ActivityAnnouncementRead": {
+            "type": "object",
+            "properties": {
+                "announcementId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "readTime": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ActivityComment": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ActivityInvitation": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "inviteeId": {
+                    "type": "integer"
+                },
+                "inviterId": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "updateTime": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ActivityJoinRequest": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "integer"
+                },
+                "updateTime": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ActivityMember": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "role": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ActivityPhoto": {
+            "type": "object",
+            "properties": {
+                "activityId": {
+                    "type": "integer"
+                },
+                "caption": {
+                    "type": "string"
+                },
+                "createTime": {
+                    "type": "string"
+                },
+                "fileId": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "sortOrder": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ActivityRevisionField": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string"
+                },
+                "newValue": {
+                    "type": "string"
+                },
+                "oldValue": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.BlockedUser": {
+            "type": "object",
+            "properties": {
+                "create_time": {
+                    "description": "屏蔽时间",
+                    "type": "string"
+                },
+                "head_img": {
+                    "description": "头像",
+                    "type": "string"
+                },
+                "name": {
+                    "description": "姓名",
+                    "type": "string"
+                },
+                "user_id": {
+                    "description": "被屏蔽者Id",
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "models.Chat": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "integer"
+                },
+                "attachment": {
+                    "description": "附件预览，仅图片和文件消息返回",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.ChatAttachment"
+                        }
+                    ]
+                },
+                "content": {
+                    "type": "string"
+                },
+                "create_time": {
+                    "type": "string"
+                },
+                "deliver_time": {
+                    "type": "string"
+                },
+                "edit_time": {
+                    "type": "string"
+                },
+                "file_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "lat": {
+                    "type": "number"
+                },
+                "lon": {
+                    "type": "number"
+                },
+                "msg_type": {
+                    "type": "string"
+                },
+                "read_time": {
+                    "type": "string"
+                },
+                "recall_time": {
+                    "type": "string"
+                },
+                "status_from": {
+                    "type": "integer"
+                },
+                "status_to": {
+                    "type": "integer"
+                },
+                "user_id_from": {
+                    "type": "integer"
+                },
+                "user_id_to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ChatAttachment": {
+            "type": "object",
+            "properties": {
+                "file_id": {
+                    "description": "附件文件Id",
+                    "type": "integer"
+                },
+                "file_name": {
+                    "description": "文件名",
+                    "type": "string"
+                },
+                "file_size": {
+                    "description": "文件大小(字节)",
+                    "type": "integer"
+                },
+                "file_type": {
+                    "description": "文件扩展名",
+                    "type": "string"
+                },
+                "height": {
+                    "description": "图片高度，非图片为0",
+                    "type": "integer"
+                },
+                "thumbnail_id": {
+                    "description": "缩略图文件Id，无缩略图为0",
+                    "type": "integer"
+                },
+                "width": {
+                    "description": "图片宽度，非图片为0",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ChatConversation": {
+            "type": "object",
             "properties": {
+                "head_img": {
+                    "description": "对方头像",
+                    "type": "string"
+                },
+                "last_message": {
+                    "description": "最近一条自己可见的消息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Chat"
+                        }
+                    ]
+                },
+                "name": {
+                    "description": "对方姓名",
+                    "type": "string"
+                },
+                "unread_count": {
+                    "description": "未读消息数",
+                    "type": "integer"
+                },
                 "user_id": {
-                    "description": "好友用户ID",
+                    "description": "会话对方用户Id",
+                    "type": "integer"
+                },
+                "username": {
+                    "description": "对方用户名",
+                    "type": "string"
+                }
+            }
+        },
+        "models.ChatEdit": {
+            "type": "object",
+            "properties": {
+                "chat_id": {
+                    "type": "integer"
+                },
+                "content": {
+                    "type": "string"
+                },
+                "edit_time": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.ChatRoom": {
+            "type": "object",
+            "properties": {
+                "activity_id": {
+                    "type": "integer"
+                },
+                "create_time": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "owner_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "integer"
+                },
+                "update_time": {
+                    "type": "string"
                 }
             }
         },
-        "api.JWTResponse": {
+        "models.ChatRoomMember": {
             "type": "object",
             "properties": {
-                "token": {
+                "id": {
+                    "type": "integer"
+                },
+                "join_time": {
                     "type": "string"
+                },
+                "role": {
+                    "type": "integer"
+                },
+                "room_id": {
+                    "type": "integer"
+                },
+                "user_id": {
+                    "type": "integer"
                 }
             }
         },
-        "api.UpdateFriendRequest": {
+        "models.ChatRoomMessage": {
             "type": "object",
-            "required": [
-                "friend_id",
-                "status"
-            ],
             "properties": {
-                "friend_id": {
-                    "description": "好友ID",
+                "content": {
+                    "type": "string"
+                },
+                "create_time": {
+                    "type": "string"
+                },
+                "id": {
                     "type": "integer"
                 },
-                "status": {
-                    "description": "申请状态，0-拒绝，1-同意",
-                    "type": "integer",
-                    "enum": [
-                        0,
-                        1
-                    ]
+                "room_id": {
+                    "type": "integer"
+                },
+                "user_id_from": {
+                    "type": "integer"
                 }
             }
         },
-        "api.UsernameAndPassword": {
+        "models.ChatSearchResult": {
             "type": "object",
-            "required": [
-                "password",
-                "username"
-            ],
             "properties": {
-                "password": {
-                    "type": "string"
+                "context_after": {
+                    "description": "同一会话中命中消息之后的消息，按Id升序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Chat"
+                    }
                 },
-                "username": {
+                "context_before": {
+                    "description": "同一会话中命中消息之前的消息，按Id升序",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Chat"
+                    }
+                },
+                "message": {
+                    "description": "命中的消息",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Chat"
+                        }
+                    ]
+                },
+                "snippet": {
+                    "description": "命中片段，关键词以 \u003cem\u003e\u003c/em\u003e 包裹",
                     "type": "string"
                 }
             }
         },
-        "api.activityCommentResponse": {
+        "models.ChatUnreadCount": {
             "type": "object",
             "properties": {
-                "comment": {
-                    "type": "string"
+                "count": {
+                    "description": "未读消息数",
+                    "type": "integer"
+                },
+                "user_id": {
+                    "description": "会话对方用户Id",
+                    "type": "integer"
                 }
             }
         },
-        "api.newChatRequest": {
+        "models.DeviceToken": {
             "type": "object",
-            "required": [
-                "content",
-                "user_id_to"
-            ],
             "properties": {
-                "content": {
-                    "description": "聊天内容",
+                "createTime": {
                     "type": "string"
                 },
-                "user_id_to": {
-                    "description": "接收者用户Id",
+                "id": {
+                    "type": "integer"
+                },
+                "platform": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "updateTime": {
+                    "type": "string"
+                },
+                "userId": {
                     "type": "integer"
                 }
             }
         },
-        "api.simpleActivity": {
+        "models.ErrorResponse": {
             "type": "object",
             "properties": {
-                "id": {
-                    "type": "integer"
-                },
-                "name": {
+                "errorMessage": {
                     "type": "string"
                 }
             }
         },
-        "models.Activity": {
+        "models.File": {
             "type": "object",
             "properties": {
-                "addr": {
+                "create_time": {
                     "type": "string"
                 },
-                "createTime": {
+                "file_hash": {
                     "type": "string"
                 },
-                "headImg": {
+                "file_name": {
+                    "type": "string"
+                },
+                "file_size": {
+                    "type": "integer"
+                },
+                "file_type": {
                     "type": "string"
                 },
+                "height": {
+                    "type": "integer"
+                },
                 "id": {
                     "type": "integer"
                 },
-                "ifDelete": {
+                "link_file_id": {
                     "type": "integer"
                 },
-                "intro": {
-                    "type": "string"
+                "thumbnail_id": {
+                    "type": "integer"
                 },
-                "lat": {
-                    "type": "number"
+                "upload_user_id": {
+                    "type": "integer"
                 },
-                "lon": {
-                    "type": "number"
+                "width": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FriendGroupInfo": {
+            "type": "object",
+            "properties": {
+                "friend_ids": {
+                    "description": "分组中的好友Id，按加入顺序排列",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "id": {
+                    "type": "integer"
                 },
                 "name": {
                     "type": "string"
                 },
-                "startTime": {
+                "sort_order": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.FriendRequestInfo": {
+            "type": "object",
+            "properties": {
+                "expire_time": {
+                    "description": "过期时间，未配置过期时为空",
                     "type": "string"
                 },
-                "state": {
-                    "type": "integer"
+                "message": {
+                    "description": "申请附言",
+                    "type": "string"
                 },
-                "updateTime": {
+                "request_time": {
+                    "description": "申请时间",
                     "type": "string"
                 },
-                "userId": {
+                "user": {
+                    "$ref": "#/definitions/models.PublicUser"
+                }
+            }
+        },
+        "models.FriendSuggestion": {
+            "type": "object",
+            "properties": {
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SuggestionReason"
+                    }
+                },
+                "score": {
                     "type": "integer"
+                },
+                "user": {
+                    "$ref": "#/definitions/models.PublicUser"
                 }
             }
         },
-        "models.ActivityComment": {
+        "models.Notification": {
             "type": "object",
             "properties": {
                 "activityId": {
                     "type": "integer"
                 },
+                "actorId": {
+                    "type": "integer"
+                },
                 "content": {
                     "type": "string"
                 },
@@ -1645,91 +8187,87 @@ const docTemplate = `{
                 "id": {
                     "type": "integer"
                 },
+                "ifRead": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "relatedId": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
                 "userId": {
                     "type": "integer"
                 }
             }
         },
-        "models.Chat": {
+        "models.NotificationPage": {
             "type": "object",
             "properties": {
-                "content": {
-                    "type": "string"
-                },
-                "create_time": {
-                    "type": "string"
-                },
-                "id": {
+                "items": {},
+                "page": {
                     "type": "integer"
                 },
-                "status_from": {
-                    "type": "integer"
-                },
-                "status_to": {
+                "pageSize": {
                     "type": "integer"
                 },
-                "user_id_from": {
+                "total": {
                     "type": "integer"
                 },
-                "user_id_to": {
+                "unreadCount": {
                     "type": "integer"
                 }
             }
         },
-        "models.ErrorResponse": {
+        "models.PageResponse": {
             "type": "object",
             "properties": {
-                "errorMessage": {
-                    "type": "string"
+                "items": {},
+                "page": {
+                    "type": "integer"
+                },
+                "pageSize": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
-        "models.File": {
+        "models.Presence": {
             "type": "object",
             "properties": {
-                "create_time": {
+                "lastSeenTime": {
+                    "description": "最近在线时间，隐藏或从未上线时为空",
                     "type": "string"
                 },
-                "file_hash": {
-                    "type": "string"
-                },
-                "file_name": {
-                    "type": "string"
-                },
-                "file_size": {
-                    "type": "integer"
-                },
-                "file_type": {
+                "status": {
+                    "description": "在线状态（online, away, offline）",
                     "type": "string"
                 },
-                "id": {
-                    "type": "integer"
-                },
-                "link_file_id": {
-                    "type": "integer"
-                },
-                "upload_user_id": {
+                "userId": {
+                    "description": "用户Id",
                     "type": "integer"
                 }
             }
         },
-        "models.Friend": {
+        "models.PublicUser": {
             "type": "object",
             "properties": {
-                "create_time": {
+                "gender": {
                     "type": "string"
                 },
-                "friend_id": {
-                    "type": "integer"
+                "headImg": {
+                    "type": "string"
                 },
                 "id": {
                     "type": "integer"
                 },
-                "status": {
-                    "type": "integer"
-                },
-                "user_id": {
-                    "type": "integer"
+                "name": {
+                    "type": "string"
                 }
             }
         },
@@ -1741,6 +8279,34 @@ const docTemplate = `{
                 }
             }
         },
+        "models.SuggestionReason": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "description": "共同好友、共同活动或相同兴趣的数量",
+                    "type": "integer"
+                },
+                "distanceKm": {
+                    "description": "大致距离（公里，向上取整）",
+                    "type": "integer"
+                },
+                "tags": {
+                    "description": "相同的兴趣标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "text": {
+                    "description": "展示用的理由说明",
+                    "type": "string"
+                },
+                "type": {
+                    "description": "理由类型",
+                    "type": "string"
+                }
+            }
+        },
         "models.User": {
             "type": "object",
             "properties": {
@@ -1775,6 +8341,72 @@ const docTemplate = `{
                     "type": "string"
                 }
             }
+        },
+        "models.UserSearchResult": {
+            "type": "object",
+            "properties": {
+                "distanceKm": {
+                    "description": "大致距离（公里，向上取整），任一方未设置位置或对方隐藏距离时为空",
+                    "type": "integer"
+                },
+                "tags": {
+                    "description": "兴趣标签",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "user": {
+                    "$ref": "#/definitions/models.PublicUser"
+                }
+            }
+        },
+        "models.UserSetting": {
+            "type": "object",
+            "properties": {
+                "hideDistance": {
+                    "type": "boolean"
+                },
+                "hideFromSearch": {
+                    "type": "boolean"
+                },
+                "hideLastSeen": {
+                    "type": "boolean"
+                },
+                "hidePresence": {
+                    "type": "boolean"
+                },
+                "hideTyping": {
+                    "type": "boolean"
+                },
+                "muteActivityPush": {
+                    "type": "boolean"
+                },
+                "muteChatPush": {
+                    "type": "boolean"
+                },
+                "muteFriendPush": {
+                    "type": "boolean"
+                },
+                "muteInvitationPush": {
+                    "type": "boolean"
+                },
+                "updateTime": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.WSEvent": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "type": {
+                    "type": "string"
+                }
+            }
         }
     }
 }`
@@ -5,6 +5,35 @@ import (
 	"time"
 )
 
+// 活动状态
+const (
+	ActivityStateNotStarted = 0 // 未开始
+	ActivityStateOngoing    = 1 // 进行中
+	ActivityStateEnded      = 2 // 已结束
+	ActivityStateCancelled  = 3 // 已取消
+	ActivityStateDraft      = 4 // 草稿，开始时间待投票确定
+)
+
+// 活动可见性
+const (
+	ActivityVisibilityPublic  = 0 // 公开
+	ActivityVisibilityFriends = 1 // 组织者好友可见
+	ActivityVisibilityInvite  = 2 // 仅受邀用户可见
+)
+
+// 活动加入方式
+const (
+	ActivityJoinOpen     = 0 // 直接加入
+	ActivityJoinApproval = 1 // 需组织者审批
+)
+
+// 活动成员角色
+const (
+	ActivityRoleMember      = 0 // 普通成员
+	ActivityRoleCoOrganizer = 1 // 联合组织者
+	ActivityRoleOrganizer   = 2 // 组织者
+)
+
 type Activity struct {
 	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'活动Id'"`
 	Name       string    `json:"name" gorm:"type:varchar(255);not null;comment:'活动名称'"`
@@ -15,10 +44,12 @@ type Activity struct {
 	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
 	UpdateTime time.Time `json:"updateTime" gorm:"not null;comment:'更新时间'"`
 	StartTime  time.Time `json:"startTime" gorm:"not null;comment:'活动开始时间'"`
-	State      int       `json:"state" gorm:"not null;default:0;comment:'活动状态（0: 未开始, 1: 进行中, 2: 已结束, 3: 已取消）'"`
+	State      int       `json:"state" gorm:"not null;default:0;comment:'活动状态（0: 未开始, 1: 进行中, 2: 已结束, 3: 已取消, 4: 草稿）'"`
 	IfDelete   int       `json:"ifDelete" gorm:"not null;default:0;comment:'删除状态（0: 正常, 1: 已删除）'"`
 	Lat        float64   `json:"lat" gorm:"comment:'纬度'"`
 	Lon        float64   `json:"lon" gorm:"comment:'经度'"`
+	Visibility int       `json:"visibility" gorm:"not null;default:0;comment:'可见性（0: 公开, 1: 组织者好友可见, 2: 仅受邀可见）'"`
+	JoinMode   int       `json:"joinMode" gorm:"not null;default:0;comment:'加入方式（0: 直接加入, 1: 需审批）'"`
 }
 
 func (Activity) TableName() string {
@@ -55,6 +86,7 @@ type ActivityMember struct {
 	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
 	ActivityId int64     `json:"activityId" gorm:"index;not null;comment:'活动Id'"`
 	UserId     int64     `json:"userId" gorm:"index;not null;comment:'用户Id'"`
+	Role       int       `json:"role" gorm:"not null;default:0;comment:'成员角色（0: 普通成员, 1: 联合组织者, 2: 组织者）'"`
 	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
 }
 
@@ -0,0 +1,29 @@
+package models
+
+import "time"
+
+type ActivityAnnouncement struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'公告Id'"`
+	ActivityId int64     `json:"activityId" gorm:"index;not null;comment:'活动Id'"`
+	AuthorId   int64     `json:"authorId" gorm:"not null;comment:'发布人Id'"`
+	Title      string    `json:"title" gorm:"type:varchar(255);not null;comment:'公告标题'"`
+	Content    string    `json:"content" gorm:"type:text;not null;comment:'公告内容'"`
+	Pinned     int       `json:"pinned" gorm:"not null;default:0;comment:'是否置顶（0: 否, 1: 是）'"`
+	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+	UpdateTime time.Time `json:"updateTime" gorm:"not null;comment:'更新时间'"`
+}
+
+func (ActivityAnnouncement) TableName() string {
+	return "activity_announcement"
+}
+
+type ActivityAnnouncementRead struct {
+	Id             int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	AnnouncementId int64     `json:"announcementId" gorm:"uniqueIndex:idx_announcement_user;not null;comment:'公告Id'"`
+	UserId         int64     `json:"userId" gorm:"uniqueIndex:idx_announcement_user;not null;comment:'用户Id'"`
+	ReadTime       time.Time `json:"readTime" gorm:"not null;comment:'阅读时间'"`
+}
+
+func (ActivityAnnouncementRead) TableName() string {
+	return "activity_announcement_read"
+}
@@ -0,0 +1,53 @@
+package models
+
+import "time"
+
+// 金额字段均以最小货币单位（分）存储
+
+type ActivityExpense struct {
+	Id          int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ActivityId  int64     `json:"activityId" gorm:"index;not null;comment:'活动Id'"`
+	PayerId     int64     `json:"payerId" gorm:"index;not null;comment:'付款人Id'"`
+	CreatorId   int64     `json:"creatorId" gorm:"not null;comment:'记录人Id'"`
+	Amount      int64     `json:"amount" gorm:"not null;comment:'金额（分）'"`
+	Currency    string    `json:"currency" gorm:"type:varchar(3);not null;comment:'币种'"`
+	Description string    `json:"description" gorm:"type:varchar(255);comment:'费用说明'"`
+	CreateTime  time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+}
+
+func (ActivityExpense) TableName() string {
+	return "activity_expense"
+}
+
+type ActivityExpenseShare struct {
+	Id        int64 `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ExpenseId int64 `json:"expenseId" gorm:"index;not null;comment:'费用Id'"`
+	UserId    int64 `json:"userId" gorm:"index;not null;comment:'分摊人Id'"`
+	Amount    int64 `json:"amount" gorm:"not null;comment:'分摊金额（分）'"`
+}
+
+func (ActivityExpenseShare) TableName() string {
+	return "activity_expense_share"
+}
+
+type ActivitySettlement struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ActivityId int64     `json:"activityId" gorm:"index;not null;comment:'活动Id'"`
+	FromUserId int64     `json:"fromUserId" gorm:"not null;comment:'付款人Id'"`
+	ToUserId   int64     `json:"toUserId" gorm:"not null;comment:'收款人Id'"`
+	Amount     int64     `json:"amount" gorm:"not null;comment:'金额（分）'"`
+	Currency   string    `json:"currency" gorm:"type:varchar(3);not null;comment:'币种'"`
+	CreatorId  int64     `json:"creatorId" gorm:"not null;comment:'记录人Id'"`
+	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+}
+
+func (ActivitySettlement) TableName() string {
+	return "activity_settlement"
+}
+
+// ExpenseAmount 按币种与用户汇总的金额，用于计算余额
+type ExpenseAmount struct {
+	Currency string
+	UserId   int64
+	Amount   int64
+}
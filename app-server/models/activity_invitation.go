@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// 活动邀请状态
+const (
+	InvitationPending  = 0 // 待处理
+	InvitationAccepted = 1 // 已接受
+	InvitationDeclined = 2 // 已拒绝
+)
+
+type ActivityInvitation struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ActivityId int64     `json:"activityId" gorm:"index;not null;comment:'活动Id'"`
+	InviterId  int64     `json:"inviterId" gorm:"index;not null;comment:'邀请人Id'"`
+	InviteeId  int64     `json:"inviteeId" gorm:"index;not null;comment:'被邀请人Id'"`
+	Status     int       `json:"status" gorm:"not null;default:0;comment:'邀请状态（0: 待处理, 1: 已接受, 2: 已拒绝）'"`
+	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+	UpdateTime time.Time `json:"updateTime" gorm:"not null;comment:'更新时间'"`
+}
+
+func (ActivityInvitation) TableName() string {
+	return "activity_invitation"
+}
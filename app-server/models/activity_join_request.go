@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// 入团申请状态
+const (
+	JoinRequestPending  = 0 // 待审批
+	JoinRequestApproved = 1 // 已通过
+	JoinRequestRejected = 2 // 已拒绝
+)
+
+type ActivityJoinRequest struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ActivityId int64     `json:"activityId" gorm:"index;not null;comment:'活动Id'"`
+	UserId     int64     `json:"userId" gorm:"index;not null;comment:'申请用户Id'"`
+	Status     int       `json:"status" gorm:"not null;default:0;comment:'申请状态（0: 待审批, 1: 已通过, 2: 已拒绝）'"`
+	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+	UpdateTime time.Time `json:"updateTime" gorm:"not null;comment:'更新时间'"`
+}
+
+func (ActivityJoinRequest) TableName() string {
+	return "activity_join_request"
+}
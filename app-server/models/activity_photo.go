@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type ActivityPhoto struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ActivityId int64     `json:"activityId" gorm:"index;not null;comment:'活动Id'"`
+	FileId     int64     `json:"fileId" gorm:"index;not null;comment:'文件Id'"`
+	UserId     int64     `json:"userId" gorm:"index;not null;comment:'上传用户Id'"`
+	Caption    string    `json:"caption" gorm:"type:varchar(255);comment:'图片说明'"`
+	SortOrder  int       `json:"sortOrder" gorm:"not null;default:0;comment:'排序序号'"`
+	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+}
+
+func (ActivityPhoto) TableName() string {
+	return "activity_photo"
+}
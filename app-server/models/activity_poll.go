@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// 时间投票状态
+const (
+	PollOpen      = 0 // 投票中
+	PollFinalized = 1 // 已确定时间
+)
+
+// 投票选项
+const (
+	PollChoiceYes   = 1 // 可以参加
+	PollChoiceMaybe = 2 // 可能参加
+	PollChoiceNo    = 3 // 无法参加
+)
+
+// ActivityPoll 活动时间投票，同一活动同时只能有一个进行中的投票
+type ActivityPoll struct {
+	Id            int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ActivityId    int64     `json:"activityId" gorm:"index:idx_activity_poll_activity;not null;comment:'活动Id'"`
+	CreatorId     int64     `json:"creatorId" gorm:"not null;comment:'发起人Id'"`
+	Status        int       `json:"status" gorm:"not null;default:0;comment:'投票状态（0: 投票中, 1: 已确定时间）'"`
+	FinalOptionId int64     `json:"finalOptionId" gorm:"not null;default:0;comment:'最终确定的选项Id'"`
+	CreateTime    time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+	UpdateTime    time.Time `json:"updateTime" gorm:"not null;comment:'更新时间'"`
+}
+
+func (ActivityPoll) TableName() string {
+	return "activity_poll"
+}
+
+type ActivityPollOption struct {
+	Id        int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	PollId    int64     `json:"pollId" gorm:"index;not null;comment:'投票Id'"`
+	StartTime time.Time `json:"startTime" gorm:"not null;comment:'候选开始时间'"`
+}
+
+func (ActivityPollOption) TableName() string {
+	return "activity_poll_option"
+}
+
+type ActivityPollVote struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	PollId     int64     `json:"pollId" gorm:"index;not null;comment:'投票Id'"`
+	OptionId   int64     `json:"optionId" gorm:"uniqueIndex:idx_option_user;not null;comment:'选项Id'"`
+	UserId     int64     `json:"userId" gorm:"uniqueIndex:idx_option_user;not null;comment:'投票人Id'"`
+	Choice     int       `json:"choice" gorm:"not null;comment:'选择（1: 可以, 2: 可能, 3: 不行）'"`
+	UpdateTime time.Time `json:"updateTime" gorm:"not null;comment:'更新时间'"`
+}
+
+func (ActivityPollVote) TableName() string {
+	return "activity_poll_vote"
+}
+
+// PollVoteCount 每个选项各选择的票数
+type PollVoteCount struct {
+	OptionId int64
+	Choice   int
+	Count    int64
+}
@@ -0,0 +1,77 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type ActivityRevision struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ActivityId int64     `json:"activityId" gorm:"uniqueIndex:idx_activity_version;not null;comment:'活动Id'"`
+	Version    int       `json:"version" gorm:"uniqueIndex:idx_activity_version;not null;comment:'版本号'"`
+	EditorId   int64     `json:"editorId" gorm:"not null;comment:'修改人Id'"`
+	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'修改时间'"`
+}
+
+func (ActivityRevision) TableName() string {
+	return "activity_revision"
+}
+
+type ActivityRevisionField struct {
+	Id         int64  `json:"-" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	RevisionId int64  `json:"-" gorm:"index;not null;comment:'修订Id'"`
+	Field      string `json:"field" gorm:"type:varchar(64);not null;comment:'字段名'"`
+	OldValue   string `json:"oldValue" gorm:"type:text;comment:'修改前的值'"`
+	NewValue   string `json:"newValue" gorm:"type:text;comment:'修改后的值'"`
+}
+
+func (ActivityRevisionField) TableName() string {
+	return "activity_revision_field"
+}
+
+// 不记录在修订历史中的字段
+var activityRevisionIgnoredFields = map[string]bool{
+	"Id":         true,
+	"CreateTime": true,
+	"UpdateTime": true,
+}
+
+// DiffActivityFields 比较活动修改前后的字段，返回以 json 字段名标识的变更列表
+func DiffActivityFields(before, after Activity) []ActivityRevisionField {
+	var changes []ActivityRevisionField
+	bv := reflect.ValueOf(before)
+	av := reflect.ValueOf(after)
+	t := bv.Type()
+
+	for i := range t.NumField() {
+		fieldType := t.Field(i)
+		if activityRevisionIgnoredFields[fieldType.Name] {
+			continue
+		}
+		oldValue := formatRevisionValue(bv.Field(i))
+		newValue := formatRevisionValue(av.Field(i))
+		if oldValue != newValue {
+			name, _, _ := strings.Cut(fieldType.Tag.Get("json"), ",")
+			changes = append(changes, ActivityRevisionField{Field: name, OldValue: oldValue, NewValue: newValue})
+		}
+	}
+	return changes
+}
+
+// formatRevisionValue 将字段值格式化为字符串，时间统一使用 2006-01-02 15:04:05 格式
+func formatRevisionValue(v reflect.Value) string {
+	switch value := v.Interface().(type) {
+	case time.Time:
+		if value.IsZero() {
+			return ""
+		}
+		return value.Format("2006-01-02 15:04:05")
+	case float64:
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffActivityFields(t *testing.T) {
+	startTime, _ := time.Parse("2006-01-02 15:04:05", "2025-06-01 10:00:00")
+	before := Activity{
+		Id:         1,
+		Name:       "羽毛球",
+		Addr:       "体育馆",
+		StartTime:  startTime,
+		UpdateTime: startTime,
+		Lat:        23.1,
+		Lon:        113.2,
+	}
+
+	// 更新时间等字段的变化不计入修订
+	after := before
+	after.UpdateTime = time.Now()
+	assert.Empty(t, DiffActivityFields(before, after))
+
+	after.Addr = "新体育馆"
+	after.StartTime = startTime.Add(2 * time.Hour)
+	after.Lat = 23.15
+	changes := DiffActivityFields(before, after)
+	assert.Equal(t, []ActivityRevisionField{
+		{Field: "addr", OldValue: "体育馆", NewValue: "新体育馆"},
+		{Field: "startTime", OldValue: "2025-06-01 10:00:00", NewValue: "2025-06-01 12:00:00"},
+		{Field: "lat", OldValue: "23.1", NewValue: "23.15"},
+	}, changes)
+}
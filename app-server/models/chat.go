@@ -6,19 +6,86 @@ import (
 )
 
 type Chat struct {
-	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
-	UserIdFrom int64     `json:"user_id_from" gorm:"index;not null;comment:'发送用户Id'"`
-	UserIdTo   int64     `json:"user_id_to" gorm:"index;not null;comment:'接收用户Id'"`
-	Content    string    `json:"content" gorm:"type:text;not null;comment:'消息内容'"`
-	CreateTime time.Time `json:"create_time" gorm:"not null;comment:'创建时间'"`
-	StatusFrom int32     `json:"status_from" gorm:"not null;default:2;comment:'发送方状态（0: 删除, 1: 正常）'"`
-	StatusTo   int32     `json:"status_to" gorm:"not null;default:2;comment:'接收方状态（0: 删除, 1: 正常）'"`
+	Id          int64      `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	UserIdFrom  int64      `json:"user_id_from" gorm:"index;not null;comment:'发送用户Id'"`
+	UserIdTo    int64      `json:"user_id_to" gorm:"index;not null;comment:'接收用户Id'"`
+	Content     string     `json:"content" gorm:"type:text;not null;comment:'消息内容'"`
+	CreateTime  time.Time  `json:"create_time" gorm:"not null;comment:'创建时间'"`
+	StatusFrom  int32      `json:"status_from" gorm:"not null;default:2;comment:'发送方状态（0: 删除, 1: 正常）'"`
+	StatusTo    int32      `json:"status_to" gorm:"not null;default:2;comment:'接收方状态（0: 删除, 1: 正常）'"`
+	DeliverTime *time.Time `json:"deliver_time" gorm:"comment:'送达接收方设备的时间，未送达为空'"`
+	ReadTime    *time.Time `json:"read_time" gorm:"comment:'接收方已读时间，未读为空'"`
+	MsgType     string     `json:"msg_type" gorm:"type:varchar(20);not null;default:'text';comment:'消息类型（text, image, file, activity, location）'"`
+	FileId      int64      `json:"file_id" gorm:"not null;default:0;index;comment:'图片或文件消息的附件文件Id'"`
+	ActivityId  int64      `json:"activity_id" gorm:"not null;default:0;comment:'分享的活动Id'"`
+	Lat         float64    `json:"lat" gorm:"not null;default:0;comment:'位置消息的纬度'"`
+	Lon         float64    `json:"lon" gorm:"not null;default:0;comment:'位置消息的经度'"`
+	RecallTime  *time.Time `json:"recall_time" gorm:"comment:'发送方撤回时间，未撤回为空'"`
+	EditTime    *time.Time `json:"edit_time" gorm:"comment:'最后编辑时间，未编辑为空'"`
+
+	Attachment *ChatAttachment `json:"attachment,omitempty" gorm:"-"` // 附件预览，仅图片和文件消息返回
+}
+
+const (
+	ChatMsgText     = "text"     // 文本消息
+	ChatMsgImage    = "image"    // 图片消息
+	ChatMsgFile     = "file"     // 文件消息
+	ChatMsgActivity = "activity" // 活动分享
+	ChatMsgLocation = "location" // 位置消息，Content 为地点描述
+	ChatMsgRecalled = "recalled" // 已撤回的消息，内容和附件均已清除，客户端显示“消息已撤回”
+)
+
+// ChatEdit 私聊消息的编辑历史，保存每次编辑前的内容
+type ChatEdit struct {
+	Id       int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	ChatId   int64     `json:"chat_id" gorm:"index;not null;comment:'消息Id'"`
+	Content  string    `json:"content" gorm:"type:text;not null;comment:'编辑前的内容'"`
+	EditTime time.Time `json:"edit_time" gorm:"not null;comment:'编辑时间'"`
+}
+
+// ChatAttachment 聊天附件预览
+type ChatAttachment struct {
+	FileId      int64  `json:"file_id"`      // 附件文件Id
+	FileName    string `json:"file_name"`    // 文件名
+	FileType    string `json:"file_type"`    // 文件扩展名
+	FileSize    int64  `json:"file_size"`    // 文件大小(字节)
+	Width       int    `json:"width"`        // 图片宽度，非图片为0
+	Height      int    `json:"height"`       // 图片高度，非图片为0
+	ThumbnailId int64  `json:"thumbnail_id"` // 缩略图文件Id，无缩略图为0
+}
+
+// ChatConversation 会话列表中的一项，每个聊天对象一行
+type ChatConversation struct {
+	UserId      int64  `json:"user_id"`      // 会话对方用户Id
+	Username    string `json:"username"`     // 对方用户名
+	Name        string `json:"name"`         // 对方姓名
+	HeadImg     string `json:"head_img"`     // 对方头像
+	UnreadCount int64  `json:"unread_count"` // 未读消息数
+	LastMessage Chat   `json:"last_message"` // 最近一条自己可见的消息
+}
+
+// ChatSearchResult 聊天搜索结果，包含高亮片段及前后若干条消息用于跳转定位
+type ChatSearchResult struct {
+	Message       Chat   `json:"message"`        // 命中的消息
+	Snippet       string `json:"snippet"`        // 命中片段，关键词以 <em></em> 包裹
+	ContextBefore []Chat `json:"context_before"` // 同一会话中命中消息之前的消息，按Id升序
+	ContextAfter  []Chat `json:"context_after"`  // 同一会话中命中消息之后的消息，按Id升序
+}
+
+// ChatUnreadCount 与某个用户会话中的未读消息数
+type ChatUnreadCount struct {
+	UserId int64 `json:"user_id"` // 会话对方用户Id
+	Count  int64 `json:"count"`   // 未读消息数
 }
 
 func (Chat) TableName() string {
 	return "chat"
 }
 
+func (ChatEdit) TableName() string {
+	return "chat_edit"
+}
+
 func (u *Chat) UpdateChatFields(newu Chat) {
 	// 使用反射来检查字段是否为零值，避免硬编码每个字段
 	v := reflect.ValueOf(newu)
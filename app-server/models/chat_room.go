@@ -0,0 +1,63 @@
+package models
+
+import "time"
+
+const (
+	ChatRoomTypeGroup    = 0 // 用户创建的群聊
+	ChatRoomTypeActivity = 1 // 活动群聊，成员与活动参与者同步
+)
+
+const (
+	ChatRoomRoleMember = 0 // 普通成员
+	ChatRoomRoleOwner  = 1 // 群主
+)
+
+type ChatRoom struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'群聊Id'"`
+	Type       int       `json:"type" gorm:"not null;default:0;comment:'群聊类型（0: 普通群聊, 1: 活动群聊）'"`
+	ActivityId int64     `json:"activity_id" gorm:"index;not null;default:0;comment:'活动Id，普通群聊为0'"`
+	Name       string    `json:"name" gorm:"type:varchar(64);not null;comment:'群聊名称'"`
+	OwnerId    int64     `json:"owner_id" gorm:"not null;comment:'群主用户Id'"`
+	CreateTime time.Time `json:"create_time" gorm:"not null;comment:'创建时间'"`
+	UpdateTime time.Time `json:"update_time" gorm:"not null;comment:'最后活跃时间'"`
+}
+
+func (ChatRoom) TableName() string {
+	return "chat_room"
+}
+
+type ChatRoomMember struct {
+	Id       int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	RoomId   int64     `json:"room_id" gorm:"uniqueIndex:idx_room_user;not null;comment:'群聊Id'"`
+	UserId   int64     `json:"user_id" gorm:"uniqueIndex:idx_room_user;index;not null;comment:'用户Id'"`
+	Role     int       `json:"role" gorm:"not null;default:0;comment:'角色（0: 成员, 1: 群主）'"`
+	JoinTime time.Time `json:"join_time" gorm:"not null;comment:'加入时间'"`
+}
+
+func (ChatRoomMember) TableName() string {
+	return "chat_room_member"
+}
+
+type ChatRoomMessage struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'消息Id'"`
+	RoomId     int64     `json:"room_id" gorm:"index;not null;comment:'群聊Id'"`
+	UserIdFrom int64     `json:"user_id_from" gorm:"not null;comment:'发送用户Id'"`
+	Content    string    `json:"content" gorm:"type:text;not null;comment:'消息内容'"`
+	CreateTime time.Time `json:"create_time" gorm:"not null;comment:'发送时间'"`
+}
+
+func (ChatRoomMessage) TableName() string {
+	return "chat_room_message"
+}
+
+// ChatRoomMessageDeletion 记录成员删除的群消息，删除只对该成员自己生效
+type ChatRoomMessageDeletion struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	MessageId  int64     `json:"message_id" gorm:"uniqueIndex:idx_message_user;not null;comment:'消息Id'"`
+	UserId     int64     `json:"user_id" gorm:"uniqueIndex:idx_message_user;not null;comment:'用户Id'"`
+	CreateTime time.Time `json:"create_time" gorm:"not null;comment:'删除时间'"`
+}
+
+func (ChatRoomMessageDeletion) TableName() string {
+	return "chat_room_message_deletion"
+}
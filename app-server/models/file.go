@@ -11,6 +11,9 @@ type File struct {
 	CreateTime   time.Time `json:"create_time" gorm:"not null;comment:'创建时间'"`
 	LinkFileId   int64     `json:"link_file_id" gorm:"not null;default:0;index;comment:'关联文件Id'"`
 	UpLoadUserId int64     `json:"upload_user_id" gorm:"not null;index;comment:'上传用户Id'"`
+	Width        int       `json:"width" gorm:"not null;default:0;comment:'图片宽度'"`
+	Height       int       `json:"height" gorm:"not null;default:0;comment:'图片高度'"`
+	ThumbnailId  int64     `json:"thumbnail_id" gorm:"not null;default:0;comment:'缩略图文件Id'"`
 }
 
 // 定义表名
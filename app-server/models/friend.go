@@ -1,44 +1,171 @@
 package models
 
 import (
-	"reflect"
+	"sort"
 	"time"
 )
 
-type Friend struct {
-	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
-	UserId     int64     `json:"user_id" gorm:"index;not null;comment:'用户Id'"`
-	FriendId   int64     `json:"friend_id" gorm:"index;not null;comment:'好友Id'"`
-	Status     int       `json:"status" gorm:"not null default:0;comment:'状态（0: 拒绝, 1: 接受, 2: 等待接受, 3：已发出申请）"`
-	CreateTime time.Time `json:"create_time" gorm:"not null;comment:'创建时间'"`
+// 好友关系状态
+const (
+	FriendshipRejected = 0 // 申请被拒绝或已过期
+	FriendshipAccepted = 1 // 已互为好友
+	FriendshipPending  = 2 // 申请待处理
+)
+
+// 从某一方看到的好友状态，与接口返回的 status 一致
+const (
+	FriendStatusRejected = 0 // 拒绝
+	FriendStatusAccepted = 1 // 已是好友
+	FriendStatusIncoming = 2 // 等待自己接受
+	FriendStatusOutgoing = 3 // 自己已发出申请
+)
+
+// 好友关系操作
+const (
+	FriendActionRequest  = "request"  // 发出申请，对方已向自己发出申请时直接成为好友
+	FriendActionAccept   = "accept"   // 接受对方的申请
+	FriendActionReject   = "reject"   // 拒绝对方的申请
+	FriendActionCancel   = "cancel"   // 撤回自己发出的申请
+	FriendActionUnfriend = "unfriend" // 解除好友关系
+	FriendActionBlock    = "block"    // 屏蔽对方，无论当前状态都解除关系
+)
+
+// Friendship 两个用户之间唯一的好友关系记录，UserLowId 恒小于 UserHighId。
+// 撤回申请、解除好友及屏蔽时删除记录
+type Friendship struct {
+	Id          int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	UserLowId   int64     `json:"userLowId" gorm:"uniqueIndex:idx_friendship_pair;not null;comment:'两个用户中较小的用户Id'"`
+	UserHighId  int64     `json:"userHighId" gorm:"uniqueIndex:idx_friendship_pair;index;not null;comment:'两个用户中较大的用户Id'"`
+	RequesterId int64     `json:"requesterId" gorm:"not null;comment:'最近一次发出好友申请的用户Id'"`
+	Status      int       `json:"status" gorm:"not null;default:0;index;comment:'状态（0: 拒绝, 1: 已是好友, 2: 申请待处理）'"`
+	Message     string    `json:"message" gorm:"type:varchar(255);not null;default:'';comment:'好友申请附言'"`
+	RequestTime time.Time `json:"requestTime" gorm:"not null;comment:'最近一次发出好友申请的时间，用于判断申请是否过期'"`
+	CreateTime  time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+	UpdateTime  time.Time `json:"updateTime" gorm:"not null;comment:'更新时间'"`
 }
 
-func (f *Friend) TableName() string {
-	return "friend"
+func (Friendship) TableName() string {
+	return "friendship"
+}
+
+// FriendshipPair 返回两个用户Id中较小和较大的一个，作为好友关系的唯一键
+func FriendshipPair(userId, otherId int64) (int64, int64) {
+	if userId < otherId {
+		return userId, otherId
+	}
+	return otherId, userId
+}
+
+// OtherUserId 返回好友关系中除 userId 外的另一方
+func (f *Friendship) OtherUserId(userId int64) int64 {
+	if f.UserLowId == userId {
+		return f.UserHighId
+	}
+	return f.UserLowId
+}
+
+// StatusFor 返回从 userId 一方看到的好友状态
+func (f *Friendship) StatusFor(userId int64) int {
+	switch f.Status {
+	case FriendshipAccepted:
+		return FriendStatusAccepted
+	case FriendshipPending:
+		if f.RequesterId == userId {
+			return FriendStatusOutgoing
+		}
+		return FriendStatusIncoming
+	}
+	return FriendStatusRejected
 }
 
-func (u *Friend) UpdateFriendFields(newu Friend) {
-	// 使用反射来检查字段是否为零值，避免硬编码每个字段
-	v := reflect.ValueOf(newu)
-	t := reflect.TypeOf(newu)
+// LegacyFriend 旧版好友记录，每对好友在 friend 表中各有一条记录，仅用于迁移到 Friendship
+type LegacyFriend struct {
+	Id          int64
+	UserId      int64
+	FriendId    int64
+	Status      int // 0: 拒绝, 1: 接受, 2: 等待接受, 3：已发出申请
+	CreateTime  time.Time
+	Message     string
+	RequestTime *time.Time
+}
 
-	dbv := reflect.ValueOf(u).Elem()
+func (LegacyFriend) TableName() string {
+	return "friend"
+}
 
-	for i := range v.NumField() {
-		field := v.Field(i)
-		fieldType := t.Field(i)
+// MergeLegacyFriends 将旧版双向记录按用户对合并为好友关系。
+// 两条记录不一致时，任一方为拒绝则视为拒绝，否则有待处理申请则视为待处理，只有双方都为接受才视为好友
+func MergeLegacyFriends(rows []LegacyFriend, now time.Time) []Friendship {
+	pairs := make(map[[2]int64][]LegacyFriend)
+	for _, row := range rows {
+		if row.UserId == row.FriendId {
+			continue
+		}
+		low, high := FriendshipPair(row.UserId, row.FriendId)
+		pairs[[2]int64{low, high}] = append(pairs[[2]int64{low, high}], row)
+	}
 
-		// 检查字段是否为零值
-		if !reflect.DeepEqual(field.Interface(), reflect.Zero(field.Type()).Interface()) {
-			// 跳过 ID 字段，通常不希望更新主键
-			if fieldType.Name == "ID" {
-				continue
+	friendships := make([]Friendship, 0, len(pairs))
+	for pair, records := range pairs {
+		friendship := Friendship{UserLowId: pair[0], UserHighId: pair[1], UpdateTime: now}
+		rejected, accepted := false, 0
+		for _, record := range records {
+			if friendship.CreateTime.IsZero() || record.CreateTime.Before(friendship.CreateTime) {
+				friendship.CreateTime = record.CreateTime
+			}
+			switch record.Status {
+			case FriendStatusRejected:
+				rejected = true
+			case FriendStatusAccepted:
+				accepted++
+			case FriendStatusOutgoing:
+				friendship.RequesterId = record.UserId
+			case FriendStatusIncoming:
+				if friendship.RequesterId == 0 {
+					friendship.RequesterId = record.FriendId
+				}
+			}
+			if record.Message != "" {
+				friendship.Message = record.Message
 			}
-			// 更新非零值字段
-			dbField := dbv.FieldByName(fieldType.Name)
-			if dbField.IsValid() && dbField.CanSet() {
-				dbField.Set(field)
+			if record.RequestTime != nil && record.RequestTime.After(friendship.RequestTime) {
+				friendship.RequestTime = *record.RequestTime
 			}
 		}
+
+		switch {
+		case rejected:
+			friendship.Status = FriendshipRejected
+		case friendship.RequesterId != 0:
+			friendship.Status = FriendshipPending
+		case accepted == 2:
+			friendship.Status = FriendshipAccepted
+		default:
+			// 缺少对方记录的接受状态无法确认，按拒绝处理
+			friendship.Status = FriendshipRejected
+		}
+		if friendship.RequesterId == 0 {
+			friendship.RequesterId = records[0].UserId
+		}
+		if friendship.RequestTime.IsZero() {
+			friendship.RequestTime = friendship.CreateTime
+		}
+		friendships = append(friendships, friendship)
 	}
+
+	sort.Slice(friendships, func(i, j int) bool {
+		if friendships[i].UserLowId != friendships[j].UserLowId {
+			return friendships[i].UserLowId < friendships[j].UserLowId
+		}
+		return friendships[i].UserHighId < friendships[j].UserHighId
+	})
+	return friendships
+}
+
+// FriendRequestInfo 待处理的好友申请，User 为申请的另一方
+type FriendRequestInfo struct {
+	User        PublicUser `json:"user"`
+	Message     string     `json:"message"`               // 申请附言
+	RequestTime time.Time  `json:"request_time"`          // 申请时间
+	ExpireTime  *time.Time `json:"expire_time,omitempty"` // 过期时间，未配置过期时为空
 }
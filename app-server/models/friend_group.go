@@ -0,0 +1,51 @@
+package models
+
+import "time"
+
+// FriendGroup 用户自定义的好友分组，只对创建者可见
+type FriendGroup struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	UserId     int64     `json:"user_id" gorm:"uniqueIndex:idx_friend_group_name;not null;comment:'分组所属用户Id'"`
+	Name       string    `json:"name" gorm:"type:varchar(32);uniqueIndex:idx_friend_group_name;not null;comment:'分组名称'"`
+	SortOrder  int       `json:"sort_order" gorm:"not null;default:0;comment:'排序，越小越靠前'"`
+	CreateTime time.Time `json:"create_time" gorm:"not null;comment:'创建时间'"`
+	UpdateTime time.Time `json:"update_time" gorm:"not null;comment:'更新时间'"`
+}
+
+func (FriendGroup) TableName() string {
+	return "friend_group"
+}
+
+// FriendGroupMember 分组中的好友，一个好友可以在多个分组中
+type FriendGroupMember struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	GroupId    int64     `json:"group_id" gorm:"uniqueIndex:idx_friend_group_member;not null;comment:'分组Id'"`
+	UserId     int64     `json:"user_id" gorm:"index:idx_friend_group_member_user;not null;comment:'分组所属用户Id'"`
+	FriendId   int64     `json:"friend_id" gorm:"uniqueIndex:idx_friend_group_member;index:idx_friend_group_member_user;not null;comment:'好友Id'"`
+	CreateTime time.Time `json:"create_time" gorm:"not null;comment:'加入分组时间'"`
+}
+
+func (FriendGroupMember) TableName() string {
+	return "friend_group_member"
+}
+
+// FriendRemark 用户给好友设置的备注名，只对设置者可见
+type FriendRemark struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	UserId     int64     `json:"user_id" gorm:"uniqueIndex:idx_friend_remark;not null;comment:'设置备注的用户Id'"`
+	FriendId   int64     `json:"friend_id" gorm:"uniqueIndex:idx_friend_remark;not null;comment:'好友Id'"`
+	Remark     string    `json:"remark" gorm:"type:varchar(64);not null;comment:'备注名'"`
+	UpdateTime time.Time `json:"update_time" gorm:"not null;comment:'更新时间'"`
+}
+
+func (FriendRemark) TableName() string {
+	return "friend_remark"
+}
+
+// FriendGroupInfo 好友分组及其中的好友Id
+type FriendGroupInfo struct {
+	Id        int64   `json:"id"`
+	Name      string  `json:"name"`
+	SortOrder int     `json:"sort_order"`
+	FriendIds []int64 `json:"friend_ids"` // 分组中的好友Id，按加入顺序排列
+}
@@ -0,0 +1,38 @@
+package models
+
+// 好友推荐理由类型
+const (
+	SuggestionMutualFriends    = "mutual_friends"    // 共同好友
+	SuggestionSharedActivities = "shared_activities" // 共同参加的活动
+	SuggestionSharedInterests  = "shared_interests"  // 相同的兴趣标签
+	SuggestionNearby           = "nearby"            // 距离较近
+)
+
+// PublicUser 展示给其他用户的公开信息，不包含用户名、地址及位置
+type PublicUser struct {
+	Id      int64  `json:"id"`
+	Name    string `json:"name"`
+	Gender  string `json:"gender"`
+	HeadImg string `json:"headImg"`
+}
+
+// NewPublicUser 从用户详情中提取公开信息
+func NewPublicUser(user User) PublicUser {
+	return PublicUser{Id: user.Id, Name: user.Name, Gender: user.Gender, HeadImg: user.HeadImg}
+}
+
+// SuggestionReason 推荐理由
+type SuggestionReason struct {
+	Type       string   `json:"type"`                 // 理由类型
+	Count      int      `json:"count,omitempty"`      // 共同好友、共同活动或相同兴趣的数量
+	Tags       []string `json:"tags,omitempty"`       // 相同的兴趣标签
+	DistanceKm int      `json:"distanceKm,omitempty"` // 大致距离（公里，向上取整）
+	Text       string   `json:"text"`                 // 展示用的理由说明
+}
+
+// FriendSuggestion 好友推荐，按 Score 从高到低排列
+type FriendSuggestion struct {
+	User    PublicUser         `json:"user"`
+	Score   int                `json:"score"`
+	Reasons []SuggestionReason `json:"reasons"`
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFriendshipStatusFor(t *testing.T) {
+	friendship := Friendship{UserLowId: 1, UserHighId: 2, RequesterId: 2, Status: FriendshipPending}
+	assert.Equal(t, FriendStatusIncoming, friendship.StatusFor(1))
+	assert.Equal(t, FriendStatusOutgoing, friendship.StatusFor(2))
+	assert.Equal(t, int64(2), friendship.OtherUserId(1))
+	assert.Equal(t, int64(1), friendship.OtherUserId(2))
+
+	friendship.Status = FriendshipAccepted
+	assert.Equal(t, FriendStatusAccepted, friendship.StatusFor(1))
+	friendship.Status = FriendshipRejected
+	assert.Equal(t, FriendStatusRejected, friendship.StatusFor(2))
+}
+
+func TestMergeLegacyFriends(t *testing.T) {
+	now := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	day1 := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	day2 := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	rows := []LegacyFriend{
+		// 1 和 2 已是好友
+		{UserId: 1, FriendId: 2, Status: 1, CreateTime: day2},
+		{UserId: 2, FriendId: 1, Status: 1, CreateTime: day1},
+		// 5 向 3 发出申请，记录了附言和申请时间
+		{UserId: 5, FriendId: 3, Status: 3, CreateTime: day1, Message: "你好", RequestTime: &day2},
+		{UserId: 3, FriendId: 5, Status: 2, CreateTime: day1, Message: "你好", RequestTime: &day2},
+		// 旧版删除好友只把一方改为0，视为拒绝
+		{UserId: 4, FriendId: 1, Status: 0, CreateTime: day1},
+		{UserId: 1, FriendId: 4, Status: 1, CreateTime: day1},
+		// 只剩一方的接受记录无法确认，视为拒绝
+		{UserId: 6, FriendId: 7, Status: 1, CreateTime: day1},
+		// 自己加自己的脏数据被忽略
+		{UserId: 8, FriendId: 8, Status: 1, CreateTime: day1},
+	}
+
+	assert.Equal(t, []Friendship{
+		{UserLowId: 1, UserHighId: 2, RequesterId: 1, Status: FriendshipAccepted, RequestTime: day1, CreateTime: day1, UpdateTime: now},
+		{UserLowId: 1, UserHighId: 4, RequesterId: 4, Status: FriendshipRejected, RequestTime: day1, CreateTime: day1, UpdateTime: now},
+		{UserLowId: 3, UserHighId: 5, RequesterId: 5, Status: FriendshipPending, Message: "你好", RequestTime: day2, CreateTime: day1, UpdateTime: now},
+		{UserLowId: 6, UserHighId: 7, RequesterId: 6, Status: FriendshipRejected, RequestTime: day1, CreateTime: day1, UpdateTime: now},
+	}, MergeLegacyFriends(rows, now))
+}
@@ -7,3 +7,10 @@ type ErrorResponse struct {
 type SuccessResponse struct {
 	SuccessMessage string `json:"successMessage"`
 }
+
+type PageResponse struct {
+	Total    int64       `json:"total"`
+	Page     int         `json:"page"`
+	PageSize int         `json:"pageSize"`
+	Items    interface{} `json:"items"`
+}
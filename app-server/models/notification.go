@@ -0,0 +1,74 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// 通知类型
+const (
+	NotificationActivityTimeFinalized = "activity_time_finalized" // 活动时间投票已确定
+	NotificationActivityUpdated       = "activity_updated"        // 活动时间或地点变更
+	NotificationActivityAnnouncement  = "activity_announcement"   // 活动公告
+	NotificationFriendRequest         = "friend_request"          // 收到好友申请
+	NotificationFriendAccepted        = "friend_accepted"         // 好友申请被同意
+	NotificationActivityJoined        = "activity_joined"         // 有人加入了自己创建的活动
+	NotificationActivityComment       = "activity_comment"        // 自己创建的活动收到评论
+)
+
+// Notification 站内通知，Payload 为按通知类型解析的附加数据，结构见各 *NotificationPayload
+type Notification struct {
+	Id         int64           `json:"id" gorm:"primaryKey;autoIncrement;comment:'通知Id'"`
+	UserId     int64           `json:"userId" gorm:"index;not null;comment:'接收人Id'"`
+	Type       string          `json:"type" gorm:"type:varchar(64);not null;comment:'通知类型'"`
+	Content    string          `json:"content" gorm:"type:varchar(512);not null;comment:'通知内容'"`
+	ActivityId int64           `json:"activityId" gorm:"not null;default:0;comment:'关联活动Id'"`
+	RelatedId  int64           `json:"relatedId" gorm:"not null;default:0;comment:'关联对象Id，如公告Id'"`
+	ActorId    int64           `json:"actorId" gorm:"not null;default:0;comment:'触发通知的用户Id，系统通知为0'"`
+	Payload    json.RawMessage `json:"payload,omitempty" gorm:"type:text;comment:'附加数据（JSON）'" swaggertype:"object"`
+	IfRead     int             `json:"ifRead" gorm:"not null;default:0;comment:'是否已读（0: 未读, 1: 已读）'"`
+	CreateTime time.Time       `json:"createTime" gorm:"not null;comment:'创建时间'"`
+}
+
+func (Notification) TableName() string {
+	return "notification"
+}
+
+// SetPayload 将附加数据序列化后写入通知
+func (n *Notification) SetPayload(payload interface{}) {
+	n.Payload, _ = json.Marshal(payload)
+}
+
+// UserNotificationPayload 触发通知的用户信息
+type UserNotificationPayload struct {
+	UserId   int64  `json:"userId"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	HeadImg  string `json:"headImg"`
+}
+
+// FriendNotificationPayload 好友申请及好友申请被同意的附加数据
+type FriendNotificationPayload struct {
+	UserNotificationPayload
+	Message string `json:"message,omitempty"` // 好友申请附言
+}
+
+// ActivityJoinedNotificationPayload 有人加入活动的附加数据
+type ActivityJoinedNotificationPayload struct {
+	UserNotificationPayload
+	ActivityName string `json:"activityName"`
+}
+
+// ActivityCommentNotificationPayload 活动收到评论的附加数据
+type ActivityCommentNotificationPayload struct {
+	UserNotificationPayload
+	ActivityName string `json:"activityName"`
+	CommentId    int64  `json:"commentId"`
+	Excerpt      string `json:"excerpt"` // 评论内容摘要
+}
+
+// NotificationPage 通知列表，附带未读数量
+type NotificationPage struct {
+	PageResponse
+	UnreadCount int64 `json:"unreadCount"`
+}
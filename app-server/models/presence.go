@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// 在线状态
+const (
+	PresenceOnline  = "online"  // 在线
+	PresenceAway    = "away"    // 已连接但长时间无操作，或客户端切到后台
+	PresenceOffline = "offline" // 离线
+)
+
+// UserPresence 用户最近在线时间，在线期间定期写入，断开连接时立即写入
+type UserPresence struct {
+	UserId       int64     `json:"userId" gorm:"primaryKey;autoIncrement:false;comment:'用户Id'"`
+	LastSeenTime time.Time `json:"lastSeenTime" gorm:"not null;comment:'最近在线时间'"`
+}
+
+func (UserPresence) TableName() string {
+	return "user_presence"
+}
+
+// UserSetting 用户隐私及推送设置，未设置时所有选项均为默认值
+type UserSetting struct {
+	UserId       int64 `json:"userId" gorm:"primaryKey;autoIncrement:false;comment:'用户Id'"`
+	HidePresence bool  `json:"hidePresence" gorm:"not null;default:false;comment:'不向好友展示在线状态'"`
+	HideLastSeen bool  `json:"hideLastSeen" gorm:"not null;default:false;comment:'不向好友展示最近在线时间'"`
+	HideTyping   bool  `json:"hideTyping" gorm:"not null;default:false;comment:'不向对方发送正在输入提示'"`
+
+	HideFromSearch bool `json:"hideFromSearch" gorm:"not null;default:false;comment:'不出现在用户搜索结果中'"`
+	HideDistance   bool `json:"hideDistance" gorm:"not null;default:false;comment:'不在用户搜索中展示与对方的距离，也不参与按距离筛选'"`
+
+	MuteChatPush       bool `json:"muteChatPush" gorm:"not null;default:false;comment:'关闭聊天消息推送'"`
+	MuteFriendPush     bool `json:"muteFriendPush" gorm:"not null;default:false;comment:'关闭好友申请推送'"`
+	MuteInvitationPush bool `json:"muteInvitationPush" gorm:"not null;default:false;comment:'关闭活动邀请推送'"`
+	MuteActivityPush   bool `json:"muteActivityPush" gorm:"not null;default:false;comment:'关闭活动变更推送'"`
+
+	UpdateTime time.Time `json:"updateTime" gorm:"comment:'更新时间'"`
+}
+
+func (UserSetting) TableName() string {
+	return "user_setting"
+}
+
+// Presence 展示给好友的在线状态，隐藏时状态为离线且不返回最近在线时间
+type Presence struct {
+	UserId       int64      `json:"userId"`       // 用户Id
+	Status       string     `json:"status"`       // 在线状态（online, away, offline）
+	LastSeenTime *time.Time `json:"lastSeenTime"` // 最近在线时间，隐藏或从未上线时为空
+}
+
+// PushMuted 判断用户是否关闭了该类别的推送
+func (s UserSetting) PushMuted(category string) bool {
+	switch category {
+	case PushCategoryChat:
+		return s.MuteChatPush
+	case PushCategoryFriend:
+		return s.MuteFriendPush
+	case PushCategoryInvitation:
+		return s.MuteInvitationPush
+	case PushCategoryActivity:
+		return s.MuteActivityPush
+	}
+	return false
+}
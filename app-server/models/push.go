@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+// 推送类别，对应用户可单独关闭的推送设置
+const (
+	PushCategoryChat       = "chat"       // 私聊及群聊消息
+	PushCategoryFriend     = "friend"     // 好友申请及处理结果
+	PushCategoryInvitation = "invitation" // 活动邀请
+	PushCategoryActivity   = "activity"   // 活动变更、公告及时间投票结果
+)
+
+// DeviceToken 用户设备的推送令牌，同一令牌只属于最后登记的用户
+type DeviceToken struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	UserId     int64     `json:"userId" gorm:"index;not null;comment:'用户Id'"`
+	Platform   string    `json:"platform" gorm:"type:varchar(20);not null;comment:'设备平台（android, ios, harmony, web）'"`
+	Token      string    `json:"token" gorm:"type:varchar(255);uniqueIndex;not null;comment:'推送令牌'"`
+	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+	UpdateTime time.Time `json:"updateTime" gorm:"not null;comment:'最后登记时间'"`
+}
+
+func (DeviceToken) TableName() string {
+	return "device_token"
+}
+
+// PushMessage 发送给单个设备的推送内容
+type PushMessage struct {
+	UserId   int64             `json:"userId"`         // 接收人Id
+	Category string            `json:"category"`       // 推送类别
+	Title    string            `json:"title"`          // 标题
+	Body     string            `json:"body"`           // 正文
+	Data     map[string]string `json:"data,omitempty"` // 客户端跳转所需的附加数据
+}
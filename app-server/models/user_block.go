@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// UserBlock 屏蔽关系，屏蔽后双方不能互加好友、私聊或邀请，也互相看不到对方的评论
+type UserBlock struct {
+	Id            int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	UserId        int64     `json:"user_id" gorm:"not null;uniqueIndex:idx_user_block;comment:'屏蔽者Id'"`
+	BlockedUserId int64     `json:"blocked_user_id" gorm:"not null;uniqueIndex:idx_user_block;index;comment:'被屏蔽者Id'"`
+	CreateTime    time.Time `json:"create_time" gorm:"not null;comment:'屏蔽时间'"`
+}
+
+func (UserBlock) TableName() string {
+	return "user_block"
+}
+
+// BlockedUser 屏蔽列表中的一项
+type BlockedUser struct {
+	UserId     int64     `json:"user_id"`     // 被屏蔽者Id
+	Username   string    `json:"username"`    // 用户名
+	Name       string    `json:"name"`        // 姓名
+	HeadImg    string    `json:"head_img"`    // 头像
+	CreateTime time.Time `json:"create_time"` // 屏蔽时间
+}
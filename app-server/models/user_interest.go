@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// UserInterest 用户的兴趣标签，用于好友推荐及用户搜索
+type UserInterest struct {
+	Id         int64     `json:"id" gorm:"primaryKey;autoIncrement;comment:'记录Id'"`
+	UserId     int64     `json:"userId" gorm:"uniqueIndex:idx_user_interest;not null;comment:'用户Id'"`
+	Tag        string    `json:"tag" gorm:"type:varchar(32);uniqueIndex:idx_user_interest;index;not null;comment:'兴趣标签'"`
+	CreateTime time.Time `json:"createTime" gorm:"not null;comment:'创建时间'"`
+}
+
+func (UserInterest) TableName() string {
+	return "user_interest"
+}
@@ -0,0 +1,27 @@
+package models
+
+// UserSearchFilter 用户搜索条件，零值字段表示不限制该条件
+type UserSearchFilter struct {
+	Keyword    string  // 用户名或姓名中包含的关键字，以关键字开头的排在前面
+	Tag        string  // 兴趣标签
+	ExcludeIds []int64 // 排除的用户Id，如自己及屏蔽关系中的用户
+
+	// 按距离筛选，为 true 时排除未设置位置及隐藏距离的用户，结果按距离从近到远排列。
+	// 先用经纬度范围利用索引预筛选，再按平面近似距离筛选半径内的用户
+	WithinDistance bool
+	Lat            float64 // 中心点纬度
+	Lon            float64 // 中心点经度
+	RadiusDeg      float64 // 半径换算成的纬度差
+	LonScale       float64 // 中心点所在纬度上经度差相对纬度差的距离比例
+	MinLat         float64 // 纬度下限
+	MaxLat         float64 // 纬度上限
+	MinLon         float64 // 经度下限
+	MaxLon         float64 // 经度上限
+}
+
+// UserSearchResult 用户搜索结果中的一项
+type UserSearchResult struct {
+	User       PublicUser `json:"user"`
+	Tags       []string   `json:"tags"`                 // 兴趣标签
+	DistanceKm *int       `json:"distanceKm,omitempty"` // 大致距离（公里，向上取整），任一方未设置位置或对方隐藏距离时为空
+}
@@ -0,0 +1,62 @@
+package models
+
+import "time"
+
+const (
+	WSEventPing            = "ping"              // 心跳
+	WSEventPong            = "pong"              // 心跳回应
+	WSEventChatMessage     = "chat_message"      // 新的私聊消息
+	WSEventChatDeleted     = "chat_deleted"      // 私聊消息被删除
+	WSEventChatDelivered   = "chat_delivered"    // 对方已收到私聊消息
+	WSEventChatRead        = "chat_read"         // 对方已读私聊消息
+	WSEventChatRecalled    = "chat_recalled"     // 私聊消息被发送方撤回，数据为撤回后的消息
+	WSEventChatEdited      = "chat_edited"       // 私聊消息被发送方编辑，数据为编辑后的消息
+	WSEventChatRoomMessage = "chat_room_message" // 新的群消息
+	WSEventFriendRequest   = "friend_request"    // 收到好友申请
+	WSEventFriendResponse  = "friend_response"   // 好友申请被同意或拒绝
+	WSEventFriendCancelled = "friend_cancelled"  // 对方撤回了好友申请
+	WSEventFriendRemoved   = "friend_removed"    // 对方解除了好友关系
+	WSEventBackfillDone    = "backfill_done"     // 断线期间的消息补发完成
+	WSEventPresence        = "presence"          // 好友在线状态变化；客户端发送时用于切换在线或离开
+	WSEventTyping          = "typing"            // 正在输入提示，客户端发送时需指定会话
+	WSEventNotification    = "notification"      // 新的站内通知，数据为通知内容
+)
+
+// WSEvent WebSocket 推送给客户端的事件
+type WSEvent struct {
+	Type string      `json:"type"`
+	Data interface{} `json:"data,omitempty"`
+}
+
+type ChatDeletedEvent struct {
+	Id int64 `json:"id"` // 被删除的消息Id
+}
+
+// ChatReceiptEvent 回执事件，表示 UserId 已收到或已读会话中 Id 不大于 LastId 的消息
+type ChatReceiptEvent struct {
+	UserId int64     `json:"userId"` // 收到或阅读消息的用户Id
+	LastId int64     `json:"lastId"` // 回执覆盖的最后一条消息Id
+	Time   time.Time `json:"time"`   // 送达或阅读时间
+}
+
+type FriendEvent struct {
+	UserId int64 `json:"userId"` // 触发事件的用户Id
+	Status int   `json:"status"` // 当前好友状态（0: 拒绝, 1: 接受, 2: 等待接受）
+}
+
+type BackfillDoneEvent struct {
+	Count   int  `json:"count"`   // 本次补发的消息数量
+	HasMore bool `json:"hasMore"` // 是否还有未补发的消息，需通过聊天记录接口获取
+}
+
+// TypingEvent 正在输入提示。客户端发送时填写 UserId（私聊对象）或 RoomId（群聊），
+// 服务端转发时 UserId 为正在输入的用户
+type TypingEvent struct {
+	UserId int64 `json:"userId"`           // 私聊对象或正在输入的用户Id
+	RoomId int64 `json:"roomId,omitempty"` // 群聊Id，私聊时为0
+}
+
+// PresenceEvent 客户端切换在线状态，Status 为 online 或 away
+type PresenceEvent struct {
+	Status string `json:"status"`
+}
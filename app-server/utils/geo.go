@@ -0,0 +1,43 @@
+package utils
+
+import "math"
+
+const (
+	earthRadiusKm = 6371.0
+	kmPerDegree   = earthRadiusKm * math.Pi / 180 // 经线上每度对应的距离
+)
+
+// HasLocation 判断经纬度是否已设置，未设置的用户经纬度均为0
+func HasLocation(lat, lon float64) bool {
+	return lat != 0 || lon != 0
+}
+
+// DistanceKm 使用 haversine 公式计算两点间的球面距离，单位为公里
+func DistanceKm(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(a))
+}
+
+// KmToLatDegrees 将距离换算为经线上对应的纬度差
+func KmToLatDegrees(km float64) float64 {
+	return km / kmPerDegree
+}
+
+// LonScale 返回某纬度上经度差相对纬度差的距离比例，用于在数据库中按平面近似比较百公里内的距离
+func LonScale(lat float64) float64 {
+	return math.Cos(lat * math.Pi / 180)
+}
+
+// BoundingBox 返回以某点为中心、半径为 radiusKm 的经纬度范围，用于在数据库中预筛选
+func BoundingBox(lat, lon, radiusKm float64) (minLat, maxLat, minLon, maxLon float64) {
+	dLat := radiusKm / kmPerDegree
+	dLon := 180.0
+	if cos := math.Cos(lat * math.Pi / 180); cos > 0.01 {
+		dLon = math.Min(radiusKm/(kmPerDegree*cos), 180)
+	}
+	return lat - dLat, lat + dLat, lon - dLon, lon + dLon
+}
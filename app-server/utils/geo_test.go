@@ -0,0 +1,43 @@
+package utils
+
+import (
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDistanceKm(t *testing.T) {
+	assert.Equal(t, 0.0, DistanceKm(39.9, 116.4, 39.9, 116.4))
+	// 北京到上海约 1067 公里
+	assert.InDelta(t, 1067, DistanceKm(39.9042, 116.4074, 31.2304, 121.4737), 5)
+	// 经度相差1度，在赤道上约 111 公里
+	assert.InDelta(t, 111.2, DistanceKm(0, 0, 0, 1), 0.1)
+}
+
+func TestHasLocation(t *testing.T) {
+	assert.False(t, HasLocation(0, 0))
+	assert.True(t, HasLocation(0, 116.4))
+}
+
+func TestPlanarApproximation(t *testing.T) {
+	// 百公里内平面近似与 haversine 距离基本一致
+	lat, lon := 39.9, 116.4
+	for _, p := range [][2]float64{{40.5, 116.4}, {39.9, 117.3}, {40.3, 117.0}} {
+		dLat := p[0] - lat
+		dLon := (p[1] - lon) * LonScale(lat)
+		planar := math.Sqrt(dLat*dLat+dLon*dLon) / KmToLatDegrees(1)
+		assert.InDelta(t, DistanceKm(lat, lon, p[0], p[1]), planar, 0.5)
+	}
+}
+
+func TestBoundingBox(t *testing.T) {
+	minLat, maxLat, minLon, maxLon := BoundingBox(39.9, 116.4, 10)
+	assert.InDelta(t, 39.81, minLat, 0.01)
+	assert.InDelta(t, 39.99, maxLat, 0.01)
+	assert.Less(t, minLon, 116.4-0.09)
+	assert.Greater(t, maxLon, 116.4+0.09)
+
+	// 框内的点到中心的距离不会被误排除
+	assert.Less(t, DistanceKm(39.9, 116.4, 39.9, maxLon), 10.01)
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	_ "image/png"
+	"io"
+	"strings"
+)
+
+// maxThumbnailSourcePixels 生成缩略图时允许的原图最大像素数（宽×高），防止小文件声明超大尺寸耗尽内存
+const maxThumbnailSourcePixels = 50_000_000
+
+var ErrImageTooLarge = errors.New("image dimensions are too large")
+
+// 支持生成缩略图的图片扩展名
+var thumbnailImageTypes = []string{".png", ".jpg", ".jpeg", ".gif"}
+
+// IsImageType 判断文件扩展名是否为可生成缩略图的图片类型
+func IsImageType(fileType string) bool {
+	for _, t := range thumbnailImageTypes {
+		if strings.EqualFold(fileType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// GenerateThumbnail 读取图片并按最长边不超过 maxSize 等比缩放，返回JPEG编码的缩略图及原图宽高。
+// 解码前先读取图片头中的尺寸，超过 maxThumbnailSourcePixels 时返回 ErrImageTooLarge
+func GenerateThumbnail(r io.Reader, maxSize int) ([]byte, int, int, error) {
+	// 读取图片头时保留已读数据，随后与剩余数据一起完整解码
+	var header bytes.Buffer
+	imageConfig, _, err := image.DecodeConfig(io.TeeReader(r, &header))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	if imageConfig.Width <= 0 || imageConfig.Height <= 0 || int64(imageConfig.Width)*int64(imageConfig.Height) > maxThumbnailSourcePixels {
+		return nil, 0, 0, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(io.MultiReader(&header, r))
+	if err != nil {
+		return nil, 0, 0, err
+	}
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+
+	// 计算缩略图尺寸，小图不放大
+	thumbWidth, thumbHeight := width, height
+	if width > maxSize || height > maxSize {
+		if width >= height {
+			thumbWidth = maxSize
+			thumbHeight = height * maxSize / width
+		} else {
+			thumbHeight = maxSize
+			thumbWidth = width * maxSize / height
+		}
+	}
+	thumbWidth = max(thumbWidth, 1)
+	thumbHeight = max(thumbHeight, 1)
+
+	// 区域平均采样缩放
+	dst := image.NewRGBA(image.Rect(0, 0, thumbWidth, thumbHeight))
+	for y := 0; y < thumbHeight; y++ {
+		sy0 := bounds.Min.Y + y*height/thumbHeight
+		sy1 := max(bounds.Min.Y+(y+1)*height/thumbHeight, sy0+1)
+		for x := 0; x < thumbWidth; x++ {
+			sx0 := bounds.Min.X + x*width/thumbWidth
+			sx1 := max(bounds.Min.X+(x+1)*width/thumbWidth, sx0+1)
+			var sumR, sumG, sumB, sumA, n uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					sumR += uint64(cr)
+					sumG += uint64(cg)
+					sumB += uint64(cb)
+					sumA += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(x, y, color.RGBA64{
+				R: uint16(sumR / n),
+				G: uint16(sumG / n),
+				B: uint16(sumB / n),
+				A: uint16(sumA / n),
+			})
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: 80}); err != nil {
+		return nil, 0, 0, err
+	}
+	return buf.Bytes(), width, height, nil
+}
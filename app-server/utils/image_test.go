@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsImageType(t *testing.T) {
+	assert.True(t, IsImageType(".png"))
+	assert.True(t, IsImageType(".JPG"))
+	assert.False(t, IsImageType(".pdf"))
+	assert.False(t, IsImageType(""))
+}
+
+func TestGenerateThumbnail(t *testing.T) {
+	// 生成一张 400x200 的测试图片
+	src := image.NewRGBA(image.Rect(0, 0, 400, 200))
+	for y := 0; y < 200; y++ {
+		for x := 0; x < 400; x++ {
+			src.Set(x, y, color.RGBA{R: 255, A: 255})
+		}
+	}
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, src))
+
+	data, width, height, err := GenerateThumbnail(&buf, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, 400, width)
+	assert.Equal(t, 200, height)
+
+	thumb, err := jpeg.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, thumb.Bounds().Dx())
+	assert.Equal(t, 50, thumb.Bounds().Dy())
+
+	// 非图片数据
+	_, _, _, err = GenerateThumbnail(bytes.NewReader([]byte("not an image")), 100)
+	assert.Error(t, err)
+}
+
+func TestGenerateThumbnailTooLarge(t *testing.T) {
+	// 只有图片头声明了 100000x100000 的尺寸，不应进入完整解码
+	var buf bytes.Buffer
+	assert.NoError(t, png.Encode(&buf, image.NewGray(image.Rect(0, 0, 1, 1))))
+	data := buf.Bytes()
+	// PNG 的 IHDR 块中宽高位于第16至24字节，修改后重新计算块的校验和
+	binary.BigEndian.PutUint32(data[16:20], 100000)
+	binary.BigEndian.PutUint32(data[20:24], 100000)
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+
+	_, _, _, err := GenerateThumbnail(bytes.NewReader(data), 100)
+	assert.ErrorIs(t, err, ErrImageTooLarge)
+}
@@ -0,0 +1,78 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// 金额统一以最小货币单位（分）的整数存储，避免浮点误差
+const MoneyScale = 100
+
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// ParseMoney 将 "12.34" 形式的十进制金额解析为以分为单位的整数，最多两位小数且不能为负
+func ParseMoney(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	intPart, fracPart, hasFrac := strings.Cut(s, ".")
+	// 整数及小数部分只能由数字组成，不接受正负号
+	if !isASCIIDigits(intPart) || (hasFrac && (!isASCIIDigits(fracPart) || len(fracPart) > 2)) {
+		return 0, ErrInvalidMoney
+	}
+	units, err := strconv.ParseInt(intPart, 10, 64)
+	if err != nil || units > (1<<62)/MoneyScale {
+		return 0, ErrInvalidMoney
+	}
+	var cents int64
+	if hasFrac {
+		for len(fracPart) < 2 {
+			fracPart += "0"
+		}
+		cents, err = strconv.ParseInt(fracPart, 10, 64)
+		if err != nil {
+			return 0, ErrInvalidMoney
+		}
+	}
+	return units*MoneyScale + cents, nil
+}
+
+// isASCIIDigits 判断字符串非空且只包含 0-9
+func isASCIIDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// FormatMoney 将以分为单位的整数格式化为保留两位小数的字符串
+func FormatMoney(amount int64) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+		amount = -amount
+	}
+	return sign + strconv.FormatInt(amount/MoneyScale, 10) + "." +
+		strconv.FormatInt(amount%MoneyScale/10, 10) + strconv.FormatInt(amount%10, 10)
+}
+
+// SplitAmount 将金额平均分成 n 份，除不尽的余数按顺序每份多分1分，保证总和不变
+func SplitAmount(amount int64, n int) []int64 {
+	if n <= 0 {
+		return nil
+	}
+	shares := make([]int64, n)
+	base := amount / int64(n)
+	remainder := amount % int64(n)
+	for i := range shares {
+		shares[i] = base
+		if int64(i) < remainder {
+			shares[i]++
+		}
+	}
+	return shares
+}
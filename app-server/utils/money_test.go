@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMoney(t *testing.T) {
+	cases := map[string]int64{
+		"12":     1200,
+		"12.3":   1230,
+		"12.34":  1234,
+		"0.01":   1,
+		" 7.05 ": 705,
+	}
+	for input, expected := range cases {
+		amount, err := ParseMoney(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, amount, input)
+	}
+
+	// 非法金额
+	invalid := []string{
+		"", "-1", "+1", "1.", ".5", "1.234", "abc", "1.x", "1e3",
+		"12.+3", "12.-3", "12.+", "12.-", "+12.34", "-12.34", "12.3 4", "1 2.34", "１２.34", "12.٣",
+	}
+	for _, input := range invalid {
+		_, err := ParseMoney(input)
+		assert.ErrorIs(t, err, ErrInvalidMoney, input)
+	}
+}
+
+func TestFormatMoney(t *testing.T) {
+	assert.Equal(t, "12.34", FormatMoney(1234))
+	assert.Equal(t, "0.05", FormatMoney(5))
+	assert.Equal(t, "100.00", FormatMoney(10000))
+	assert.Equal(t, "-3.10", FormatMoney(-310))
+}
+
+func TestSplitAmount(t *testing.T) {
+	assert.Equal(t, []int64{334, 333, 333}, SplitAmount(1000, 3))
+	assert.Equal(t, []int64{500, 500}, SplitAmount(1000, 2))
+	assert.Nil(t, SplitAmount(1000, 0))
+
+	var total int64
+	for _, share := range SplitAmount(1001, 7) {
+		total += share
+	}
+	assert.Equal(t, int64(1001), total)
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	DefaultPage     = 1
+	DefaultPageSize = 10
+	MaxPageSize     = 100
+)
+
+// ParsePagination 从查询参数 page、pageSize 中解析分页信息，默认第1页、每页10条
+func ParsePagination(c *gin.Context) (int, int, error) {
+	page, err := StringToInt(c.DefaultQuery("page", "1"))
+	if err != nil || page < 1 {
+		return 0, 0, errors.New("invalid page number")
+	}
+	pageSize, err := StringToInt(c.DefaultQuery("pageSize", "10"))
+	if err != nil || pageSize < 1 {
+		return 0, 0, errors.New("invalid page size")
+	}
+	if pageSize > MaxPageSize {
+		pageSize = MaxPageSize
+	}
+	return page, pageSize, nil
+}
@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newQueryContext(rawQuery string) *gin.Context {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/?"+rawQuery, nil)
+	return c
+}
+
+func TestParsePagination(t *testing.T) {
+	// 默认值
+	page, pageSize, err := ParsePagination(newQueryContext(""))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultPage, page)
+	assert.Equal(t, DefaultPageSize, pageSize)
+
+	// 正常解析
+	page, pageSize, err = ParsePagination(newQueryContext("page=3&pageSize=20"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, page)
+	assert.Equal(t, 20, pageSize)
+
+	// 超过上限时截断
+	_, pageSize, err = ParsePagination(newQueryContext("pageSize=1000"))
+	assert.NoError(t, err)
+	assert.Equal(t, MaxPageSize, pageSize)
+
+	// 非法参数
+	_, _, err = ParsePagination(newQueryContext("page=0"))
+	assert.Error(t, err)
+	_, _, err = ParsePagination(newQueryContext("pageSize=abc"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,99 @@
+package utils
+
+import (
+	"sync"
+	"time"
+
+	"hobbyhub-server/models"
+)
+
+const (
+	// PresenceIdleTimeout 超过该时长无操作时，有连接的用户显示为离开，无连接的用户显示为离线
+	PresenceIdleTimeout = 5 * time.Minute
+	// presencePersistInterval 在线期间写入最近在线时间的最小间隔
+	presencePersistInterval = time.Minute
+)
+
+// PresenceTracker 在内存中记录用户最近的活动时间及客户端上报的离开状态
+type PresenceTracker struct {
+	mu        sync.Mutex
+	active    map[int64]time.Time
+	persisted map[int64]time.Time
+	away      map[int64]bool
+	offline   map[int64]bool // 最后一个连接断开后尚无新活动
+}
+
+func NewPresenceTracker() *PresenceTracker {
+	return &PresenceTracker{
+		active:    make(map[int64]time.Time),
+		persisted: make(map[int64]time.Time),
+		away:      make(map[int64]bool),
+		offline:   make(map[int64]bool),
+	}
+}
+
+// UserPresence 全局的在线状态记录
+var UserPresence = NewPresenceTracker()
+
+// Touch 记录用户的一次活动，返回是否需要将最近在线时间写入数据库
+func (p *PresenceTracker) Touch(userId int64, now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active[userId] = now
+	delete(p.offline, userId)
+	if now.Sub(p.persisted[userId]) < presencePersistInterval {
+		return false
+	}
+	p.persisted[userId] = now
+	return true
+}
+
+// SetAway 设置客户端上报的离开状态，返回状态是否发生变化
+func (p *PresenceTracker) SetAway(userId int64, away bool) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.away[userId] == away {
+		return false
+	}
+	if away {
+		p.away[userId] = true
+	} else {
+		delete(p.away, userId)
+	}
+	return true
+}
+
+// Disconnect 在用户最后一个连接断开时调用，此后直到下一次活动前都显示为离线
+func (p *PresenceTracker) Disconnect(userId int64, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active[userId] = now
+	p.persisted[userId] = now
+	p.offline[userId] = true
+	delete(p.away, userId)
+}
+
+// LastActive 获取用户在本进程内最近的活动时间
+func (p *PresenceTracker) LastActive(userId int64) (time.Time, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	lastActive, ok := p.active[userId]
+	return lastActive, ok
+}
+
+// Status 根据是否有在线连接及最近活动时间计算在线状态
+func (p *PresenceTracker) Status(userId int64, connected bool, now time.Time) string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	idle := now.Sub(p.active[userId]) >= PresenceIdleTimeout
+	if connected {
+		if p.away[userId] || idle {
+			return models.PresenceAway
+		}
+		return models.PresenceOnline
+	}
+	if idle || p.offline[userId] {
+		return models.PresenceOffline
+	}
+	return models.PresenceOnline
+}
@@ -0,0 +1,53 @@
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPresenceTracker(t *testing.T) {
+	tracker := NewPresenceTracker()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.Local)
+
+	// 从未活动的用户为离线
+	assert.Equal(t, models.PresenceOffline, tracker.Status(1, false, now))
+	_, ok := tracker.LastActive(1)
+	assert.False(t, ok)
+
+	// 首次活动需要写入，间隔内的后续活动不需要
+	assert.True(t, tracker.Touch(1, now))
+	assert.False(t, tracker.Touch(1, now.Add(30*time.Second)))
+	assert.True(t, tracker.Touch(1, now.Add(90*time.Second)))
+	lastActive, ok := tracker.LastActive(1)
+	assert.True(t, ok)
+	assert.Equal(t, now.Add(90*time.Second), lastActive)
+
+	// 仅通过接口请求活动的用户空闲后直接离线
+	assert.Equal(t, models.PresenceOnline, tracker.Status(1, false, now.Add(2*time.Minute)))
+	assert.Equal(t, models.PresenceOffline, tracker.Status(1, false, now.Add(10*time.Minute)))
+
+	// 保持连接的用户空闲后显示为离开
+	assert.Equal(t, models.PresenceOnline, tracker.Status(1, true, now.Add(2*time.Minute)))
+	assert.Equal(t, models.PresenceAway, tracker.Status(1, true, now.Add(10*time.Minute)))
+
+	// 客户端上报离开
+	assert.True(t, tracker.SetAway(1, true))
+	assert.False(t, tracker.SetAway(1, true))
+	assert.Equal(t, models.PresenceAway, tracker.Status(1, true, now.Add(2*time.Minute)))
+	assert.True(t, tracker.SetAway(1, false))
+	assert.Equal(t, models.PresenceOnline, tracker.Status(1, true, now.Add(2*time.Minute)))
+
+	// 最后一个连接断开后立即离线，再次请求接口后恢复在线
+	tracker.SetAway(1, true)
+	tracker.Disconnect(1, now.Add(3*time.Minute))
+	assert.Equal(t, models.PresenceOffline, tracker.Status(1, false, now.Add(3*time.Minute)))
+	lastActive, _ = tracker.LastActive(1)
+	assert.Equal(t, now.Add(3*time.Minute), lastActive)
+	assert.False(t, tracker.Touch(1, now.Add(3*time.Minute+time.Second)))
+	assert.Equal(t, models.PresenceOnline, tracker.Status(1, false, now.Add(4*time.Minute)))
+	assert.Equal(t, models.PresenceOnline, tracker.Status(1, true, now.Add(4*time.Minute)))
+}
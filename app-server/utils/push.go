@@ -0,0 +1,219 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"hobbyhub-server/models"
+)
+
+const (
+	pushQueueSize      = 1024 // 待发送推送的缓冲数量，写满时丢弃新的推送
+	pushWebhookTimeout = 10 * time.Second
+)
+
+// ErrPushTokenInvalid 设备令牌已失效，应从登记表中删除，不再重试
+var ErrPushTokenInvalid = errors.New("push token is invalid")
+
+// PushPermanentError 推送服务明确拒绝的请求，重试也不会成功
+type PushPermanentError struct {
+	StatusCode int
+}
+
+func (e *PushPermanentError) Error() string {
+	return fmt.Sprintf("push rejected with status %d", e.StatusCode)
+}
+
+// PushProvider 推送服务，Send 返回的错误除 ErrPushTokenInvalid 和 PushPermanentError 外都会重试
+type PushProvider interface {
+	Name() string
+	Send(device models.DeviceToken, message models.PushMessage) error
+}
+
+// NoopPushProvider 不发送任何推送
+type NoopPushProvider struct{}
+
+func (NoopPushProvider) Name() string { return "none" }
+
+func (NoopPushProvider) Send(models.DeviceToken, models.PushMessage) error { return nil }
+
+// LogPushProvider 只把推送内容写入日志，用于开发调试
+type LogPushProvider struct{}
+
+func (LogPushProvider) Name() string { return "log" }
+
+func (LogPushProvider) Send(device models.DeviceToken, message models.PushMessage) error {
+	log.Printf("推送[%s] 用户 %d 设备 %s(%s): %s - %s", message.Category, message.UserId, device.Token, device.Platform, message.Title, message.Body)
+	return nil
+}
+
+// webhookPushPayload webhook 推送的请求体
+type webhookPushPayload struct {
+	Token    string            `json:"token"`
+	Platform string            `json:"platform"`
+	UserId   int64             `json:"userId"`
+	Category string            `json:"category"`
+	Title    string            `json:"title"`
+	Body     string            `json:"body"`
+	Data     map[string]string `json:"data,omitempty"`
+}
+
+// WebhookPushProvider 以 JSON POST 的方式把推送交给外部推送网关。
+// 网关返回 404 或 410 表示令牌失效，其他 4xx 不重试，5xx 及网络错误会重试
+type WebhookPushProvider struct {
+	URL    string
+	Secret string // 设置后以 HMAC-SHA256 对请求体签名，放在 X-Push-Signature 头中
+	Client *http.Client
+}
+
+func NewWebhookPushProvider(url, secret string) *WebhookPushProvider {
+	return &WebhookPushProvider{URL: url, Secret: secret, Client: &http.Client{Timeout: pushWebhookTimeout}}
+}
+
+func (p *WebhookPushProvider) Name() string { return "webhook" }
+
+func (p *WebhookPushProvider) Send(device models.DeviceToken, message models.PushMessage) error {
+	body, err := json.Marshal(webhookPushPayload{
+		Token:    device.Token,
+		Platform: device.Platform,
+		UserId:   message.UserId,
+		Category: message.Category,
+		Title:    message.Title,
+		Body:     message.Body,
+		Data:     message.Data,
+	})
+	if err != nil {
+		return &PushPermanentError{}
+	}
+	req, err := http.NewRequest(http.MethodPost, p.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.Secret != "" {
+		req.Header.Set("X-Push-Signature", SignPushBody(p.Secret, body))
+	}
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrPushTokenInvalid
+	case resp.StatusCode >= 400 && resp.StatusCode < 500 && resp.StatusCode != http.StatusTooManyRequests:
+		return &PushPermanentError{StatusCode: resp.StatusCode}
+	}
+	return fmt.Errorf("push webhook returned status %d", resp.StatusCode)
+}
+
+// SignPushBody 计算 webhook 请求体的签名，格式为 sha256=<hex>
+func SignPushBody(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+type pushJob struct {
+	device  models.DeviceToken
+	message models.PushMessage
+}
+
+// PushDispatcher 异步发送推送，失败时按指数退避重试
+type PushDispatcher struct {
+	provider   PushProvider
+	queue      chan pushJob
+	maxRetries int
+	backoff    time.Duration
+	wg         sync.WaitGroup
+
+	// OnInvalidToken 推送服务报告令牌失效时调用，用于删除该令牌
+	OnInvalidToken func(device models.DeviceToken)
+	// sleep 重试前的等待，测试中可替换
+	sleep func(time.Duration)
+}
+
+// NewPushDispatcher 创建推送分发器并启动 workers 个发送协程
+func NewPushDispatcher(provider PushProvider, workers, maxRetries int, backoff time.Duration) *PushDispatcher {
+	d := &PushDispatcher{
+		provider:   provider,
+		queue:      make(chan pushJob, pushQueueSize),
+		maxRetries: max(maxRetries, 0),
+		backoff:    backoff,
+		sleep:      time.Sleep,
+	}
+	for i := 0; i < max(workers, 1); i++ {
+		d.wg.Add(1)
+		go d.run()
+	}
+	return d
+}
+
+// Push 全局的推送分发器，启动时由配置替换
+var Push = NewPushDispatcher(NoopPushProvider{}, 1, 0, 0)
+
+// Provider 返回当前使用的推送服务
+func (d *PushDispatcher) Provider() PushProvider {
+	return d.provider
+}
+
+// Dispatch 把推送加入发送队列，队列已满时丢弃并返回 false
+func (d *PushDispatcher) Dispatch(device models.DeviceToken, message models.PushMessage) bool {
+	select {
+	case d.queue <- pushJob{device: device, message: message}:
+		return true
+	default:
+		log.Printf("推送队列已满，丢弃发送给用户 %d 的推送", message.UserId)
+		return false
+	}
+}
+
+// Close 停止接收新的推送，并等待队列中的推送发送完成
+func (d *PushDispatcher) Close() {
+	close(d.queue)
+	d.wg.Wait()
+}
+
+func (d *PushDispatcher) run() {
+	defer d.wg.Done()
+	for job := range d.queue {
+		d.deliver(job)
+	}
+}
+
+// deliver 发送单条推送，可重试的错误最多重试 maxRetries 次，每次等待时间翻倍
+func (d *PushDispatcher) deliver(job pushJob) {
+	wait := d.backoff
+	for attempt := 0; ; attempt++ {
+		err := d.provider.Send(job.device, job.message)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, ErrPushTokenInvalid) {
+			if d.OnInvalidToken != nil {
+				d.OnInvalidToken(job.device)
+			}
+			return
+		}
+		var permanent *PushPermanentError
+		if errors.As(err, &permanent) || attempt >= d.maxRetries {
+			log.Printf("向用户 %d 的设备 %d 推送失败: %v", job.message.UserId, job.device.Id, err)
+			return
+		}
+		d.sleep(wait)
+		wait *= 2
+	}
+}
@@ -0,0 +1,119 @@
+package utils
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"hobbyhub-server/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakePushProvider 按顺序返回预设的错误，并记录调用次数
+type fakePushProvider struct {
+	mu     sync.Mutex
+	errs   []error
+	called int
+}
+
+func (p *fakePushProvider) Name() string { return "fake" }
+
+func (p *fakePushProvider) Send(models.DeviceToken, models.PushMessage) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.called++
+	if len(p.errs) == 0 {
+		return nil
+	}
+	err := p.errs[0]
+	p.errs = p.errs[1:]
+	return err
+}
+
+func newTestDispatcher(provider PushProvider, maxRetries int) (*PushDispatcher, *[]time.Duration) {
+	d := NewPushDispatcher(provider, 1, maxRetries, 100*time.Millisecond)
+	var waits []time.Duration
+	d.sleep = func(wait time.Duration) { waits = append(waits, wait) }
+	return d, &waits
+}
+
+func TestPushDispatcherRetry(t *testing.T) {
+	retryable := errors.New("connection refused")
+
+	// 可重试的错误按指数退避重试，直到成功
+	provider := &fakePushProvider{errs: []error{retryable, retryable}}
+	d, waits := newTestDispatcher(provider, 3)
+	assert.True(t, d.Dispatch(models.DeviceToken{Id: 1}, models.PushMessage{UserId: 1}))
+	d.Close()
+	assert.Equal(t, 3, provider.called)
+	assert.Equal(t, []time.Duration{100 * time.Millisecond, 200 * time.Millisecond}, *waits)
+
+	// 超过最大重试次数后放弃
+	provider = &fakePushProvider{errs: []error{retryable, retryable, retryable, retryable}}
+	d, _ = newTestDispatcher(provider, 2)
+	d.Dispatch(models.DeviceToken{Id: 1}, models.PushMessage{UserId: 1})
+	d.Close()
+	assert.Equal(t, 3, provider.called)
+
+	// 被明确拒绝的推送不重试
+	provider = &fakePushProvider{errs: []error{&PushPermanentError{StatusCode: 400}}}
+	d, _ = newTestDispatcher(provider, 3)
+	d.Dispatch(models.DeviceToken{Id: 1}, models.PushMessage{UserId: 1})
+	d.Close()
+	assert.Equal(t, 1, provider.called)
+}
+
+func TestPushDispatcherInvalidToken(t *testing.T) {
+	provider := &fakePushProvider{errs: []error{ErrPushTokenInvalid}}
+	d, _ := newTestDispatcher(provider, 3)
+	var invalid []models.DeviceToken
+	d.OnInvalidToken = func(device models.DeviceToken) { invalid = append(invalid, device) }
+	d.Dispatch(models.DeviceToken{Id: 5, Token: "abc"}, models.PushMessage{UserId: 1})
+	d.Close()
+	assert.Equal(t, 1, provider.called)
+	assert.Equal(t, []models.DeviceToken{{Id: 5, Token: "abc"}}, invalid)
+}
+
+func TestWebhookPushProvider(t *testing.T) {
+	var status int
+	var payload webhookPushPayload
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		json.Unmarshal(body, &payload)
+		signature = r.Header.Get("X-Push-Signature")
+		assert.Equal(t, SignPushBody("secret", body), signature)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	provider := NewWebhookPushProvider(server.URL, "secret")
+	device := models.DeviceToken{Token: "token-1", Platform: "android"}
+	message := models.PushMessage{UserId: 2, Category: models.PushCategoryChat, Title: "小明", Body: "你好", Data: map[string]string{"chatId": "3"}}
+
+	status = http.StatusOK
+	assert.NoError(t, provider.Send(device, message))
+	assert.Equal(t, webhookPushPayload{
+		Token: "token-1", Platform: "android", UserId: 2, Category: "chat", Title: "小明", Body: "你好",
+		Data: map[string]string{"chatId": "3"},
+	}, payload)
+	assert.Contains(t, signature, "sha256=")
+
+	status = http.StatusGone
+	assert.ErrorIs(t, provider.Send(device, message), ErrPushTokenInvalid)
+
+	status = http.StatusBadRequest
+	var permanent *PushPermanentError
+	assert.ErrorAs(t, provider.Send(device, message), &permanent)
+
+	status = http.StatusServiceUnavailable
+	err := provider.Send(device, message)
+	assert.Error(t, err)
+	assert.False(t, errors.As(err, &permanent))
+}
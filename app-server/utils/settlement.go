@@ -0,0 +1,59 @@
+package utils
+
+import "sort"
+
+// Transfer 表示一笔结算转账，金额以分为单位
+type Transfer struct {
+	FromUserId int64 `json:"fromUserId"`
+	ToUserId   int64 `json:"toUserId"`
+	Amount     int64 `json:"amount"`
+}
+
+type balanceEntry struct {
+	userId int64
+	amount int64
+}
+
+// SimplifyDebts 根据每个用户的净余额（正数为应收，负数为应付）计算结算转账，
+// 每次让欠款最多的人向应收最多的人转账，转账笔数不超过非零余额人数减一
+func SimplifyDebts(balances map[int64]int64) []Transfer {
+	var creditors, debtors []balanceEntry
+	for userId, amount := range balances {
+		if amount > 0 {
+			creditors = append(creditors, balanceEntry{userId, amount})
+		} else if amount < 0 {
+			debtors = append(debtors, balanceEntry{userId, -amount})
+		}
+	}
+	// 按金额降序、用户Id升序排序，保证结果稳定
+	byAmount := func(entries []balanceEntry) func(i, j int) bool {
+		return func(i, j int) bool {
+			if entries[i].amount != entries[j].amount {
+				return entries[i].amount > entries[j].amount
+			}
+			return entries[i].userId < entries[j].userId
+		}
+	}
+
+	var transfers []Transfer
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sort.Slice(creditors, byAmount(creditors))
+		sort.Slice(debtors, byAmount(debtors))
+
+		amount := min(creditors[0].amount, debtors[0].amount)
+		transfers = append(transfers, Transfer{
+			FromUserId: debtors[0].userId,
+			ToUserId:   creditors[0].userId,
+			Amount:     amount,
+		})
+		creditors[0].amount -= amount
+		debtors[0].amount -= amount
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+	}
+	return transfers
+}
@@ -0,0 +1,36 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSimplifyDebts(t *testing.T) {
+	// 1 垫付了全部费用，2、3 各欠一份
+	transfers := SimplifyDebts(map[int64]int64{1: 2000, 2: -1000, 3: -1000})
+	assert.Equal(t, []Transfer{
+		{FromUserId: 2, ToUserId: 1, Amount: 1000},
+		{FromUserId: 3, ToUserId: 1, Amount: 1000},
+	}, transfers)
+
+	// 链式欠款会被合并：2 欠 1，3 欠 2，结果只需 3 直接付给 1
+	transfers = SimplifyDebts(map[int64]int64{1: 500, 2: 0, 3: -500})
+	assert.Equal(t, []Transfer{{FromUserId: 3, ToUserId: 1, Amount: 500}}, transfers)
+
+	// 转账后所有人余额清零
+	balances := map[int64]int64{1: 1234, 2: -333, 3: -567, 4: 100, 5: -434}
+	transfers = SimplifyDebts(balances)
+	assert.LessOrEqual(t, len(transfers), len(balances)-1)
+	for _, transfer := range transfers {
+		assert.Greater(t, transfer.Amount, int64(0))
+		balances[transfer.FromUserId] += transfer.Amount
+		balances[transfer.ToUserId] -= transfer.Amount
+	}
+	for _, amount := range balances {
+		assert.Equal(t, int64(0), amount)
+	}
+
+	// 已结清时无需转账
+	assert.Empty(t, SimplifyDebts(map[int64]int64{1: 0, 2: 0}))
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"html"
+	"strings"
+	"unicode"
+)
+
+const (
+	SnippetHighlightStart = "<em>"  // 高亮开始标记
+	SnippetHighlightEnd   = "</em>" // 高亮结束标记
+	snippetEllipsis       = "…"
+)
+
+// HighlightSnippet 截取关键词首次出现位置前后各 radius 个字符作为片段，片段中的关键词以 <em></em> 包裹，
+// 匹配不区分大小写。内容中的 HTML 字符会被转义；未找到关键词时返回内容开头的片段
+func HighlightSnippet(content, keyword string, radius int) string {
+	text := []rune(content)
+	needle := []rune(keyword)
+
+	first := -1
+	if len(needle) > 0 {
+		first = indexRunesFold(text, needle, 0)
+	}
+	if first < 0 {
+		if len(text) <= 2*radius {
+			return html.EscapeString(content)
+		}
+		return html.EscapeString(string(text[:2*radius])) + snippetEllipsis
+	}
+
+	start := max(0, first-radius)
+	end := min(len(text), first+len(needle)+radius)
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(snippetEllipsis)
+	}
+	pos := start
+	for pos < end {
+		match := indexRunesFold(text[:end], needle, pos)
+		if match < 0 {
+			break
+		}
+		b.WriteString(html.EscapeString(string(text[pos:match])))
+		b.WriteString(SnippetHighlightStart)
+		b.WriteString(html.EscapeString(string(text[match : match+len(needle)])))
+		b.WriteString(SnippetHighlightEnd)
+		pos = match + len(needle)
+	}
+	if pos < end {
+		b.WriteString(html.EscapeString(string(text[pos:end])))
+	}
+	if end < len(text) {
+		b.WriteString(snippetEllipsis)
+	}
+	return b.String()
+}
+
+// indexRunesFold 从 from 开始查找 needle 在 text 中首次出现的位置，不区分大小写
+func indexRunesFold(text, needle []rune, from int) int {
+	for i := from; i+len(needle) <= len(text); i++ {
+		matched := true
+		for j, r := range needle {
+			if unicode.ToLower(text[i+j]) != unicode.ToLower(r) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestHighlightSnippet(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		keyword  string
+		radius   int
+		expected string
+	}{
+		{"短内容", "我家在人民路100号", "人民路", 10, "我家在<em>人民路</em>100号"},
+		{"截断前后", "上次说的地址是人民路100号三单元，记得带伞", "人民路", 3, "…地址是<em>人民路</em>100…"},
+		{"多次命中", "abc ABC abc", "abc", 20, "<em>abc</em> <em>ABC</em> <em>abc</em>"},
+		{"转义HTML", "<b>hello</b>", "hello", 5, "&lt;b&gt;<em>hello</em>&lt;/b&gt;"},
+		{"未命中", "今天天气很好我们去爬山吧", "咖啡", 3, "今天天气很好…"},
+	}
+
+	for _, test := range tests {
+		result := HighlightSnippet(test.content, test.keyword, test.radius)
+		if result != test.expected {
+			t.Errorf("%s: HighlightSnippet(%q, %q, %d) = %q; want %q",
+				test.name, test.content, test.keyword, test.radius, result, test.expected)
+		}
+	}
+}
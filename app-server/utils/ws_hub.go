@@ -0,0 +1,93 @@
+package utils
+
+import (
+	"encoding/json"
+	"log"
+	"sync"
+
+	"hobbyhub-server/models"
+)
+
+// wsSendBufferSize 每个连接待发送消息的缓冲数量，写满的连接会被断开
+const wsSendBufferSize = 64
+
+// WSClient 一个已连接的设备
+type WSClient struct {
+	UserId int64
+	Send   chan []byte // 待写入连接的消息，被断开时关闭
+}
+
+// WSHub 管理所有在线连接，同一用户可以有多个设备同时在线
+type WSHub struct {
+	mu      sync.RWMutex
+	clients map[int64]map[*WSClient]struct{}
+}
+
+func NewWSHub() *WSHub {
+	return &WSHub{clients: make(map[int64]map[*WSClient]struct{})}
+}
+
+// ChatHub 全局的聊天连接中心
+var ChatHub = NewWSHub()
+
+// Register 为用户登记一个新连接
+func (h *WSHub) Register(userId int64) *WSClient {
+	client := &WSClient{UserId: userId, Send: make(chan []byte, wsSendBufferSize)}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.clients[userId] == nil {
+		h.clients[userId] = make(map[*WSClient]struct{})
+	}
+	h.clients[userId][client] = struct{}{}
+	return client
+}
+
+// Unregister 移除连接并关闭其发送队列，可重复调用
+func (h *WSHub) Unregister(client *WSClient) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	devices, ok := h.clients[client.UserId]
+	if !ok {
+		return
+	}
+	if _, ok := devices[client]; !ok {
+		return
+	}
+	delete(devices, client)
+	close(client.Send)
+	if len(devices) == 0 {
+		delete(h.clients, client.UserId)
+	}
+}
+
+// Publish 向用户的所有在线设备推送事件，用户不在线时直接忽略
+func (h *WSHub) Publish(userId int64, event models.WSEvent) {
+	data, err := json.Marshal(event)
+	if err != nil {
+		log.Printf("序列化推送事件失败: %v", err)
+		return
+	}
+
+	var slowClients []*WSClient
+	h.mu.RLock()
+	for client := range h.clients[userId] {
+		select {
+		case client.Send <- data:
+		default:
+			slowClients = append(slowClients, client)
+		}
+	}
+	h.mu.RUnlock()
+
+	// 断开来不及消费的连接，客户端重连后可按最后消息Id补发
+	for _, client := range slowClients {
+		h.Unregister(client)
+	}
+}
+
+// DeviceCount 获取用户当前在线的设备数量
+func (h *WSHub) DeviceCount(userId int64) int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	return len(h.clients[userId])
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+
+	"hobbyhub-server/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWSHubPublish(t *testing.T) {
+	hub := NewWSHub()
+
+	// 同一用户的多个设备都能收到推送
+	phone := hub.Register(1)
+	laptop := hub.Register(1)
+	other := hub.Register(2)
+	assert.Equal(t, 2, hub.DeviceCount(1))
+
+	hub.Publish(1, models.WSEvent{Type: models.WSEventChatDeleted, Data: models.ChatDeletedEvent{Id: 5}})
+	for _, client := range []*WSClient{phone, laptop} {
+		var event map[string]interface{}
+		assert.NoError(t, json.Unmarshal(<-client.Send, &event))
+		assert.Equal(t, models.WSEventChatDeleted, event["type"])
+		assert.Equal(t, float64(5), event["data"].(map[string]interface{})["id"])
+	}
+	assert.Len(t, other.Send, 0)
+
+	// 不在线的用户直接忽略
+	hub.Publish(3, models.WSEvent{Type: models.WSEventPing})
+
+	// 注销后关闭发送队列，重复注销不会出错
+	hub.Unregister(phone)
+	hub.Unregister(phone)
+	_, ok := <-phone.Send
+	assert.False(t, ok)
+	assert.Equal(t, 1, hub.DeviceCount(1))
+
+	hub.Unregister(laptop)
+	assert.Equal(t, 0, hub.DeviceCount(1))
+}
+
+func TestWSHubDropsSlowClient(t *testing.T) {
+	hub := NewWSHub()
+	client := hub.Register(1)
+
+	// 发送队列写满后断开该连接
+	for i := 0; i <= wsSendBufferSize; i++ {
+		hub.Publish(1, models.WSEvent{Type: models.WSEventPing})
+	}
+	assert.Equal(t, 0, hub.DeviceCount(1))
+
+	count := 0
+	for range client.Send {
+		count++
+	}
+	assert.Equal(t, wsSendBufferSize, count)
+}